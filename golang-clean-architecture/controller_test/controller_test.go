@@ -6,18 +6,19 @@ import (
 	"errors"
 	"fmt"
 	"golang-clean-architecture/delivery/controllers"
+	"golang-clean-architecture/delivery/dto"
 	"golang-clean-architecture/domain"
 	"golang-clean-architecture/domain/mocks"
 	"golang-clean-architecture/infrastructure"
 	"net/http"
 	"net/http/httptest"
-	"os"
 	"testing"
 	"time"
 
-	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type ControllerTestSuite struct {
@@ -26,21 +27,41 @@ type ControllerTestSuite struct {
 	userController		*controllers.UserController
 	taskController		*controllers.TaskController
 	mockUserUseCase 	*mocks.UserUseCase
+	mockVerificationUseCase	*mocks.EmailVerificationUseCase
+	mockTwoFactorUseCase	*mocks.TwoFactorUseCase
 	mockTaskUseCase		*mocks.TaskUseCase
+	webhookController	*controllers.WebhookController
+	mockWebhookUseCase	*mocks.WebhookUseCase
+	passwordController	*controllers.PasswordController
+	mockPasswordResetUseCase	*mocks.PasswordResetUseCase
 	TaskGroup			[]*domain.Task
 	SingleTask			domain.Task
+	tokens				domain.TokenService
 }
 
 func (suite *ControllerTestSuite) SetupTest() {
 	suite.router = gin.Default()
+	suite.tokens = newTestTokenService(suite.T())
 	suite.mockUserUseCase = new(mocks.UserUseCase)
 	suite.mockTaskUseCase = new(mocks.TaskUseCase)
+	suite.mockVerificationUseCase = new(mocks.EmailVerificationUseCase)
+	suite.mockTwoFactorUseCase = new(mocks.TwoFactorUseCase)
 	suite.userController = &controllers.UserController{
 		UserUseCase : suite.mockUserUseCase,
+		VerificationUseCase : suite.mockVerificationUseCase,
+		TwoFactorUseCase : suite.mockTwoFactorUseCase,
 	}
 	suite.taskController = &controllers.TaskController {
 		TaskUseCase : suite.mockTaskUseCase,
 	}
+	suite.mockWebhookUseCase = new(mocks.WebhookUseCase)
+	suite.webhookController = &controllers.WebhookController{
+		WebhookUseCase : suite.mockWebhookUseCase,
+	}
+	suite.mockPasswordResetUseCase = new(mocks.PasswordResetUseCase)
+	suite.passwordController = &controllers.PasswordController{
+		PasswordResetUseCase : suite.mockPasswordResetUseCase,
+	}
 
 	suite.TaskGroup = []*domain.Task{
 		{
@@ -60,29 +81,42 @@ func (suite *ControllerTestSuite) SetupTest() {
 	suite.SingleTask = domain.Task{Title : "Title 1", Description : "this is title 1",Status : "pending",}
 	suite.router.POST("/register", suite.userController.Register())
 	suite.router.POST("/login", suite.userController.Login())
-	suite.router.PUT("/promote/:id", infrastructure.AuthMiddleWare(), suite.userController.PromoteUser())
-	suite.router.GET("/tasks", infrastructure.AuthMiddleWare(), suite.taskController.GetTasks())
-	suite.router.POST("/tasks", infrastructure.AuthMiddleWare(), suite.taskController.PostTask())
-	suite.router.DELETE("/tasks/:id", infrastructure.AuthMiddleWare(), suite.taskController.DeleteTask())
-	suite.router.PUT("/tasks/:id", infrastructure.AuthMiddleWare(), suite.taskController.UpdateTask())
-	suite.router.GET("/tasks/:id", infrastructure.AuthMiddleWare(), suite.taskController.GetTask())
+	suite.router.POST("/login/2fa", suite.userController.LoginTwoFactor())
+	suite.router.GET("/verify", suite.userController.VerifyEmail())
+	suite.router.GET("/me", infrastructure.AuthMiddleWare(suite.tokens), suite.userController.Me())
+	suite.router.PUT("/me/password", infrastructure.AuthMiddleWare(suite.tokens), suite.userController.ChangePassword())
+	suite.router.POST("/me/2fa/confirm", infrastructure.AuthMiddleWare(suite.tokens), suite.userController.ConfirmTwoFactor())
+	suite.router.PUT("/promote/:id", infrastructure.AuthMiddleWare(suite.tokens), suite.userController.PromoteUser())
+	suite.router.PUT("/users/:id/unlock", infrastructure.AuthMiddleWare(suite.tokens), suite.userController.UnlockAccount())
+	suite.router.GET("/tasks", infrastructure.AuthMiddleWare(suite.tokens), suite.taskController.GetTasks())
+	suite.router.GET("/tasks/search", infrastructure.AuthMiddleWare(suite.tokens), suite.taskController.SearchTasks())
+	suite.router.POST("/tasks", infrastructure.AuthMiddleWare(suite.tokens), suite.taskController.PostTask())
+	suite.router.DELETE("/tasks/:id", infrastructure.AuthMiddleWare(suite.tokens), suite.taskController.DeleteTask())
+	suite.router.PUT("/tasks/:id", infrastructure.AuthMiddleWare(suite.tokens), suite.taskController.UpdateTask())
+	suite.router.GET("/tasks/:id", infrastructure.AuthMiddleWare(suite.tokens), suite.taskController.GetTask())
+	suite.router.PUT("/tasks/:id/recurrence", infrastructure.AuthMiddleWare(suite.tokens), suite.taskController.UpdateRecurrence())
+	suite.router.DELETE("/tasks/:id/recurrence", infrastructure.AuthMiddleWare(suite.tokens), suite.taskController.StopRecurrence())
+	suite.router.POST("/webhooks", infrastructure.AuthMiddleWare(suite.tokens), suite.webhookController.CreateSubscription())
+	suite.router.GET("/webhooks/:id/deliveries", infrastructure.AuthMiddleWare(suite.tokens), suite.webhookController.GetDeliveries())
+	suite.router.POST("/password/forgot", suite.passwordController.ForgotPassword())
+	suite.router.POST("/password/reset", suite.passwordController.ResetPassword())
 }
 
 
-func (suite *ControllerTestSuite) GenerateToken(email string, role string) (string, error) {
-
-	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"email" :email,
-		"role" : role,
-		"exp" : time.Now().Add(time.Hour * 72).Unix(),
-	})
-
-	token, err := jwtToken.SignedString([]byte(os.Getenv("JWT_SECRET")))
+// newTestTokenService signs tokens with a key that only lives as long as
+// the test.
+func newTestTokenService(t *testing.T) domain.TokenService {
+	keys, err := infrastructure.NewEphemeralKeyRing()
 	if err != nil {
-		return "", errors.New("error while generating token")
+		t.Fatal(err)
 	}
+	return infrastructure.NewJWTService(infrastructure.JWTConfig{Keys: keys, Issuer: "task-manager", Audience: "task-manager-api", TTL: time.Hour})
+}
+
+func (suite *ControllerTestSuite) GenerateToken(email string, role string) (string, error) {
 
-	return token, nil
+	token, err := suite.tokens.IssueToken(&domain.User{Email: email, Role: role})
+	return token.Token, err
 }
 func (suite *ControllerTestSuite) TestRegisterSuccess() {
     user := domain.User{
@@ -90,8 +124,11 @@ func (suite *ControllerTestSuite) TestRegisterSuccess() {
         Password: "password123",
     }
 
-    suite.mockUserUseCase.On("Register", &user).Return(nil)
-    body, err := json.Marshal(user)
+    userID := primitive.NewObjectID()
+    suite.mockUserUseCase.On("Register", mock.Anything, &user).Return(nil).Run(func(args mock.Arguments) {
+        args.Get(1).(*domain.User).ID = userID
+    })
+    body, err := json.Marshal(dto.RegisterRequest{Email: user.Email, Password: user.Password})
     suite.NoError(err, "error while marshalling user data")
 
     req, err := http.NewRequest(http.MethodPost, "/register", bytes.NewBuffer(body))
@@ -101,14 +138,16 @@ func (suite *ControllerTestSuite) TestRegisterSuccess() {
     recorder := httptest.NewRecorder()
     suite.router.ServeHTTP(recorder, req)
 
-    suite.Equal(http.StatusOK, recorder.Code)
+    suite.Equal(http.StatusCreated, recorder.Code)
+    suite.Equal("/me", recorder.Header().Get("Location"))
 
-    var responseBody gin.H
+    var responseBody dto.UserResponse
     err = json.Unmarshal(recorder.Body.Bytes(), &responseBody)
     suite.NoError(err, "error while unmarshalling response body")
-    suite.Equal(gin.H{"message": "User registered Successfully"}, responseBody)
+    suite.Equal(userID.Hex(), responseBody.ID)
+    suite.Equal("newuser@example.com", responseBody.Email)
 
-    suite.mockUserUseCase.AssertCalled(suite.T(), "Register", &user)
+    suite.mockUserUseCase.AssertExpectations(suite.T())
 }
 
 func (suite *ControllerTestSuite) TestRegisterUserAlreadyExists() {
@@ -117,9 +156,9 @@ func (suite *ControllerTestSuite) TestRegisterUserAlreadyExists() {
         Password: "password123",
     }
 
-	suite.mockUserUseCase.On("Register", &user).Return(errors.New("user already exists"))
+	suite.mockUserUseCase.On("Register", mock.Anything, &user).Return(errors.New("user already exists"))
 
-    body, _ := json.Marshal(user)
+    body, _ := json.Marshal(dto.RegisterRequest{Email: user.Email, Password: user.Password})
     req, _ := http.NewRequest(http.MethodPost, "/register", bytes.NewBuffer(body))
     req.Header.Set("Content-Type", "application/json")
 
@@ -133,7 +172,48 @@ func (suite *ControllerTestSuite) TestRegisterUserAlreadyExists() {
     suite.NoError(err)
     suite.Equal(gin.H{"error": "user already exists"}, responseBody)
 
-	suite.mockUserUseCase.AssertCalled(suite.T(), "Register", &user)
+	suite.mockUserUseCase.AssertCalled(suite.T(), "Register", mock.Anything, &user)
+}
+
+func (suite *ControllerTestSuite) TestRegister_PasswordPolicyViolations() {
+    user := &domain.User{Email: "weak@example.com", Password: "qwerty"}
+    suite.mockUserUseCase.On("Register", mock.Anything, user).Return(&domain.PasswordPolicyError{Violations: []domain.PasswordViolation{
+        {Rule: "min_length", Message: "password must be at least 8 characters long"},
+        {Rule: "common", Message: "password is too common"},
+    }})
+
+    body, _ := json.Marshal(dto.RegisterRequest{Email: user.Email, Password: user.Password})
+    req, _ := http.NewRequest(http.MethodPost, "/register", bytes.NewBuffer(body))
+    req.Header.Set("Content-Type", "application/json")
+
+    recorder := httptest.NewRecorder()
+    suite.router.ServeHTTP(recorder, req)
+
+    suite.Equal(http.StatusBadRequest, recorder.Code)
+    var responseBody struct {
+        Error      string                     `json:"error"`
+        Violations []domain.PasswordViolation `json:"violations"`
+    }
+    suite.NoError(json.Unmarshal(recorder.Body.Bytes(), &responseBody))
+    suite.Equal("password must be at least 8 characters long; password is too common", responseBody.Error)
+    suite.Len(responseBody.Violations, 2)
+    suite.Equal("common", responseBody.Violations[1].Rule)
+}
+
+func (suite *ControllerTestSuite) TestRegister_IgnoresServerManagedFields() {
+    expected := &domain.User{Email: "sneaky@example.com", Password: "password123"}
+    suite.mockUserUseCase.On("Register", mock.Anything, expected).Return(nil)
+
+    body := `{"id": "66b7a0c4e1f3a2b5c8d9e0f1", "email": "sneaky@example.com", "password": "password123", "role": "admin", "verified": true}`
+    req, err := http.NewRequest(http.MethodPost, "/register", bytes.NewBufferString(body))
+    suite.NoError(err)
+    req.Header.Set("Content-Type", "application/json")
+
+    recorder := httptest.NewRecorder()
+    suite.router.ServeHTTP(recorder, req)
+
+    suite.Equal(http.StatusCreated, recorder.Code)
+    suite.mockUserUseCase.AssertCalled(suite.T(), "Register", mock.Anything, expected)
 }
 
 func (suite *ControllerTestSuite) TestLoginSuccess() {
@@ -142,9 +222,9 @@ func (suite *ControllerTestSuite) TestLoginSuccess() {
 		Password: "password123",
 	}
 	
-	suite.mockUserUseCase.On("Login", &user).Return("a_mock_token", nil)
+	suite.mockUserUseCase.On("Login", mock.Anything, &user, mock.Anything).Return(domain.LoginResult{Token: domain.AccessToken{Token: "a_mock_token", ExpiresAt: time.Now().Add(time.Hour)}}, nil)
 	
-	body, _ := json.Marshal(user)
+	body, _ := json.Marshal(dto.LoginRequest{Email: user.Email, Password: user.Password})
 	req, _ := http.NewRequest(http.MethodPost, "/login", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
 	
@@ -153,13 +233,14 @@ func (suite *ControllerTestSuite) TestLoginSuccess() {
 	
 	suite.Equal(http.StatusOK, recorder.Code)
 	
-	var responseBody gin.H
+	var responseBody dto.TokenResponse
 	err := json.Unmarshal(recorder.Body.Bytes(), &responseBody)
 	suite.NoError(err)
-	expectedMessage := "logged in successfully, here is your token: a_mock_token"
-	suite.Equal(gin.H{"message": expectedMessage}, responseBody)
+	suite.Equal("a_mock_token", responseBody.AccessToken)
+	suite.Equal("Bearer", responseBody.TokenType)
+	suite.InDelta(3600, responseBody.ExpiresIn, 1)
 	
-	suite.mockUserUseCase.AssertCalled(suite.T(), "Login", &user)
+	suite.mockUserUseCase.AssertCalled(suite.T(), "Login", mock.Anything, &user, mock.Anything)
 }
 
 func (suite *ControllerTestSuite) TestPromoteUserSuccess() {
@@ -170,7 +251,7 @@ func (suite *ControllerTestSuite) TestPromoteUserSuccess() {
     // Generate a token for the user
     token, err := suite.GenerateToken("kidusm3l@gmail.com", "admin")
     suite.NoError(err)
-    suite.mockUserUseCase.On("PromoteUser", "12345").Return(nil)
+    suite.mockUserUseCase.On("PromoteUser", mock.Anything, "12345").Return(nil)
 
     // Set the Content-Type and Authorization headers
     req.Header.Set("Content-Type", "application/json")
@@ -191,7 +272,7 @@ func (suite *ControllerTestSuite) TestPromoteUserSuccess() {
     // Assert that the response body contains the expected success message and new role
     suite.Equal("user with the given ID promoted to admin", responseBody["message"])
     // Assert that the PromoteUser method was called with the correct user ID
-    suite.mockUserUseCase.AssertCalled(suite.T(), "PromoteUser", "12345")
+    suite.mockUserUseCase.AssertCalled(suite.T(), "PromoteUser", mock.Anything, "12345")
 }
 
 func (suite *ControllerTestSuite) TestPromote_UserUnauthorized() {
@@ -202,7 +283,7 @@ func (suite *ControllerTestSuite) TestPromote_UserUnauthorized() {
     // Generate a token for the user
     token, err := suite.GenerateToken("kidusm3l@gmail.com", "user")
     suite.NoError(err)
-    suite.mockUserUseCase.On("PromoteUser", "12345").Return(nil)
+    suite.mockUserUseCase.On("PromoteUser", mock.Anything, "12345").Return(nil)
 
     // Set the Content-Type and Authorization headers
     req.Header.Set("Content-Type", "application/json")
@@ -224,7 +305,7 @@ func (suite *ControllerTestSuite) TestPromote_UserUnauthorized() {
     suite.Equal("You are not authorized to promote another user", responseBody["error"])
 
     // Assert that the PromoteUser method was not called with the correct user ID
-    suite.mockUserUseCase.AssertNotCalled(suite.T(), "PromoteUser", "12345")
+    suite.mockUserUseCase.AssertNotCalled(suite.T(), "PromoteUser", mock.Anything, "12345")
 }
 
 
@@ -238,7 +319,7 @@ func (suite *ControllerTestSuite) TestPromote_UserAlreadyanAdmin() {
     suite.NoError(err)
 
     // Mock the PromoteUser method to return an error indicating the user is already an admin
-    suite.mockUserUseCase.On("PromoteUser", "12345").Return(errors.New("user is already an admin"))
+    suite.mockUserUseCase.On("PromoteUser", mock.Anything, "12345").Return(errors.New("user is already an admin"))
 
     // Set the Content-Type and Authorization headers
     req.Header.Set("Content-Type", "application/json")
@@ -260,7 +341,7 @@ func (suite *ControllerTestSuite) TestPromote_UserAlreadyanAdmin() {
     suite.Equal("user is already an admin", responseBody["error"])
 
     // Assert that the PromoteUser method was called with the correct user ID
-    suite.mockUserUseCase.AssertCalled(suite.T(), "PromoteUser", "12345")
+    suite.mockUserUseCase.AssertCalled(suite.T(), "PromoteUser", mock.Anything, "12345")
 }
 
 func (suite *ControllerTestSuite) TestGetTasksSuccess() {
@@ -274,7 +355,7 @@ func (suite *ControllerTestSuite) TestGetTasksSuccess() {
     suite.NoError(err)
 
     // Mock the GetTasks method to return the expected tasks
-    suite.mockTaskUseCase.On("GetTasks").Return(returnedTask, nil)
+    suite.mockTaskUseCase.On("GetTasks", mock.Anything).Return(returnedTask, nil)
 
     // Set the Content-Type and Authorization headers
     req.Header.Set("Content-Type", "application/json")
@@ -297,7 +378,7 @@ func (suite *ControllerTestSuite) TestGetTasksSuccess() {
 	suite.Equal(returnedTask[0].Status, responseBody[0].Status)
 
     // Assert that the GetTasks method was called
-    suite.mockTaskUseCase.AssertCalled(suite.T(), "GetTasks")
+    suite.mockTaskUseCase.AssertCalled(suite.T(), "GetTasks", mock.Anything)
 }
 
 func (suite *ControllerTestSuite) TestGetTasks_ErrorFetchingTasks() {
@@ -310,7 +391,7 @@ func (suite *ControllerTestSuite) TestGetTasks_ErrorFetchingTasks() {
     suite.NoError(err)
 
     // Mock the GetTasks method to return the expected tasks
-    suite.mockTaskUseCase.On("GetTasks").Return(nil, errors.New("error while fetching tasks"))
+    suite.mockTaskUseCase.On("GetTasks", mock.Anything).Return(nil, errors.New("error while fetching tasks"))
 
     // Set the Content-Type and Authorization headers
     req.Header.Set("Content-Type", "application/json")
@@ -332,7 +413,7 @@ func (suite *ControllerTestSuite) TestGetTasks_ErrorFetchingTasks() {
     suite.Equal("internal server error", responseBody["error"])
 
     // Assert that the GetTasks method was called
-    suite.mockTaskUseCase.AssertCalled(suite.T(), "GetTasks")
+    suite.mockTaskUseCase.AssertCalled(suite.T(), "GetTasks", mock.Anything)
 }
 
 func (suite *ControllerTestSuite) TestPostTaskSuccess() {
@@ -340,7 +421,10 @@ func (suite *ControllerTestSuite) TestPostTaskSuccess() {
     token, err := suite.GenerateToken("kidusm3l@gmail.com", "admin")
     suite.NoError(err)
 
-    suite.mockTaskUseCase.On("PostTask", task).Return(nil)
+    taskID := primitive.NewObjectID()
+    suite.mockTaskUseCase.On("PostTask", mock.Anything, &task).Return(nil).Run(func(args mock.Arguments) {
+        args.Get(1).(*domain.Task).ID = taskID
+    })
     body, err := json.Marshal(task)
     suite.NoError(err, "no error while marshalling task data")
 
@@ -352,18 +436,20 @@ func (suite *ControllerTestSuite) TestPostTaskSuccess() {
     recorder := httptest.NewRecorder()
     suite.router.ServeHTTP(recorder, req)
 
-    suite.Equal(http.StatusOK, recorder.Code)
+    suite.Equal(http.StatusCreated, recorder.Code)
+    suite.Equal("/tasks/"+taskID.Hex(), recorder.Header().Get("Location"))
 
     // Log the response body
     responseBodyBytes := recorder.Body.Bytes()
     fmt.Println("Response Body:", string(responseBodyBytes))
 
-    var responseBody map[string]interface{}
+    var responseBody dto.TaskResponse
     err = json.Unmarshal(responseBodyBytes, &responseBody)
     suite.NoError(err, "no error while unmarshalling response body")
-    suite.Equal(map[string]interface{}{"message": "task added successfully"}, responseBody)
+    suite.Equal(taskID.Hex(), responseBody.ID)
+    suite.Equal("Title 1", responseBody.Title)
 
-    suite.mockTaskUseCase.AssertCalled(suite.T(), "PostTask", task)
+    suite.mockTaskUseCase.AssertExpectations(suite.T())
 }
 
 func (suite *ControllerTestSuite) TestPostTask_UserNotAuthorized() {
@@ -371,7 +457,7 @@ func (suite *ControllerTestSuite) TestPostTask_UserNotAuthorized() {
     token, err := suite.GenerateToken("kidusm3l@gmail.com", "user")
     suite.NoError(err)
 
-    suite.mockTaskUseCase.On("PostTask", task).Return(nil)
+    suite.mockTaskUseCase.On("PostTask", mock.Anything, &task).Return(nil)
     body, err := json.Marshal(task)
     suite.NoError(err, "no error while marshalling task data")
 
@@ -394,7 +480,7 @@ func (suite *ControllerTestSuite) TestPostTask_UserNotAuthorized() {
     suite.NoError(err, "no error while unmarshalling response body")
     suite.Equal(map[string]interface{}{"error": "You are not authorized to post a task"}, responseBody)
 
-    suite.mockTaskUseCase.AssertNotCalled(suite.T(), "PostTask", task)
+    suite.mockTaskUseCase.AssertNotCalled(suite.T(), "PostTask", mock.Anything, &task)
 }
 
 func (suite *ControllerTestSuite) TestPostTask_ErrorWhileAddingTask() {
@@ -402,7 +488,7 @@ func (suite *ControllerTestSuite) TestPostTask_ErrorWhileAddingTask() {
     token, err := suite.GenerateToken("kidusm3l@gmail.com", "admin")
     suite.NoError(err)
 
-    suite.mockTaskUseCase.On("PostTask", task).Return(errors.New("error while trying to insert data"))
+    suite.mockTaskUseCase.On("PostTask", mock.Anything, &task).Return(errors.New("error while trying to insert data"))
     body, err := json.Marshal(task)
     suite.NoError(err, "no error while marshalling task data")
 
@@ -425,7 +511,61 @@ func (suite *ControllerTestSuite) TestPostTask_ErrorWhileAddingTask() {
     suite.NoError(err, "no error while unmarshalling response body")
     suite.Equal(map[string]interface{}{"error": "internal server error"}, responseBody)
 
-    suite.mockTaskUseCase.AssertCalled(suite.T(), "PostTask", task)
+    suite.mockTaskUseCase.AssertCalled(suite.T(), "PostTask", mock.Anything, &task)
+}
+
+func (suite *ControllerTestSuite) TestPostTask_IgnoresServerManagedFields() {
+    expected := domain.Task{Title: "Title 1", Status: "pending"}
+    suite.mockTaskUseCase.On("PostTask", mock.Anything, &expected).Return(nil)
+
+    token, err := suite.GenerateToken("kidusm3l@gmail.com", "admin")
+    suite.NoError(err)
+    body := `{"id": "66b7a0c4e1f3a2b5c8d9e0f1", "title": "Title 1", "status": "pending", "series_id": "66b7a0c4e1f3a2b5c8d9e0f2", "occurrence": 7}`
+    req, err := http.NewRequest(http.MethodPost, "/tasks", bytes.NewBufferString(body))
+    suite.NoError(err)
+    req.Header.Set("Content-Type", "application/json")
+    req.Header.Set("Authorization", "Bearer " + token)
+
+    recorder := httptest.NewRecorder()
+    suite.router.ServeHTTP(recorder, req)
+
+    suite.Equal(http.StatusCreated, recorder.Code)
+    suite.mockTaskUseCase.AssertCalled(suite.T(), "PostTask", mock.Anything, &expected)
+}
+
+func (suite *ControllerTestSuite) postTask(body string) *httptest.ResponseRecorder {
+    token, err := suite.GenerateToken("kidusm3l@gmail.com", "admin")
+    suite.NoError(err)
+    req, err := http.NewRequest(http.MethodPost, "/tasks", bytes.NewBufferString(body))
+    suite.NoError(err)
+    req.Header.Set("Content-Type", "application/json")
+    req.Header.Set("Authorization", "Bearer " + token)
+
+    recorder := httptest.NewRecorder()
+    suite.router.ServeHTTP(recorder, req)
+    return recorder
+}
+
+func (suite *ControllerTestSuite) TestPostTask_InvalidRecurrenceRule() {
+    expected := domain.Task{Title: "Title 1", Status: "pending", Recurrence: "FREQ=HOURLY"}
+    suite.mockTaskUseCase.On("PostTask", mock.Anything, &expected).Return(errors.New("invalid recurrence rule: unsupported frequency HOURLY"))
+
+    recorder := suite.postTask(`{"title": "Title 1", "status": "pending", "recurrence": "FREQ=HOURLY"}`)
+
+    suite.Equal(http.StatusBadRequest, recorder.Code)
+    suite.JSONEq(`{"error": "invalid recurrence rule: unsupported frequency HOURLY"}`, recorder.Body.String())
+    suite.mockTaskUseCase.AssertExpectations(suite.T())
+}
+
+func (suite *ControllerTestSuite) TestPostTask_RecurringWithoutDueDate() {
+    expected := domain.Task{Title: "Title 1", Status: "pending", Recurrence: "FREQ=WEEKLY"}
+    suite.mockTaskUseCase.On("PostTask", mock.Anything, &expected).Return(errors.New("recurring tasks require a due date"))
+
+    recorder := suite.postTask(`{"title": "Title 1", "status": "pending", "recurrence": "FREQ=WEEKLY"}`)
+
+    suite.Equal(http.StatusBadRequest, recorder.Code)
+    suite.JSONEq(`{"error": "recurring tasks require a due date"}`, recorder.Body.String())
+    suite.mockTaskUseCase.AssertExpectations(suite.T())
 }
 
 func (suite *ControllerTestSuite) TestDeleteTaskSuccess() {
@@ -435,7 +575,7 @@ func (suite *ControllerTestSuite) TestDeleteTaskSuccess() {
 
     token, err := suite.GenerateToken("kidusm3l@gmail.com", "admin")
     suite.NoError(err)
-    suite.mockTaskUseCase.On("DeleteTask", "12345").Return(nil)
+    suite.mockTaskUseCase.On("DeleteTask", mock.Anything, "12345").Return(nil)
 
     req.Header.Set("Content-Type", "application/json")
     req.Header.Set("Authorization", "Bearer " + token)
@@ -451,7 +591,7 @@ func (suite *ControllerTestSuite) TestDeleteTaskSuccess() {
     suite.NoError(err)
 
     suite.Equal("task deleted successfully", responseBody["message"])
-    suite.mockTaskUseCase.AssertCalled(suite.T(), "DeleteTask", "12345")
+    suite.mockTaskUseCase.AssertCalled(suite.T(), "DeleteTask", mock.Anything, "12345")
 }
 
 func (suite *ControllerTestSuite) TestUpdateTaskSuccess() {
@@ -459,7 +599,7 @@ func (suite *ControllerTestSuite) TestUpdateTaskSuccess() {
     token, err := suite.GenerateToken("kidusm3l@gmail.com", "admin")
     suite.NoError(err)
 
-    suite.mockTaskUseCase.On("UpdateTask", "12345", &task).Return(nil)
+    suite.mockTaskUseCase.On("UpdateTask", mock.Anything, "12345", &task).Return(nil)
     body, err := json.Marshal(task)
     suite.NoError(err, "no error while marshalling task data")
 
@@ -482,7 +622,7 @@ func (suite *ControllerTestSuite) TestUpdateTaskSuccess() {
     suite.NoError(err, "no error while unmarshalling response body")
     suite.Equal(map[string]interface{}{"message": "task updated successfully"}, responseBody)
 
-    suite.mockTaskUseCase.AssertCalled(suite.T(), "UpdateTask", "12345", &task)
+    suite.mockTaskUseCase.AssertCalled(suite.T(), "UpdateTask", mock.Anything, "12345", &task)
 }
 
 func (suite *ControllerTestSuite) TestGetTaskByIDSuccess() {
@@ -496,7 +636,7 @@ func (suite *ControllerTestSuite) TestGetTaskByIDSuccess() {
     suite.NoError(err)
 
     // Mock the GetTasks method to return the expected tasks
-    suite.mockTaskUseCase.On("GetTask", "12345").Return(returnedTask, nil)
+    suite.mockTaskUseCase.On("GetTask", mock.Anything, "12345").Return(returnedTask, nil)
 
     // Set the Content-Type and Authorization headers
     req.Header.Set("Content-Type", "application/json")
@@ -519,7 +659,387 @@ func (suite *ControllerTestSuite) TestGetTaskByIDSuccess() {
 	suite.Equal(returnedTask.Status, responseBody.Status)
 
     // Assert that the GetTasks method was called
-    suite.mockTaskUseCase.AssertCalled(suite.T(), "GetTask", "12345")
+    suite.mockTaskUseCase.AssertCalled(suite.T(), "GetTask", mock.Anything, "12345")
+}
+
+func (suite *ControllerTestSuite) TestSearchTasksSuccess() {
+    results := []*domain.TaskSearchResult{
+        {
+            Task: &suite.SingleTask,
+            Score: 2.5,
+            Highlights: map[string]string{"title": "<mark>Title</mark> 1"},
+        },
+    }
+    suite.mockTaskUseCase.On("SearchTasks", mock.Anything, "title", 5).Return(results, nil)
+
+    token, err := suite.GenerateToken("kidusm3l@gmail.com", "user")
+    suite.NoError(err)
+    req, err := http.NewRequest(http.MethodGet, "/tasks/search?q=title&limit=5", nil)
+    suite.NoError(err)
+    req.Header.Set("Authorization", "Bearer " + token)
+
+    recorder := httptest.NewRecorder()
+    suite.router.ServeHTTP(recorder, req)
+
+    suite.Equal(http.StatusOK, recorder.Code)
+
+    var responseBody []*domain.TaskSearchResult
+    err = json.Unmarshal(recorder.Body.Bytes(), &responseBody)
+    suite.NoError(err)
+    suite.Equal(suite.SingleTask.Title, responseBody[0].Task.Title)
+    suite.Equal("<mark>Title</mark> 1", responseBody[0].Highlights["title"])
+    suite.mockTaskUseCase.AssertCalled(suite.T(), "SearchTasks", mock.Anything, "title", 5)
+}
+
+func (suite *ControllerTestSuite) TestSearchTasks_MissingQuery() {
+    suite.mockTaskUseCase.On("SearchTasks", mock.Anything, "", 0).Return(nil, errors.New("search query is required"))
+
+    token, err := suite.GenerateToken("kidusm3l@gmail.com", "user")
+    suite.NoError(err)
+    req, err := http.NewRequest(http.MethodGet, "/tasks/search", nil)
+    suite.NoError(err)
+    req.Header.Set("Authorization", "Bearer " + token)
+
+    recorder := httptest.NewRecorder()
+    suite.router.ServeHTTP(recorder, req)
+
+    suite.Equal(http.StatusBadRequest, recorder.Code)
+
+    var responseBody gin.H
+    err = json.Unmarshal(recorder.Body.Bytes(), &responseBody)
+    suite.NoError(err)
+    suite.Equal("search query is required", responseBody["error"])
+}
+
+func (suite *ControllerTestSuite) TestUpdateRecurrence_InvalidRule() {
+    suite.mockTaskUseCase.On("UpdateRecurrence", mock.Anything, "12345", "FREQ=HOURLY").Return(errors.New("invalid recurrence rule: unsupported frequency HOURLY"))
+
+    token, err := suite.GenerateToken("kidusm3l@gmail.com", "admin")
+    suite.NoError(err)
+    req, err := http.NewRequest(http.MethodPut, "/tasks/12345/recurrence", bytes.NewBufferString(`{"recurrence": "FREQ=HOURLY"}`))
+    suite.NoError(err)
+    req.Header.Set("Content-Type", "application/json")
+    req.Header.Set("Authorization", "Bearer " + token)
+
+    recorder := httptest.NewRecorder()
+    suite.router.ServeHTTP(recorder, req)
+
+    suite.Equal(http.StatusBadRequest, recorder.Code)
+    suite.mockTaskUseCase.AssertCalled(suite.T(), "UpdateRecurrence", mock.Anything, "12345", "FREQ=HOURLY")
+}
+
+func (suite *ControllerTestSuite) TestStopRecurrenceSuccess() {
+    suite.mockTaskUseCase.On("StopRecurrence", mock.Anything, "12345").Return(nil)
+
+    token, err := suite.GenerateToken("kidusm3l@gmail.com", "admin")
+    suite.NoError(err)
+    req, err := http.NewRequest(http.MethodDelete, "/tasks/12345/recurrence", nil)
+    suite.NoError(err)
+    req.Header.Set("Authorization", "Bearer " + token)
+
+    recorder := httptest.NewRecorder()
+    suite.router.ServeHTTP(recorder, req)
+
+    suite.Equal(http.StatusOK, recorder.Code)
+
+    var responseBody gin.H
+    err = json.Unmarshal(recorder.Body.Bytes(), &responseBody)
+    suite.NoError(err)
+    suite.Equal("recurrence stopped successfully", responseBody["message"])
+}
+
+func (suite *ControllerTestSuite) TestCreateWebhookSuccess() {
+    subscription := domain.WebhookSubscription{URL: "https://hooks.example.com", EventTypes: []string{"task.created"}}
+    subscriptionID := primitive.NewObjectID()
+    suite.mockWebhookUseCase.On("CreateSubscription", mock.Anything, &subscription).Return(nil).Run(func(args mock.Arguments) {
+        args.Get(1).(*domain.WebhookSubscription).ID = subscriptionID
+    })
+
+    token, err := suite.GenerateToken("kidusm3l@gmail.com", "admin")
+    suite.NoError(err)
+    body, _ := json.Marshal(subscription)
+    req, err := http.NewRequest(http.MethodPost, "/webhooks", bytes.NewBuffer(body))
+    suite.NoError(err)
+    req.Header.Set("Content-Type", "application/json")
+    req.Header.Set("Authorization", "Bearer " + token)
+
+    recorder := httptest.NewRecorder()
+    suite.router.ServeHTTP(recorder, req)
+
+    suite.Equal(http.StatusCreated, recorder.Code)
+    suite.Equal("/webhooks/"+subscriptionID.Hex(), recorder.Header().Get("Location"))
+    suite.mockWebhookUseCase.AssertExpectations(suite.T())
+}
+
+func (suite *ControllerTestSuite) TestCreateWebhook_UserNotAuthorized() {
+    token, err := suite.GenerateToken("kidusm3l@gmail.com", "user")
+    suite.NoError(err)
+    req, err := http.NewRequest(http.MethodPost, "/webhooks", bytes.NewBufferString(`{"url": "https://hooks.example.com"}`))
+    suite.NoError(err)
+    req.Header.Set("Content-Type", "application/json")
+    req.Header.Set("Authorization", "Bearer " + token)
+
+    recorder := httptest.NewRecorder()
+    suite.router.ServeHTTP(recorder, req)
+
+    suite.Equal(http.StatusForbidden, recorder.Code)
+    suite.mockWebhookUseCase.AssertNotCalled(suite.T(), "CreateSubscription", mock.Anything, mock.Anything)
+}
+
+func (suite *ControllerTestSuite) TestGetWebhookDeliveries_NotFound() {
+    suite.mockWebhookUseCase.On("GetDeliveries", mock.Anything, "12345").Return(nil, errors.New("webhook with the specified id not found"))
+
+    token, err := suite.GenerateToken("kidusm3l@gmail.com", "admin")
+    suite.NoError(err)
+    req, err := http.NewRequest(http.MethodGet, "/webhooks/12345/deliveries", nil)
+    suite.NoError(err)
+    req.Header.Set("Authorization", "Bearer " + token)
+
+    recorder := httptest.NewRecorder()
+    suite.router.ServeHTTP(recorder, req)
+
+    suite.Equal(http.StatusNotFound, recorder.Code)
+}
+
+func (suite *ControllerTestSuite) TestVerifyEmailSuccess() {
+    suite.mockVerificationUseCase.On("VerifyEmail", mock.Anything, "4f1c0a").Return(nil)
+
+    req, err := http.NewRequest(http.MethodGet, "/verify?token=4f1c0a", nil)
+    suite.NoError(err)
+
+    recorder := httptest.NewRecorder()
+    suite.router.ServeHTTP(recorder, req)
+
+    suite.Equal(http.StatusOK, recorder.Code)
+    suite.mockVerificationUseCase.AssertCalled(suite.T(), "VerifyEmail", mock.Anything, "4f1c0a")
+}
+
+func (suite *ControllerTestSuite) TestVerifyEmail_InvalidToken() {
+    suite.mockVerificationUseCase.On("VerifyEmail", mock.Anything, "expired").Return(errors.New("invalid or expired token"))
+
+    req, err := http.NewRequest(http.MethodGet, "/verify?token=expired", nil)
+    suite.NoError(err)
+
+    recorder := httptest.NewRecorder()
+    suite.router.ServeHTTP(recorder, req)
+
+    suite.Equal(http.StatusBadRequest, recorder.Code)
+}
+
+func (suite *ControllerTestSuite) TestLogin_UnverifiedEmail() {
+    user := domain.User{Email: "unverified@example.com", Password: "password123"}
+    suite.mockUserUseCase.On("Login", mock.Anything, &user, mock.Anything).Return(domain.LoginResult{}, errors.New("email address not verified"))
+
+    body, err := json.Marshal(dto.LoginRequest{Email: user.Email, Password: user.Password})
+    suite.NoError(err)
+    req, err := http.NewRequest(http.MethodPost, "/login", bytes.NewBuffer(body))
+    suite.NoError(err)
+    req.Header.Set("Content-Type", "application/json")
+
+    recorder := httptest.NewRecorder()
+    suite.router.ServeHTTP(recorder, req)
+
+    suite.Equal(http.StatusForbidden, recorder.Code)
+}
+
+func (suite *ControllerTestSuite) TestLogin_ErrorStatuses() {
+    for message, expected := range map[string]int{
+        "invalid credentials":         http.StatusUnauthorized,
+        "required fields are missing": http.StatusBadRequest,
+        "internal server error":       http.StatusInternalServerError,
+    } {
+        user := domain.User{Email: "wrong@example.com", Password: message}
+        suite.mockUserUseCase.On("Login", mock.Anything, &user, mock.Anything).Return(domain.LoginResult{}, errors.New(message))
+
+        body, err := json.Marshal(dto.LoginRequest{Email: user.Email, Password: user.Password})
+        suite.NoError(err)
+        req, err := http.NewRequest(http.MethodPost, "/login", bytes.NewBuffer(body))
+        suite.NoError(err)
+        req.Header.Set("Content-Type", "application/json")
+
+        recorder := httptest.NewRecorder()
+        suite.router.ServeHTTP(recorder, req)
+
+        suite.Equal(expected, recorder.Code, message)
+        var responseBody dto.ErrorResponse
+        suite.NoError(json.Unmarshal(recorder.Body.Bytes(), &responseBody))
+        suite.Equal(message, responseBody.Error)
+    }
+}
+
+func (suite *ControllerTestSuite) TestMe_OmitsPasswordHash() {
+    suite.mockUserUseCase.On("GetCurrentUser", mock.Anything, "kidusm3l@gmail.com").Return(domain.User{Email: "kidusm3l@gmail.com", Password: "$2a$10$hash", Role: "user", DisplayName: "Kidus"}, nil)
+
+    token, err := suite.GenerateToken("kidusm3l@gmail.com", "user")
+    suite.NoError(err)
+    req, err := http.NewRequest(http.MethodGet, "/me", nil)
+    suite.NoError(err)
+    req.Header.Set("Authorization", "Bearer " + token)
+
+    recorder := httptest.NewRecorder()
+    suite.router.ServeHTTP(recorder, req)
+
+    suite.Equal(http.StatusOK, recorder.Code)
+    var responseBody gin.H
+    suite.NoError(json.Unmarshal(recorder.Body.Bytes(), &responseBody))
+    suite.Equal("Kidus", responseBody["display_name"])
+    suite.NotContains(responseBody, "password")
+    suite.NotContains(recorder.Body.String(), "$2a$10$hash")
+}
+
+func (suite *ControllerTestSuite) TestChangePassword_WrongCurrentPassword() {
+    suite.mockUserUseCase.On("ChangePassword", mock.Anything, "kidusm3l@gmail.com", "wrong", "correct horse battery").Return(domain.AccessToken{}, errors.New("current password is incorrect"))
+
+    token, err := suite.GenerateToken("kidusm3l@gmail.com", "user")
+    suite.NoError(err)
+    req, err := http.NewRequest(http.MethodPut, "/me/password", bytes.NewBufferString(`{"current_password": "wrong", "new_password": "correct horse battery"}`))
+    suite.NoError(err)
+    req.Header.Set("Content-Type", "application/json")
+    req.Header.Set("Authorization", "Bearer " + token)
+
+    recorder := httptest.NewRecorder()
+    suite.router.ServeHTTP(recorder, req)
+
+    suite.Equal(http.StatusBadRequest, recorder.Code)
+}
+
+func (suite *ControllerTestSuite) TestLogin_TooManyAttempts() {
+    user := domain.User{Email: "locked@example.com", Password: "password123"}
+    suite.mockUserUseCase.On("Login", mock.Anything, &user, mock.Anything).Return(domain.LoginResult{}, &domain.RetryError{Message: "too many failed login attempts, try again later", RetryAfter: 1500 * time.Millisecond})
+
+    body, err := json.Marshal(dto.LoginRequest{Email: user.Email, Password: user.Password})
+    suite.NoError(err)
+    req, err := http.NewRequest(http.MethodPost, "/login", bytes.NewBuffer(body))
+    suite.NoError(err)
+    req.Header.Set("Content-Type", "application/json")
+
+    recorder := httptest.NewRecorder()
+    suite.router.ServeHTTP(recorder, req)
+
+    suite.Equal(http.StatusTooManyRequests, recorder.Code)
+    suite.Equal("2", recorder.Header().Get("Retry-After"))
+}
+
+func (suite *ControllerTestSuite) TestLogin_TwoFactorChallenge() {
+    user := domain.User{Email: "2fa@example.com", Password: "password123"}
+    suite.mockUserUseCase.On("Login", mock.Anything, &user, mock.Anything).Return(domain.LoginResult{TwoFactorRequired: true, ChallengeToken: "a_challenge"}, nil)
+
+    body, err := json.Marshal(dto.LoginRequest{Email: user.Email, Password: user.Password})
+    suite.NoError(err)
+    req, err := http.NewRequest(http.MethodPost, "/login", bytes.NewBuffer(body))
+    suite.NoError(err)
+    req.Header.Set("Content-Type", "application/json")
+
+    recorder := httptest.NewRecorder()
+    suite.router.ServeHTTP(recorder, req)
+
+    suite.Equal(http.StatusOK, recorder.Code)
+    var responseBody gin.H
+    suite.NoError(json.Unmarshal(recorder.Body.Bytes(), &responseBody))
+    suite.Equal(true, responseBody["two_factor_required"])
+    suite.Equal("a_challenge", responseBody["challenge_token"])
+}
+
+func (suite *ControllerTestSuite) TestLoginTwoFactor() {
+    suite.mockUserUseCase.On("CompleteTwoFactorLogin", mock.Anything, "a_challenge", "123456", mock.Anything).Return(domain.AccessToken{Token: "a_mock_token", ExpiresAt: time.Now().Add(time.Hour)}, nil)
+    suite.mockUserUseCase.On("CompleteTwoFactorLogin", mock.Anything, "a_challenge", "000000", mock.Anything).Return(domain.AccessToken{}, errors.New("invalid two-factor code"))
+
+    for code, expected := range map[string]int{"123456": http.StatusOK, "000000": http.StatusUnauthorized} {
+        body, err := json.Marshal(dto.TwoFactorLoginRequest{ChallengeToken: "a_challenge", Code: code})
+        suite.NoError(err)
+        req, err := http.NewRequest(http.MethodPost, "/login/2fa", bytes.NewBuffer(body))
+        suite.NoError(err)
+        req.Header.Set("Content-Type", "application/json")
+
+        recorder := httptest.NewRecorder()
+        suite.router.ServeHTTP(recorder, req)
+        suite.Equal(expected, recorder.Code, code)
+        if expected == http.StatusOK {
+            var responseBody dto.TokenResponse
+            suite.NoError(json.Unmarshal(recorder.Body.Bytes(), &responseBody))
+            suite.Equal("a_mock_token", responseBody.AccessToken)
+            suite.Equal("Bearer", responseBody.TokenType)
+        }
+    }
+}
+
+func (suite *ControllerTestSuite) TestConfirmTwoFactor() {
+    suite.mockTwoFactorUseCase.On("ConfirmEnrollment", mock.Anything, "kidusm3l@gmail.com", "123456").Return([]string{"aaaa-bbbb-cccc-dddd"}, nil)
+
+    token, err := suite.GenerateToken("kidusm3l@gmail.com", "user")
+    suite.NoError(err)
+    req, err := http.NewRequest(http.MethodPost, "/me/2fa/confirm", bytes.NewBufferString(`{"code": "123456"}`))
+    suite.NoError(err)
+    req.Header.Set("Content-Type", "application/json")
+    req.Header.Set("Authorization", "Bearer " + token)
+
+    recorder := httptest.NewRecorder()
+    suite.router.ServeHTTP(recorder, req)
+
+    suite.Equal(http.StatusOK, recorder.Code)
+    var responseBody gin.H
+    suite.NoError(json.Unmarshal(recorder.Body.Bytes(), &responseBody))
+    suite.Equal([]interface{}{"aaaa-bbbb-cccc-dddd"}, responseBody["recovery_codes"])
+}
+
+func (suite *ControllerTestSuite) TestUnlockAccount() {
+    suite.mockUserUseCase.On("UnlockAccount", mock.Anything, "12345").Return(nil)
+
+    for role, expected := range map[string]int{"user": http.StatusForbidden, "admin": http.StatusOK} {
+        token, err := suite.GenerateToken("kidusm3l@gmail.com", role)
+        suite.NoError(err)
+        req, err := http.NewRequest(http.MethodPut, "/users/12345/unlock", nil)
+        suite.NoError(err)
+        req.Header.Set("Authorization", "Bearer " + token)
+
+        recorder := httptest.NewRecorder()
+        suite.router.ServeHTTP(recorder, req)
+        suite.Equal(expected, recorder.Code, role)
+    }
+    suite.mockUserUseCase.AssertNumberOfCalls(suite.T(), "UnlockAccount", 1)
+}
+
+func (suite *ControllerTestSuite) TestForgotPasswordSuccess() {
+    suite.mockPasswordResetUseCase.On("ForgotPassword", mock.Anything, "kidusm3l@gmail.com").Return(nil)
+
+    req, err := http.NewRequest(http.MethodPost, "/password/forgot", bytes.NewBufferString(`{"email": "kidusm3l@gmail.com"}`))
+    suite.NoError(err)
+    req.Header.Set("Content-Type", "application/json")
+
+    recorder := httptest.NewRecorder()
+    suite.router.ServeHTTP(recorder, req)
+
+    suite.Equal(http.StatusOK, recorder.Code)
+    suite.mockPasswordResetUseCase.AssertCalled(suite.T(), "ForgotPassword", mock.Anything, "kidusm3l@gmail.com")
+}
+
+func (suite *ControllerTestSuite) TestResetPasswordSuccess() {
+    suite.mockPasswordResetUseCase.On("ResetPassword", mock.Anything, "4f1c0a", "correct horse battery").Return(nil)
+
+    req, err := http.NewRequest(http.MethodPost, "/password/reset", bytes.NewBufferString(`{"token": "4f1c0a", "password": "correct horse battery"}`))
+    suite.NoError(err)
+    req.Header.Set("Content-Type", "application/json")
+
+    recorder := httptest.NewRecorder()
+    suite.router.ServeHTTP(recorder, req)
+
+    suite.Equal(http.StatusOK, recorder.Code)
+}
+
+func (suite *ControllerTestSuite) TestResetPassword_InvalidToken() {
+    suite.mockPasswordResetUseCase.On("ResetPassword", mock.Anything, "used", "correct horse battery").Return(errors.New("invalid or expired token"))
+
+    req, err := http.NewRequest(http.MethodPost, "/password/reset", bytes.NewBufferString(`{"token": "used", "password": "correct horse battery"}`))
+    suite.NoError(err)
+    req.Header.Set("Content-Type", "application/json")
+
+    recorder := httptest.NewRecorder()
+    suite.router.ServeHTTP(recorder, req)
+
+    suite.Equal(http.StatusBadRequest, recorder.Code)
+    var responseBody gin.H
+    suite.NoError(json.Unmarshal(recorder.Body.Bytes(), &responseBody))
+    suite.Equal(gin.H{"error": "invalid or expired token"}, responseBody)
 }
 
 
@@ -0,0 +1,126 @@
+package controller_test
+
+import (
+	"context"
+	"bufio"
+	"golang-clean-architecture/delivery/controllers"
+	"golang-clean-architecture/domain"
+	"golang-clean-architecture/infrastructure"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/suite"
+)
+
+type StreamControllerTestSuite struct {
+	suite.Suite
+	bus    *infrastructure.EventBus
+	server *httptest.Server
+	tokens domain.TokenService
+}
+
+func (suite *StreamControllerTestSuite) SetupTest() {
+	suite.bus = infrastructure.NewEventBus(10)
+	streamController := &controllers.StreamController{Events: suite.bus}
+
+	suite.tokens = newTestTokenService(suite.T())
+	router := gin.New()
+	router.GET("/tasks/stream", infrastructure.QueryTokenAuth(), infrastructure.AuthMiddleWare(suite.tokens), streamController.StreamTasks())
+	suite.server = httptest.NewServer(router)
+}
+
+func (suite *StreamControllerTestSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+func (suite *StreamControllerTestSuite) token(role string) string {
+	token, err := suite.tokens.IssueToken(&domain.User{Email: "kidusm3l@gmail.com", Role: role})
+	suite.Require().NoError(err)
+	return token.Token
+}
+
+// readEvents collects SSE events (as "type:id") until count are read.
+func readEvents(scanner *bufio.Scanner, count int) []string {
+	var events []string
+	var id string
+	for len(events) < count && scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "id: ") {
+			id = strings.TrimPrefix(line, "id: ")
+		}
+		if strings.HasPrefix(line, "event: ") {
+			events = append(events, strings.TrimPrefix(line, "event: ")+":"+id)
+			id = ""
+		}
+	}
+	return events
+}
+
+func (suite *StreamControllerTestSuite) TestSSEResumesAndFiltersByVisibility() {
+	suite.bus.Publish(context.Background(), domain.Event{ID: "1", Type: "task.created"})
+	suite.bus.Publish(context.Background(), domain.Event{ID: "2", Type: "task.updated"})
+
+	req, err := http.NewRequest(http.MethodGet, suite.server.URL+"/tasks/stream", nil)
+	suite.NoError(err)
+	req.Header.Set("Authorization", "Bearer "+suite.token("user"))
+	req.Header.Set("Last-Event-ID", "1")
+
+	response, err := http.DefaultClient.Do(req)
+	suite.NoError(err)
+	defer response.Body.Close()
+	suite.Equal(http.StatusOK, response.StatusCode)
+	suite.Equal("text/event-stream", response.Header.Get("Content-Type"))
+
+	scanner := bufio.NewScanner(response.Body)
+	suite.Equal([]string{"task.updated:2"}, readEvents(scanner, 1))
+
+	suite.bus.Publish(context.Background(), domain.Event{ID: "3", Type: "user.promoted"})
+	suite.bus.Publish(context.Background(), domain.Event{ID: "4", Type: "task.deleted"})
+	suite.Equal([]string{"task.deleted:4"}, readEvents(scanner, 1))
+}
+
+func (suite *StreamControllerTestSuite) TestSSEResetWhenEventWasEvicted() {
+	req, err := http.NewRequest(http.MethodGet, suite.server.URL+"/tasks/stream?access_token="+suite.token("admin"), nil)
+	suite.NoError(err)
+	req.Header.Set("Last-Event-ID", "gone")
+
+	response, err := http.DefaultClient.Do(req)
+	suite.NoError(err)
+	defer response.Body.Close()
+	suite.Equal(http.StatusOK, response.StatusCode)
+
+	scanner := bufio.NewScanner(response.Body)
+	suite.Equal([]string{"reset:"}, readEvents(scanner, 1))
+}
+
+func (suite *StreamControllerTestSuite) TestWebSocketStream() {
+	url := "ws" + strings.TrimPrefix(suite.server.URL, "http") + "/tasks/stream?access_token=" + suite.token("admin")
+	connection, _, err := websocket.DefaultDialer.Dial(url, nil)
+	suite.Require().NoError(err)
+	defer connection.Close()
+
+	// give the handler a moment to subscribe before publishing
+	time.Sleep(50 * time.Millisecond)
+	suite.bus.Publish(context.Background(), domain.Event{ID: "1", Type: "user.promoted"})
+
+	var received domain.Event
+	connection.SetReadDeadline(time.Now().Add(2 * time.Second))
+	suite.NoError(connection.ReadJSON(&received))
+	suite.Equal("user.promoted", received.Type)
+}
+
+func (suite *StreamControllerTestSuite) TestStreamRequiresToken() {
+	response, err := http.Get(suite.server.URL + "/tasks/stream")
+	suite.NoError(err)
+	defer response.Body.Close()
+	suite.Equal(http.StatusBadRequest, response.StatusCode)
+}
+
+func TestStreamControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(StreamControllerTestSuite))
+}
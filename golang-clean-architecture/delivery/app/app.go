@@ -0,0 +1,98 @@
+// Package app is the composition root: it builds every repository, service
+// and use case once and hands them to the router and the background workers.
+package app
+
+import (
+	"context"
+	"golang-clean-architecture/delivery/router"
+	"golang-clean-architecture/domain"
+	"golang-clean-architecture/infrastructure"
+	"golang-clean-architecture/repository"
+	usecase "golang-clean-architecture/use_cases"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// App is the application built over one set of repositories and services.
+type App struct {
+	Config       Config
+	Dependencies router.Dependencies
+	Reminders    domain.ReminderUseCase
+}
+
+// New builds the use cases over repositories and services. The repositories
+// can be backed by anything, which is how tests build the application
+// without a database.
+func New(config Config, repositories Repositories, services Services) *App {
+	logger, metrics, tracing := services.Logger, services.Metrics, services.Tracing
+
+	// the same decorated repositories are shared by every use case, so that
+	// they all see the writes going through the task cache
+	users := infrastructure.TraceUserRepository(infrastructure.InstrumentUserRepository(repositories.Users, metrics), tracing)
+	tasks, searcher := repositories.Tasks, repositories.TaskSearch
+	if searcher == nil {
+		// the index has to see every write, so it wraps the backend itself
+		indexed := repository.NewIndexedTaskRepository(tasks)
+		tasks, searcher = indexed, indexed
+	}
+	tasks = infrastructure.InstrumentTaskRepository(tasks, metrics)
+	tasks = infrastructure.TraceTaskRepository(infrastructure.CacheTaskRepository(tasks, services.TaskCache, config.TaskCacheTTL, metrics), tracing)
+	search := infrastructure.TraceTaskSearcher(searcher, tracing)
+
+	webhooks := usecase.NewWebhookUseCase(repositories.Webhooks, services.WebhookSender, logger)
+	events := infrastructure.NewEventBus(config.EventBufferSize, webhooks)
+	guard := infrastructure.InstrumentLoginGuard(usecase.NewLoginGuard(repositories.LoginAttempts, events, config.LoginGuard, logger), metrics)
+	verification := usecase.NewEmailVerificationUseCase(users, repositories.OneTimeTokens, services.Mailer, config.EmailVerificationTTL, config.EmailVerificationURL, logger)
+	twoFactor := usecase.NewTwoFactorUseCase(users, repositories.TwoFactor, repositories.OneTimeTokens, config.TwoFactorIssuer, config.TwoFactorChallengeTTL)
+
+	return &App{
+		Config: config,
+		Dependencies: router.Dependencies{
+			Tasks:         infrastructure.TraceTaskUseCase(usecase.NewTaskUseCase(tasks, search, events), tracing),
+			Users:         infrastructure.TraceUserUseCase(usecase.NewUserUseCase(users, verification, guard, twoFactor, services.Tokens, services.Hasher, services.Policy, events, config.RequireEmailVerification, logger), tracing),
+			Verification:  verification,
+			TwoFactor:     twoFactor,
+			PasswordReset: usecase.NewPasswordResetUseCase(users, repositories.OneTimeTokens, services.Mailer, services.Hasher, services.Policy, config.PasswordResetTTL, config.PasswordResetURL, logger),
+			APITokens:     usecase.NewAPITokenUseCase(users, repositories.APITokens, logger),
+			Webhooks:      webhooks,
+			Events:        events,
+			Sessions:      users,
+			Tokens:        services.Tokens,
+			Keys:          services.Keys,
+			RateLimits:    services.RateLimits,
+			Logger:        logger,
+			Metrics:       metrics,
+			Tracing:       tracing,
+		},
+		Reminders: usecase.NewReminderUseCase(tasks, services.Notifier, config.ReminderLeadTimes),
+	}
+}
+
+// Setup mounts the API on engine.
+func (app *App) Setup(engine *gin.Engine) {
+	router.Setup(engine, app.Dependencies, app.Config.Router)
+}
+
+// StartWorkers runs the background jobs until ctx is done.
+func (app *App) StartWorkers(ctx context.Context) {
+	logger := app.Dependencies.Logger
+	go infrastructure.RunEvery(ctx, app.Config.RecurrenceInterval, func() {
+		err := app.Dependencies.Tasks.MaterializeRecurringTasks(ctx, time.Now().Add(app.Config.RecurrenceLookahead))
+		if err != nil {
+			logger.Error("error while materialising recurring tasks", "error", err)
+		}
+	})
+	go infrastructure.RunEvery(ctx, app.Config.ReminderInterval, func() {
+		err := app.Reminders.CheckDueTasks(ctx, time.Now())
+		if err != nil {
+			logger.Error("error while checking due tasks", "error", err)
+		}
+	})
+	go infrastructure.RunEvery(ctx, app.Config.WebhookInterval, func() {
+		err := app.Dependencies.Webhooks.DeliverPending(ctx, time.Now())
+		if err != nil {
+			logger.Error("error while delivering webhooks", "error", err)
+		}
+	})
+}
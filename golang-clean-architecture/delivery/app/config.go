@@ -0,0 +1,69 @@
+package app
+
+import (
+	"golang-clean-architecture/delivery/router"
+	"golang-clean-architecture/infrastructure"
+	usecase "golang-clean-architecture/use_cases"
+	"time"
+)
+
+// Config holds the settings the application is built with. Services and
+// repositories that pick a backend, like the mailer or the task cache, read
+// their own settings when they are loaded.
+type Config struct {
+	Router router.Config
+
+	// RequireEmailVerification is off by default so that accounts created
+	// before verification existed can still log in until they have verified.
+	RequireEmailVerification bool
+	EmailVerificationTTL     time.Duration
+	EmailVerificationURL     string
+	PasswordResetTTL         time.Duration
+	PasswordResetURL         string
+	TwoFactorIssuer          string
+	TwoFactorChallengeTTL    time.Duration
+	LoginGuard               usecase.LoginGuardPolicy
+
+	TaskCacheTTL    time.Duration
+	EventBufferSize int
+
+	RecurrenceInterval  time.Duration
+	RecurrenceLookahead time.Duration
+	ReminderInterval    time.Duration
+	ReminderLeadTimes   []time.Duration
+	WebhookInterval     time.Duration
+}
+
+// LoadConfig reads the settings from the environment, falling back to
+// defaults for the ones that aren't set.
+func LoadConfig() Config {
+	defaults := usecase.DefaultLoginGuardPolicy()
+	return Config{
+		Router: router.LoadConfig(),
+
+		RequireEmailVerification: infrastructure.GetEnvBool("REQUIRE_EMAIL_VERIFICATION", false),
+		EmailVerificationTTL:     infrastructure.GetEnvDuration("EMAIL_VERIFICATION_TTL", 24*time.Hour),
+		EmailVerificationURL:     infrastructure.GetEnv("EMAIL_VERIFICATION_URL", "http://localhost:8080/api/v1/verify"),
+		PasswordResetTTL:         infrastructure.GetEnvDuration("PASSWORD_RESET_TTL", time.Hour),
+		PasswordResetURL:         infrastructure.GetEnv("PASSWORD_RESET_URL", "http://localhost:3000/reset-password"),
+		TwoFactorIssuer:          infrastructure.GetEnv("TWO_FACTOR_ISSUER", "Task Manager"),
+		TwoFactorChallengeTTL:    infrastructure.GetEnvDuration("TWO_FACTOR_CHALLENGE_TTL", 5*time.Minute),
+		LoginGuard: usecase.LoginGuardPolicy{
+			MaxAccountFailures: infrastructure.GetEnvInt("LOGIN_MAX_FAILURES", defaults.MaxAccountFailures),
+			MaxIPFailures:      infrastructure.GetEnvInt("LOGIN_MAX_IP_FAILURES", defaults.MaxIPFailures),
+			Window:             infrastructure.GetEnvDuration("LOGIN_FAILURE_WINDOW", defaults.Window),
+			Lockout:            infrastructure.GetEnvDuration("LOGIN_LOCKOUT", defaults.Lockout),
+			BaseDelay:          infrastructure.GetEnvDuration("LOGIN_BASE_DELAY", defaults.BaseDelay),
+			MaxDelay:           infrastructure.GetEnvDuration("LOGIN_MAX_DELAY", defaults.MaxDelay),
+		},
+
+		TaskCacheTTL:    infrastructure.GetEnvDuration("TASK_CACHE_TTL", 30*time.Second),
+		EventBufferSize: infrastructure.GetEnvInt("EVENT_BUFFER_SIZE", 1000),
+
+		RecurrenceInterval:  infrastructure.GetEnvDuration("RECURRENCE_INTERVAL", time.Hour),
+		RecurrenceLookahead: infrastructure.GetEnvDuration("RECURRENCE_LOOKAHEAD", 24*time.Hour),
+		ReminderInterval:    infrastructure.GetEnvDuration("REMINDER_INTERVAL", 5*time.Minute),
+		ReminderLeadTimes:   infrastructure.GetEnvDurations("REMINDER_LEAD_TIMES", []time.Duration{24 * time.Hour, time.Hour}),
+		WebhookInterval:     infrastructure.GetEnvDuration("WEBHOOK_INTERVAL", 5*time.Second),
+	}
+}
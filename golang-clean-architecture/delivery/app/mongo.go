@@ -0,0 +1,70 @@
+package app
+
+import (
+	"golang-clean-architecture/domain"
+	"golang-clean-architecture/infrastructure"
+	"golang-clean-architecture/repository"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Repositories are where the application keeps its data. New decorates them
+// with metrics, tracing and caching, so they are given here undecorated.
+// TaskSearch is left nil for backends without full-text search of their own;
+// New then searches an in-process index of Tasks instead.
+type Repositories struct {
+	Users         domain.UserRepository
+	Tasks         domain.TaskRepository
+	TaskSearch    domain.TaskSearcher
+	TwoFactor     domain.TwoFactorRepository
+	OneTimeTokens domain.OneTimeTokenRepository
+	APITokens     domain.APITokenRepository
+	Webhooks      domain.WebhookRepository
+	LoginAttempts domain.LoginAttemptStore
+}
+
+// NewMongoRepositories creates the indexes the repositories rely on and
+// returns them over db. Login attempts are kept in memory unless
+// LOGIN_ATTEMPT_STORE is "mongo".
+func NewMongoRepositories(db *mongo.Database, config Config, logger *slog.Logger) (Repositories, error) {
+	err := repository.EnsureUserIndexes(db, "users", logger)
+	if err != nil {
+		return Repositories{}, err
+	}
+	err = repository.EnsureTaskTextIndex(db, "tasks")
+	if err != nil {
+		return Repositories{}, err
+	}
+	err = repository.EnsureOneTimeTokenIndexes(db, "tokens")
+	if err != nil {
+		return Repositories{}, err
+	}
+	err = repository.EnsureAPITokenIndexes(db, "api_tokens")
+	if err != nil {
+		return Repositories{}, err
+	}
+
+	var loginAttempts domain.LoginAttemptStore
+	switch infrastructure.GetEnv("LOGIN_ATTEMPT_STORE", "memory") {
+	case "mongo":
+		err = repository.EnsureLoginAttemptIndexes(db, "login_attempts")
+		if err != nil {
+			return Repositories{}, err
+		}
+		loginAttempts = repository.NewLoginAttemptRepository(db, "login_attempts")
+	default:
+		loginAttempts = infrastructure.NewMemoryLoginAttemptStore(config.LoginGuard.Window + config.LoginGuard.Lockout)
+	}
+
+	return Repositories{
+		Users:         repository.NewUserRepository(db, "users"),
+		Tasks:         repository.NewTaskRepository(db, "tasks", logger),
+		TaskSearch:    repository.NewTaskSearchRepository(db, "tasks"),
+		TwoFactor:     repository.NewTwoFactorRepository(db, "users"),
+		OneTimeTokens: repository.NewOneTimeTokenRepository(db, "tokens"),
+		APITokens:     repository.NewAPITokenRepository(db, "api_tokens"),
+		Webhooks:      repository.NewWebhookRepository(db, "webhooks", "webhook_deliveries"),
+		LoginAttempts: loginAttempts,
+	}, nil
+}
@@ -0,0 +1,86 @@
+package app
+
+import (
+	"golang-clean-architecture/domain"
+	"golang-clean-architecture/infrastructure"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Services are the dependencies that aren't repositories: the ones reaching
+// outside the process, and the stores and settings shared by the use cases.
+type Services struct {
+	Mailer        domain.Mailer
+	Notifier      domain.Notifier
+	WebhookSender domain.WebhookSender
+	Tokens        domain.TokenService
+	Keys          *infrastructure.KeyRing
+	Hasher        domain.PasswordHasher
+	Policy        domain.PasswordPolicy
+	RateLimits    domain.RateLimitStore
+	// TaskCache is nil when tasks aren't cached.
+	TaskCache domain.Cache
+	Logger    *slog.Logger
+	Metrics   *infrastructure.Metrics
+	Tracing   *infrastructure.Tracing
+}
+
+// LoadServices builds the services selected by the environment.
+func LoadServices(logger *slog.Logger, metrics *infrastructure.Metrics, tracing *infrastructure.Tracing) (Services, error) {
+	jwtConfig, err := infrastructure.LoadJWTConfig()
+	if err != nil {
+		return Services{}, err
+	}
+	taskCache, err := infrastructure.LoadTaskCache()
+	if err != nil {
+		return Services{}, err
+	}
+	return Services{
+		Mailer:        newMailer(),
+		Notifier:      newNotifier(),
+		WebhookSender: infrastructure.NewHTTPWebhookSender(),
+		Tokens:        infrastructure.NewJWTService(jwtConfig),
+		Keys:          jwtConfig.Keys,
+		Hasher:        infrastructure.NewPasswordHasher(infrastructure.LoadArgon2Params()),
+		Policy:        infrastructure.NewPasswordPolicy(infrastructure.LoadPasswordRules()),
+		RateLimits:    infrastructure.NewMemoryRateLimitStore(),
+		TaskCache:     taskCache,
+		Logger:        logger,
+		Metrics:       metrics,
+		Tracing:       tracing,
+	}, nil
+}
+
+func newNotifier() domain.Notifier {
+	switch infrastructure.GetEnv("REMINDER_NOTIFIER", "log") {
+	case "webhook":
+		return infrastructure.NewWebhookNotifier(os.Getenv("REMINDER_WEBHOOK_URL"))
+	case "smtp":
+		return &infrastructure.SMTPNotifier{
+			Addr:     os.Getenv("SMTP_ADDR"),
+			From:     os.Getenv("SMTP_FROM"),
+			To:       strings.Split(os.Getenv("REMINDER_SMTP_TO"), ","),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+		}
+	default:
+		return infrastructure.LogNotifier{}
+	}
+}
+
+func newMailer() domain.Mailer {
+	switch infrastructure.GetEnv("MAILER", "log") {
+	case "file":
+		return &infrastructure.FileMailer{Dir: infrastructure.GetEnv("MAIL_DIR", "mail")}
+	case "smtp":
+		return &infrastructure.SMTPMailer{
+			Addr:     os.Getenv("SMTP_ADDR"),
+			From:     os.Getenv("SMTP_FROM"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+		}
+	default:
+		return infrastructure.LogMailer{}
+	}
+}
@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"golang-clean-architecture/delivery/dto"
+	"golang-clean-architecture/domain"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type APITokenController struct {
+	APITokenUseCase domain.APITokenUseCase
+}
+
+func apiTokenErrorStatus(err error) int {
+	switch err.Error() {
+	case "internal server error", "error while trying to insert data", "error while fetching api tokens":
+		return http.StatusInternalServerError
+	case "user not found", "api token not found":
+		return http.StatusNotFound
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+func (ac *APITokenController) CreateToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		AuthUser, ok := c.Get("AuthorizedUser")
+		if !ok {
+			respondError(c, http.StatusForbidden, "You are not Authenticated to perform this task")
+			return
+		}
+
+		var request dto.APITokenRequest
+		if err := c.BindJSON(&request); err != nil {
+			respondError(c, http.StatusBadRequest, "invalid input format")
+			return
+		}
+
+		token := request.ToDomain()
+		secret, err := ac.APITokenUseCase.CreateToken(c.Request.Context(), AuthUser.(*domain.AuthenticatedUser).Email, &token)
+		if err != nil {
+			respondError(c, apiTokenErrorStatus(err), err.Error())
+			return
+		}
+		response := dto.NewAPITokenResponse(token)
+		response.Token = secret
+		respondCreated(c, response.ID, response)
+	}
+}
+
+func (ac *APITokenController) GetTokens() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		AuthUser, ok := c.Get("AuthorizedUser")
+		if !ok {
+			respondError(c, http.StatusForbidden, "You are not Authenticated to perform this task")
+			return
+		}
+
+		tokens, err := ac.APITokenUseCase.GetTokens(c.Request.Context(), AuthUser.(*domain.AuthenticatedUser).Email)
+		if err != nil {
+			respondError(c, apiTokenErrorStatus(err), err.Error())
+			return
+		}
+		c.IndentedJSON(http.StatusOK, dto.NewAPITokenResponses(tokens))
+	}
+}
+
+func (ac *APITokenController) RevokeToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		AuthUser, ok := c.Get("AuthorizedUser")
+		if !ok {
+			respondError(c, http.StatusForbidden, "You are not Authenticated to perform this task")
+			return
+		}
+
+		err := ac.APITokenUseCase.RevokeToken(c.Request.Context(), AuthUser.(*domain.AuthenticatedUser).Email, c.Param("id"))
+		if err != nil {
+			respondError(c, apiTokenErrorStatus(err), err.Error())
+			return
+		}
+		respondMessage(c, "api token revoked")
+	}
+}
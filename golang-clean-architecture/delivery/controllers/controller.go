@@ -1,14 +1,23 @@
 package controllers
 
 import (
-	"fmt"
+	"errors"
+	"math"
+	"golang-clean-architecture/delivery/dto"
 	"golang-clean-architecture/domain"
+	"golang-clean-architecture/infrastructure"
 	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
 	"github.com/gin-gonic/gin"
 )
 
 type UserController struct {
 	UserUseCase		domain.UserUseCase
+	VerificationUseCase	domain.EmailVerificationUseCase
+	TwoFactorUseCase	domain.TwoFactorUseCase
 }
 
 type TaskController struct {
@@ -18,38 +27,309 @@ type TaskController struct {
 func (uc *UserController) Register() gin.HandlerFunc {
 
 	return func(c *gin.Context) {
-		var newUser domain.User
-		if err := c.BindJSON(&newUser); err != nil {
-			c.IndentedJSON(http.StatusBadRequest, gin.H{"error":"invalid signup format"})
+		var request dto.RegisterRequest
+		if err := c.BindJSON(&request); err != nil {
+			respondError(c, http.StatusBadRequest, "invalid signup format")
 			return
 		}
 		
-		err := uc.UserUseCase.Register(&newUser)
+		user := request.ToDomain()
+		err := uc.UserUseCase.Register(c.Request.Context(), user)
+		if respondPasswordPolicyError(c, err) {
+			return
+		}
+		if err != nil && err.Error() == "invalid email address" {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
 		if err != nil {
-			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+			respondError(c, http.StatusInternalServerError, err.Error())
 			return
 		}
 
-		c.IndentedJSON(http.StatusOK, gin.H{"message" : "User registered Successfully"})
+		// the account is read back through /me once signed in
+		c.Header("Location", path.Join(path.Dir(c.Request.URL.Path), "me"))
+		c.IndentedJSON(http.StatusCreated, dto.NewUserResponse(*user))
 	}
 }
 
 func (uc *UserController) Login() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var user domain.User
-		if err := c.BindJSON(&user); err != nil {
-			c.IndentedJSON(http.StatusBadRequest, gin.H{"message": "invalid user format"})
+		var request dto.LoginRequest
+		if err := c.BindJSON(&request); err != nil {
+			respondError(c, http.StatusBadRequest, "invalid user format")
 			return
 		}
 	
-		token, err := uc.UserUseCase.Login(&user)
+		result, err := uc.UserUseCase.Login(c.Request.Context(), request.ToDomain(), c.ClientIP())
+		var retryErr *domain.RetryError
+		if errors.As(err, &retryErr) {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryErr.RetryAfter.Seconds()))))
+			respondError(c, http.StatusTooManyRequests, err.Error())
+			return
+		}
+		if err != nil {
+			status := loginErrorStatus(err)
+			if status == http.StatusInternalServerError {
+				infrastructure.LoggerFrom(c).Error("error while logging in", "error", err)
+				respondError(c, status, "internal server error")
+				return
+			}
+			respondError(c, status, err.Error())
+			return
+		}
+
+		if result.TwoFactorRequired {
+			c.IndentedJSON(http.StatusOK, dto.TwoFactorChallengeResponse{TwoFactorRequired: true, ChallengeToken: result.ChallengeToken})
+			return
+		}
+		c.IndentedJSON(http.StatusOK, dto.NewTokenResponse(result.Token, time.Now()))
+	}
+}
+
+// loginErrorStatus maps the errors of a login that wasn't throttled; a
+// locked account is answered with 429 and Retry-After before this.
+func loginErrorStatus(err error) int {
+	switch err.Error() {
+	case "required fields are missing":
+		return http.StatusBadRequest
+	case "invalid credentials":
+		return http.StatusUnauthorized
+	case "email address not verified":
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (uc *UserController) LoginTwoFactor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request dto.TwoFactorLoginRequest
+		if err := c.BindJSON(&request); err != nil {
+			respondError(c, http.StatusBadRequest, "invalid input format")
+			return
+		}
+
+		token, err := uc.UserUseCase.CompleteTwoFactorLogin(c.Request.Context(), request.ChallengeToken, request.Code, c.ClientIP())
+		if err != nil {
+			switch err.Error() {
+			case "required field missing":
+				respondError(c, http.StatusBadRequest, err.Error())
+			case "invalid or expired challenge", "invalid two-factor code":
+				respondError(c, http.StatusUnauthorized, err.Error())
+			default:
+				respondError(c, http.StatusInternalServerError, "internal server error")
+			}
+			return
+		}
+		c.IndentedJSON(http.StatusOK, dto.NewTokenResponse(token, time.Now()))
+	}
+}
+
+func (uc *UserController) UnlockAccount() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isAdmin(c) {
+			respondError(c, http.StatusForbidden, "You are not authorized to unlock accounts")
+			return
+		}
+
+		err := uc.UserUseCase.UnlockAccount(c.Request.Context(), c.Param("id"))
+		if err != nil {
+			switch err.Error() {
+			case "invalid user ID":
+				respondError(c, http.StatusBadRequest, err.Error())
+			case "no user with the specified id found":
+				respondError(c, http.StatusNotFound, err.Error())
+			default:
+				respondError(c, http.StatusInternalServerError, "internal server error")
+			}
+			return
+		}
+		respondMessage(c, "account unlocked")
+	}
+}
+
+func (uc *UserController) VerifyEmail() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := uc.VerificationUseCase.VerifyEmail(c.Request.Context(), c.Query("token"))
+		if err != nil {
+			if err.Error() == "internal server error" {
+				respondError(c, http.StatusInternalServerError, err.Error())
+				return
+			}
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondMessage(c, "email address verified")
+	}
+}
+
+func (uc *UserController) ResendVerification() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request dto.EmailRequest
+		if err := c.BindJSON(&request); err != nil {
+			respondError(c, http.StatusBadRequest, "invalid input format")
+			return
+		}
+
+		err := uc.VerificationUseCase.ResendVerification(c.Request.Context(), request.Email)
+		if err != nil {
+			if err.Error() == "internal server error" {
+				respondError(c, http.StatusInternalServerError, err.Error())
+				return
+			}
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondMessage(c, "if the account exists and is not verified yet, a new link has been sent")
+	}
+}
+
+// respondPasswordPolicyError answers with every rule a refused password
+// broke. It reports false, without responding, for any other error.
+func respondPasswordPolicyError(c *gin.Context, err error) bool {
+	var policyErr *domain.PasswordPolicyError
+	if !errors.As(err, &policyErr) {
+		return false
+	}
+	c.IndentedJSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error(), Violations: policyErr.Violations})
+	return true
+}
+
+// respondError answers with the error body every handler uses.
+func respondError(c *gin.Context, status int, message string) {
+	c.IndentedJSON(status, dto.ErrorResponse{Error: message})
+}
+
+func respondMessage(c *gin.Context, message string) {
+	c.IndentedJSON(http.StatusOK, dto.MessageResponse{Message: message})
+}
+
+// respondCreated answers with a resource that was just created in the
+// collection at the request's path.
+func respondCreated(c *gin.Context, id string, resource interface{}) {
+	c.Header("Location", c.Request.URL.Path+"/"+id)
+	c.IndentedJSON(http.StatusCreated, resource)
+}
+
+func profileErrorStatus(err error) int {
+	switch err.Error() {
+	case "internal server error":
+		return http.StatusInternalServerError
+	case "user not found":
+		return http.StatusNotFound
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+func (uc *UserController) Me() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		AuthUser, ok := c.Get("AuthorizedUser")
+		if !ok {
+			respondError(c, http.StatusForbidden, "You are not Authenticated to perform this task")
+			return
+		}
+
+		user, err := uc.UserUseCase.GetCurrentUser(c.Request.Context(), AuthUser.(*domain.AuthenticatedUser).Email)
+		if err != nil {
+			respondError(c, profileErrorStatus(err), err.Error())
+			return
+		}
+		c.IndentedJSON(http.StatusOK, dto.NewUserResponse(user))
+	}
+}
+
+func (uc *UserController) UpdateProfile() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		AuthUser, ok := c.Get("AuthorizedUser")
+		if !ok {
+			respondError(c, http.StatusForbidden, "You are not Authenticated to perform this task")
+			return
+		}
+
+		var request dto.ProfileUpdateRequest
+		if err := c.BindJSON(&request); err != nil {
+			respondError(c, http.StatusBadRequest, "invalid input format")
+			return
+		}
+
+		user, err := uc.UserUseCase.UpdateProfile(c.Request.Context(), AuthUser.(*domain.AuthenticatedUser).Email, request.ToDomain())
+		if err != nil {
+			respondError(c, profileErrorStatus(err), err.Error())
+			return
+		}
+		c.IndentedJSON(http.StatusOK, dto.NewUserResponse(user))
+	}
+}
+
+func (uc *UserController) ChangePassword() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		AuthUser, ok := c.Get("AuthorizedUser")
+		if !ok {
+			respondError(c, http.StatusForbidden, "You are not Authenticated to perform this task")
+			return
+		}
+
+		var request dto.ChangePasswordRequest
+		if err := c.BindJSON(&request); err != nil {
+			respondError(c, http.StatusBadRequest, "invalid input format")
+			return
+		}
+
+		token, err := uc.UserUseCase.ChangePassword(c.Request.Context(), AuthUser.(*domain.AuthenticatedUser).Email, request.CurrentPassword, request.NewPassword)
+		if respondPasswordPolicyError(c, err) {
+			return
+		}
 		if err != nil {
-			fmt.Println(err.Error())
-			c.IndentedJSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			respondError(c, profileErrorStatus(err), err.Error())
 			return
 		}
+		// the token the request was made with has just been revoked
+		c.IndentedJSON(http.StatusOK, dto.NewTokenResponse(token, time.Now()))
+	}
+}
 
-		c.IndentedJSON(http.StatusOK, gin.H{"message" : fmt.Sprintf("logged in successfully, here is your token: %v", token)})
+func (uc *UserController) EnrollTwoFactor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		AuthUser, ok := c.Get("AuthorizedUser")
+		if !ok {
+			respondError(c, http.StatusForbidden, "You are not Authenticated to perform this task")
+			return
+		}
+
+		enrollment, err := uc.TwoFactorUseCase.BeginEnrollment(c.Request.Context(), AuthUser.(*domain.AuthenticatedUser).Email)
+		if err != nil {
+			respondError(c, profileErrorStatus(err), err.Error())
+			return
+		}
+		c.IndentedJSON(http.StatusOK, dto.NewTwoFactorEnrollmentResponse(enrollment))
+	}
+}
+
+func (uc *UserController) ConfirmTwoFactor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		AuthUser, ok := c.Get("AuthorizedUser")
+		if !ok {
+			respondError(c, http.StatusForbidden, "You are not Authenticated to perform this task")
+			return
+		}
+
+		var request dto.TwoFactorCodeRequest
+		if err := c.BindJSON(&request); err != nil {
+			respondError(c, http.StatusBadRequest, "invalid input format")
+			return
+		}
+
+		codes, err := uc.TwoFactorUseCase.ConfirmEnrollment(c.Request.Context(), AuthUser.(*domain.AuthenticatedUser).Email, request.Code)
+		if err != nil {
+			respondError(c, profileErrorStatus(err), err.Error())
+			return
+		}
+		c.IndentedJSON(http.StatusOK, dto.TwoFactorConfirmationResponse{
+			Message : "two-factor authentication enabled, sign in again to use it. Store the recovery codes somewhere safe, they won't be shown again",
+			RecoveryCodes : codes,
+		})
 	}
 }
 
@@ -58,40 +338,40 @@ func (uc *UserController) PromoteUser() gin.HandlerFunc {
 
 		AuthUser, ok := c.Get("AuthorizedUser")
 		if !ok {
-			c.IndentedJSON(http.StatusNotFound, gin.H{"error":"Authorization error"})
+			respondError(c, http.StatusNotFound, "Authorization error")
 			return
 		}
 
 		AuthorizedUser := AuthUser.(*domain.AuthenticatedUser)
 
 		if AuthorizedUser.Role != "admin" {
-			c.IndentedJSON(http.StatusForbidden, gin.H{"error":"You are not authorized to promote another user"})
+			respondError(c, http.StatusForbidden, "You are not authorized to promote another user")
 			return
 		}
 
 		userID := c.Param("id")
-		err := uc.UserUseCase.PromoteUser(userID)
+		err := uc.UserUseCase.PromoteUser(c.Request.Context(), userID)
 
 		if err != nil {
 
 			if err.Error() == "invalid user ID" {
-				c.IndentedJSON(http.StatusBadRequest, gin.H{"error" : err.Error()})
+				respondError(c, http.StatusBadRequest, err.Error())
 				return 
 			}
 			if err.Error() == "no user with the specified id found" {
-				c.IndentedJSON(http.StatusBadRequest, gin.H{"error" : err.Error()})
+				respondError(c, http.StatusBadRequest, err.Error())
 				return
 			}
 			if err.Error() == "user is already an admin" {
-				c.IndentedJSON(http.StatusBadRequest, gin.H{"error" : err.Error()})
+				respondError(c, http.StatusBadRequest, err.Error())
 				return
 			}
 			if err.Error() == "internal server error" {
-				c.IndentedJSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+				respondError(c, http.StatusInternalServerError, err.Error())
 				return
 			}
 		}
-		c.IndentedJSON(http.StatusOK, gin.H{"message" : "user with the given ID promoted to admin"})
+		respondMessage(c, "user with the given ID promoted to admin")
 	}
 }
 
@@ -99,28 +379,59 @@ func (tc *TaskController) GetTasks() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		_, ok := c.Get("AuthorizedUser")
 		if !ok  {
-			c.IndentedJSON(http.StatusForbidden, gin.H{"error" : "You are not Authenticated to perform this task"})
+			respondError(c, http.StatusForbidden, "You are not Authenticated to perform this task")
 			return
 		}
 
-		tasks, err := tc.TaskUseCase.GetTasks()
+		tasks, err := tc.TaskUseCase.GetTasks(c.Request.Context())
 		if err != nil {
-			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error" : "internal server error"})
+			respondError(c, http.StatusInternalServerError, "internal server error")
 			return
 		}
-		c.IndentedJSON(http.StatusOK, tasks)
+		c.IndentedJSON(http.StatusOK, dto.NewTaskResponses(tasks))
+	}
+}
+
+func (tc *TaskController) SearchTasks() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, ok := c.Get("AuthorizedUser")
+		if !ok  {
+			respondError(c, http.StatusForbidden, "You are not Authenticated to perform this task")
+			return
+		}
+
+		limit := 0
+		if rawLimit := c.Query("limit"); rawLimit != "" {
+			parsedLimit, err := strconv.Atoi(rawLimit)
+			if err != nil {
+				respondError(c, http.StatusBadRequest, "invalid limit")
+				return
+			}
+			limit = parsedLimit
+		}
+
+		results, err := tc.TaskUseCase.SearchTasks(c.Request.Context(), c.Query("q"), limit)
+		if err != nil {
+			if err.Error() == "search query is required" {
+				respondError(c, http.StatusBadRequest, err.Error())
+				return
+			}
+			respondError(c, http.StatusInternalServerError, "internal server error")
+			return
+		}
+		c.IndentedJSON(http.StatusOK, dto.NewTaskSearchResultResponses(results))
 	}
 }
 
 func (tc *TaskController) GetTask() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		task_id := c.Param("id")
-		task, err := tc.TaskUseCase.GetTask(task_id)
+		task, err := tc.TaskUseCase.GetTask(c.Request.Context(), task_id)
 		if err!=nil {
-			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+			respondError(c, http.StatusInternalServerError, err.Error())
 			return
 		}
-		c.IndentedJSON(http.StatusOK, task)
+		c.IndentedJSON(http.StatusOK, dto.NewTaskResponse(&task))
 	}
 }
 
@@ -128,60 +439,69 @@ func (tc *TaskController) PostTask() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		AuthUser, ok := c.Get("AuthorizedUser")
 		if !ok {
-			c.IndentedJSON(http.StatusNotFound, gin.H{"error":"Authorization error"})
+			respondError(c, http.StatusNotFound, "Authorization error")
 			return
 		}
 
 		AuthorizedUser := AuthUser.(*domain.AuthenticatedUser)
 		
 		if AuthorizedUser.Role != "admin" {
-			c.IndentedJSON(http.StatusForbidden, gin.H{"error":"You are not authorized to post a task"})
+			respondError(c, http.StatusForbidden, "You are not authorized to post a task")
 			return
 		}
 
-		var task domain.Task
-		if err := c.BindJSON(&task); err != nil {
-			c.IndentedJSON(http.StatusBadRequest, gin.H{"error" : "invalid input format"})
+		var request dto.TaskRequest
+		if err := c.BindJSON(&request); err != nil {
+			respondError(c, http.StatusBadRequest, "invalid input format")
 			return
 		}
 
-		err := tc.TaskUseCase.PostTask(task)
+		task := request.ToDomain()
+		err := tc.TaskUseCase.PostTask(c.Request.Context(), &task)
 		if err != nil {
-			if err.Error() == "error while trying to insert data" {
-				c.IndentedJSON(http.StatusInternalServerError, gin.H{"error" : "internal server error"})
+			if isInvalidTaskError(err) {
+				respondError(c, http.StatusBadRequest, err.Error())
 				return
-			} else {
-				c.IndentedJSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
-				return	
 			}
+			infrastructure.LoggerFrom(c).Error("error while posting task", "error", err)
+			respondError(c, http.StatusInternalServerError, "internal server error")
+			return
 		}
-		c.IndentedJSON(http.StatusOK, gin.H{"message" : "task added successfully"})
+		respondCreated(c, task.ID.Hex(), dto.NewTaskResponse(&task))
 	}
 }
 
+// isInvalidTaskError reports whether the task use case refused a task or a
+// recurrence rule given by the client.
+func isInvalidTaskError(err error) bool {
+	return err.Error() == "required fields are missing" ||
+		err.Error() == "recurring tasks require a due date" ||
+		strings.HasPrefix(err.Error(), "invalid recurrence rule")
+}
+
 func (tc *TaskController) DeleteTask() gin.HandlerFunc {
 	return func(c *gin.Context) {
 
 		AuthUser, ok := c.Get("AuthorizedUser")
 		if !ok {
-			c.IndentedJSON(http.StatusNotFound, gin.H{"error":"Authorization error"})
+			respondError(c, http.StatusNotFound, "Authorization error")
 			return
 		}
 
 		AuthorizedUser := AuthUser.(*domain.AuthenticatedUser)
 		
 		if AuthorizedUser.Role != "admin" {
-			c.IndentedJSON(http.StatusForbidden, gin.H{"error":"You are not authorized to delete a task"})
+			respondError(c, http.StatusForbidden, "You are not authorized to delete a task")
 			return
 		}
 
 		task_id := c.Param("id")
-		err := tc.TaskUseCase.DeleteTask(task_id)
+		err := tc.TaskUseCase.DeleteTask(c.Request.Context(), task_id)
 		if err!=nil {
-			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+			respondError(c, http.StatusInternalServerError, err.Error())
 			return
 		}
-		c.IndentedJSON(http.StatusOK, gin.H{"message" : "task deleted successfully"})
+		respondMessage(c, "task deleted successfully")
 	}
 }
 
@@ -190,34 +510,99 @@ func (tc *TaskController) UpdateTask() gin.HandlerFunc{
 
 		AuthUser, ok := c.Get("AuthorizedUser")
 		if !ok {
-			c.IndentedJSON(http.StatusNotFound, gin.H{"error":"Authorization error"})
+			respondError(c, http.StatusNotFound, "Authorization error")
 			return
 		}
 
 		AuthorizedUser := AuthUser.(*domain.AuthenticatedUser)
 		
 		if AuthorizedUser.Role != "admin" {
-			c.IndentedJSON(http.StatusForbidden, gin.H{"error":"You are not authorized to update a task"})
+			respondError(c, http.StatusForbidden, "You are not authorized to update a task")
 			return
 		}
 
 		task_id := c.Param("id")
-		var updatedTask domain.Task
-		if err := c.BindJSON(&updatedTask); err != nil {
-			c.IndentedJSON(http.StatusBadRequest, gin.H{"error" : "invalid input format"})
+		var request dto.TaskRequest
+		if err := c.BindJSON(&request); err != nil {
+			respondError(c, http.StatusBadRequest, "invalid input format")
 			return
 		}
+		updatedTask := request.ToDomain()
 
-		err := tc.TaskUseCase.UpdateTask(task_id, &updatedTask)
+		err := tc.TaskUseCase.UpdateTask(c.Request.Context(), task_id, &updatedTask)
 		if err != nil {
 			if err.Error() == "error while trying to delete data" {
-				c.IndentedJSON(http.StatusInternalServerError, gin.H{"error" : "internal server error"})
+				respondError(c, http.StatusInternalServerError, "internal server error")
 				return
 			} else {
-				c.IndentedJSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+				respondError(c, http.StatusInternalServerError, err.Error())
 				return	
 			}
 		}
-		c.IndentedJSON(http.StatusOK, gin.H{"message" : "task updated successfully"})
+		respondMessage(c, "task updated successfully")
+	}
+}
+
+func (tc *TaskController) UpdateRecurrence() gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		AuthUser, ok := c.Get("AuthorizedUser")
+		if !ok {
+			respondError(c, http.StatusNotFound, "Authorization error")
+			return
+		}
+
+		AuthorizedUser := AuthUser.(*domain.AuthenticatedUser)
+
+		if AuthorizedUser.Role != "admin" {
+			respondError(c, http.StatusForbidden, "You are not authorized to update a task")
+			return
+		}
+
+		var body dto.RecurrenceRequest
+		if err := c.BindJSON(&body); err != nil {
+			respondError(c, http.StatusBadRequest, "invalid input format")
+			return
+		}
+
+		err := tc.TaskUseCase.UpdateRecurrence(c.Request.Context(), c.Param("id"), body.Recurrence)
+		if err != nil {
+			if isInvalidTaskError(err) {
+				respondError(c, http.StatusBadRequest, err.Error())
+				return
+			}
+			respondError(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+		respondMessage(c, "recurrence updated successfully")
+	}
+}
+
+func (tc *TaskController) StopRecurrence() gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		AuthUser, ok := c.Get("AuthorizedUser")
+		if !ok {
+			respondError(c, http.StatusNotFound, "Authorization error")
+			return
+		}
+
+		AuthorizedUser := AuthUser.(*domain.AuthenticatedUser)
+
+		if AuthorizedUser.Role != "admin" {
+			respondError(c, http.StatusForbidden, "You are not authorized to update a task")
+			return
+		}
+
+		err := tc.TaskUseCase.StopRecurrence(c.Request.Context(), c.Param("id"))
+		if err != nil {
+			if err.Error() == "task is not part of a recurring series" {
+				respondError(c, http.StatusBadRequest, err.Error())
+				return
+			}
+			respondError(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+		respondMessage(c, "recurrence stopped successfully")
 	}
-}
\ No newline at end of file
+}
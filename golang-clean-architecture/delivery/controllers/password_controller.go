@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"golang-clean-architecture/delivery/dto"
+	"golang-clean-architecture/domain"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PasswordController struct {
+	PasswordResetUseCase domain.PasswordResetUseCase
+}
+
+func (pc *PasswordController) ForgotPassword() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request dto.EmailRequest
+		if err := c.BindJSON(&request); err != nil {
+			respondError(c, http.StatusBadRequest, "invalid input format")
+			return
+		}
+
+		err := pc.PasswordResetUseCase.ForgotPassword(c.Request.Context(), request.Email)
+		if err != nil {
+			if err.Error() == "internal server error" {
+				respondError(c, http.StatusInternalServerError, "internal server error")
+				return
+			}
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondMessage(c, "if an account with that email exists, a reset link has been sent to it")
+	}
+}
+
+func (pc *PasswordController) ResetPassword() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request dto.ResetPasswordRequest
+		if err := c.BindJSON(&request); err != nil {
+			respondError(c, http.StatusBadRequest, "invalid input format")
+			return
+		}
+
+		err := pc.PasswordResetUseCase.ResetPassword(c.Request.Context(), request.Token, request.Password)
+		if respondPasswordPolicyError(c, err) {
+			return
+		}
+		if err != nil {
+			if err.Error() == "internal server error" {
+				respondError(c, http.StatusInternalServerError, "internal server error")
+				return
+			}
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondMessage(c, "password has been reset, please log in again")
+	}
+}
@@ -0,0 +1,170 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"golang-clean-architecture/domain"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+type StreamController struct {
+	Events    domain.EventStream
+	Heartbeat time.Duration
+}
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+}
+
+// StreamTasks pushes task events to the client as they happen, over
+// Server-Sent Events or, when the request asks for an upgrade, a WebSocket.
+// Clients resume after a disconnect by sending the id of the last event they
+// received in Last-Event-ID (or ?last_event_id= for WebSockets); a "reset"
+// event tells them that the gap could not be filled and they should refetch.
+func (sc *StreamController) StreamTasks() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		AuthUser, ok := c.Get("AuthorizedUser")
+		if !ok {
+			respondError(c, http.StatusForbidden, "You are not Authenticated to perform this task")
+			return
+		}
+		user := AuthUser.(*domain.AuthenticatedUser)
+
+		lastEventID := c.GetHeader("Last-Event-ID")
+		if lastEventID == "" {
+			lastEventID = c.Query("last_event_id")
+		}
+
+		if websocket.IsWebSocketUpgrade(c.Request) {
+			sc.streamWebSocket(c, user, lastEventID)
+			return
+		}
+		sc.streamSSE(c, user, lastEventID)
+	}
+}
+
+func (sc *StreamController) streamSSE(c *gin.Context, user *domain.AuthenticatedUser, lastEventID string) {
+	subscription := sc.Events.Subscribe(lastEventID)
+	defer subscription.Cancel()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	if subscription.Missed {
+		fmt.Fprint(c.Writer, "event: reset\ndata: {}\n\n")
+	}
+	for _, event := range subscription.Replay {
+		writeSSEEvent(c, user, event)
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(sc.heartbeat())
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-subscription.Events:
+			if !ok {
+				return
+			}
+			writeSSEEvent(c, user, event)
+		case <-heartbeat.C:
+			fmt.Fprint(c.Writer, ": ping\n\n")
+		}
+		c.Writer.Flush()
+	}
+}
+
+func writeSSEEvent(c *gin.Context, user *domain.AuthenticatedUser, event domain.Event) {
+	if !visibleTo(user, event) {
+		return
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(c.Writer, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+}
+
+func (sc *StreamController) streamWebSocket(c *gin.Context, user *domain.AuthenticatedUser, lastEventID string) {
+	connection, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// the upgrader has already written an error response
+		return
+	}
+	defer connection.Close()
+
+	subscription := sc.Events.Subscribe(lastEventID)
+	defer subscription.Cancel()
+
+	// the client never sends anything we act on, but reading is how close
+	// frames and dropped connections are noticed
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := connection.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	if subscription.Missed {
+		if connection.WriteJSON(gin.H{"type": "reset"}) != nil {
+			return
+		}
+	}
+	for _, event := range subscription.Replay {
+		if visibleTo(user, event) && connection.WriteJSON(event) != nil {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(sc.heartbeat())
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-closed:
+			return
+		case event, ok := <-subscription.Events:
+			if !ok {
+				connection.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "resume with last_event_id"))
+				return
+			}
+			if visibleTo(user, event) && connection.WriteJSON(event) != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if connection.WriteControl(websocket.PingMessage, nil, time.Now().Add(10*time.Second)) != nil {
+				return
+			}
+		}
+	}
+}
+
+func (sc *StreamController) heartbeat() time.Duration {
+	if sc.Heartbeat <= 0 {
+		return 15 * time.Second
+	}
+	return sc.Heartbeat
+}
+
+// visibleTo decides which events a user may see on the stream. Every
+// authenticated user can read every task, so task events go to everyone;
+// events about users are only shown to admins.
+func visibleTo(user *domain.AuthenticatedUser, event domain.Event) bool {
+	if strings.HasPrefix(event.Type, "task.") {
+		return true
+	}
+	return user.Role == "admin"
+}
@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"golang-clean-architecture/domain"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookController struct {
+	WebhookUseCase domain.WebhookUseCase
+}
+
+func (wc *WebhookController) CreateSubscription() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isAdmin(c) {
+			respondError(c, http.StatusForbidden, "You are not authorized to manage webhooks")
+			return
+		}
+
+		var subscription domain.WebhookSubscription
+		if err := c.BindJSON(&subscription); err != nil {
+			respondError(c, http.StatusBadRequest, "invalid input format")
+			return
+		}
+
+		err := wc.WebhookUseCase.CreateSubscription(c.Request.Context(), &subscription)
+		if err != nil {
+			if err.Error() == "internal server error" || err.Error() == "error while trying to insert data" {
+				respondError(c, http.StatusInternalServerError, "internal server error")
+				return
+			}
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondCreated(c, subscription.ID.Hex(), subscription)
+	}
+}
+
+func (wc *WebhookController) GetSubscriptions() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isAdmin(c) {
+			respondError(c, http.StatusForbidden, "You are not authorized to manage webhooks")
+			return
+		}
+
+		subscriptions, err := wc.WebhookUseCase.GetSubscriptions(c.Request.Context())
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, "internal server error")
+			return
+		}
+		c.IndentedJSON(http.StatusOK, subscriptions)
+	}
+}
+
+func (wc *WebhookController) DeleteSubscription() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isAdmin(c) {
+			respondError(c, http.StatusForbidden, "You are not authorized to manage webhooks")
+			return
+		}
+
+		err := wc.WebhookUseCase.DeleteSubscription(c.Request.Context(), c.Param("id"))
+		if err != nil {
+			respondError(c, webhookErrorStatus(err), err.Error())
+			return
+		}
+		respondMessage(c, "webhook deleted successfully")
+	}
+}
+
+func (wc *WebhookController) GetDeliveries() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isAdmin(c) {
+			respondError(c, http.StatusForbidden, "You are not authorized to manage webhooks")
+			return
+		}
+
+		deliveries, err := wc.WebhookUseCase.GetDeliveries(c.Request.Context(), c.Param("id"))
+		if err != nil {
+			respondError(c, webhookErrorStatus(err), err.Error())
+			return
+		}
+		c.IndentedJSON(http.StatusOK, deliveries)
+	}
+}
+
+func webhookErrorStatus(err error) int {
+	if err.Error() == "invalid webhook id" {
+		return http.StatusBadRequest
+	}
+	if strings.HasSuffix(err.Error(), "not found") {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+func isAdmin(c *gin.Context) bool {
+	authUser, ok := c.Get("AuthorizedUser")
+	if !ok {
+		return false
+	}
+	return authUser.(*domain.AuthenticatedUser).Role == "admin"
+}
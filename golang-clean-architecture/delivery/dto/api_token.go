@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"golang-clean-architecture/domain"
+	"time"
+)
+
+type APITokenRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+func (r APITokenRequest) ToDomain() domain.APIToken {
+	return domain.APIToken{
+		Name:      r.Name,
+		Scopes:    r.Scopes,
+		ExpiresAt: r.ExpiresAt,
+	}
+}
+
+// APITokenResponse describes a token without its secret. Token is only set
+// in the response to creating one.
+type APITokenResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	Token      string     `json:"token,omitempty"`
+}
+
+func NewAPITokenResponse(token domain.APIToken) APITokenResponse {
+	return APITokenResponse{
+		ID:         token.ID.Hex(),
+		Name:       token.Name,
+		Prefix:     token.Prefix,
+		Scopes:     token.Scopes,
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+		CreatedAt:  token.CreatedAt,
+	}
+}
+
+func NewAPITokenResponses(tokens []*domain.APIToken) []APITokenResponse {
+	responses := make([]APITokenResponse, 0, len(tokens))
+	for _, token := range tokens {
+		responses = append(responses, NewAPITokenResponse(*token))
+	}
+	return responses
+}
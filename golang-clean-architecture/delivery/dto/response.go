@@ -0,0 +1,17 @@
+package dto
+
+import (
+	"golang-clean-architecture/domain"
+)
+
+// ErrorResponse is the body of every error the controllers answer with.
+// Violations is only set when a password was refused, with each rule it
+// broke.
+type ErrorResponse struct {
+	Error      string                     `json:"error"`
+	Violations []domain.PasswordViolation `json:"violations,omitempty"`
+}
+
+type MessageResponse struct {
+	Message string `json:"message"`
+}
@@ -0,0 +1,84 @@
+package dto
+
+import (
+	"golang-clean-architecture/domain"
+	"time"
+)
+
+// TaskRequest is the body of task creation and update requests. Series
+// bookkeeping and reminder state are managed by the server.
+type TaskRequest struct {
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	DueDate     time.Time `json:"due_date"`
+	Status      string    `json:"status"`
+	Comments    []string  `json:"comments,omitempty"`
+	Recurrence  string    `json:"recurrence,omitempty"`
+}
+
+func (r TaskRequest) ToDomain() domain.Task {
+	return domain.Task{
+		Title:       r.Title,
+		Description: r.Description,
+		DueDate:     r.DueDate,
+		Status:      r.Status,
+		Comments:    r.Comments,
+		Recurrence:  r.Recurrence,
+	}
+}
+
+type RecurrenceRequest struct {
+	Recurrence string `json:"recurrence"`
+}
+
+type TaskResponse struct {
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	DueDate     time.Time `json:"due_date"`
+	Status      string    `json:"status"`
+	Comments    []string  `json:"comments,omitempty"`
+	Recurrence  string    `json:"recurrence,omitempty"`
+	SeriesID    string    `json:"series_id,omitempty"`
+	Occurrence  int       `json:"occurrence,omitempty"`
+}
+
+func NewTaskResponse(task *domain.Task) TaskResponse {
+	return TaskResponse{
+		ID:          task.ID.Hex(),
+		Title:       task.Title,
+		Description: task.Description,
+		DueDate:     task.DueDate,
+		Status:      task.Status,
+		Comments:    task.Comments,
+		Recurrence:  task.Recurrence,
+		SeriesID:    task.SeriesID,
+		Occurrence:  task.Occurrence,
+	}
+}
+
+func NewTaskResponses(tasks []*domain.Task) []TaskResponse {
+	responses := make([]TaskResponse, 0, len(tasks))
+	for _, task := range tasks {
+		responses = append(responses, NewTaskResponse(task))
+	}
+	return responses
+}
+
+type TaskSearchResultResponse struct {
+	Task       TaskResponse      `json:"task"`
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights"`
+}
+
+func NewTaskSearchResultResponses(results []*domain.TaskSearchResult) []TaskSearchResultResponse {
+	responses := make([]TaskSearchResultResponse, 0, len(results))
+	for _, result := range results {
+		responses = append(responses, TaskSearchResultResponse{
+			Task:       NewTaskResponse(result.Task),
+			Score:      result.Score,
+			Highlights: result.Highlights,
+		})
+	}
+	return responses
+}
@@ -0,0 +1,135 @@
+// Package dto holds the request and response bodies of the HTTP API. Domain
+// entities double as storage documents, so handlers never bind requests into
+// them or serialise them directly; the mappers here decide which fields cross
+// the API boundary.
+package dto
+
+import (
+	"golang-clean-architecture/domain"
+	"time"
+)
+
+type RegisterRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// ToDomain only copies the credentials, so a client can't choose its own
+// role, id or verification state.
+func (r RegisterRequest) ToDomain() *domain.User {
+	return &domain.User{
+		Email:    r.Email,
+		Password: r.Password,
+	}
+}
+
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+func (r LoginRequest) ToDomain() *domain.User {
+	return &domain.User{
+		Email:    r.Email,
+		Password: r.Password,
+	}
+}
+
+type ProfileUpdateRequest struct {
+	DisplayName *string `json:"display_name"`
+	Timezone    *string `json:"timezone"`
+}
+
+func (r ProfileUpdateRequest) ToDomain() domain.ProfileUpdate {
+	return domain.ProfileUpdate{
+		DisplayName: r.DisplayName,
+		Timezone:    r.Timezone,
+	}
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+type EmailRequest struct {
+	Email string `json:"email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code"`
+}
+
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+}
+
+// TokenResponse is shaped like an OAuth 2.0 access token response (RFC 6749
+// section 5.1), which most HTTP clients already know how to read.
+type TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+func NewTokenResponse(token domain.AccessToken, now time.Time) TokenResponse {
+	expiresIn := int64(token.ExpiresAt.Sub(now) / time.Second)
+	if expiresIn < 0 {
+		expiresIn = 0
+	}
+	return TokenResponse{
+		AccessToken: token.Token,
+		TokenType:   "Bearer",
+		ExpiresIn:   expiresIn,
+	}
+}
+
+// TwoFactorChallengeResponse answers a login when a code is still needed;
+// ChallengeToken is sent to /login/2fa along with it.
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	ChallengeToken    string `json:"challenge_token"`
+}
+
+type TwoFactorConfirmationResponse struct {
+	Message       string   `json:"message"`
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type TwoFactorEnrollmentResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+func NewTwoFactorEnrollmentResponse(enrollment domain.TwoFactorEnrollment) TwoFactorEnrollmentResponse {
+	return TwoFactorEnrollmentResponse{
+		Secret:          enrollment.Secret,
+		ProvisioningURI: enrollment.ProvisioningURI,
+	}
+}
+
+type UserResponse struct {
+	ID          string `json:"id"`
+	Email       string `json:"email"`
+	Role        string `json:"role"`
+	Verified    bool   `json:"verified"`
+	DisplayName string `json:"display_name"`
+	Timezone    string `json:"timezone"`
+}
+
+func NewUserResponse(user domain.User) UserResponse {
+	return UserResponse{
+		ID:          user.ID.Hex(),
+		Email:       user.Email,
+		Role:        user.Role,
+		Verified:    user.Verified,
+		DisplayName: user.DisplayName,
+		Timezone:    user.Timezone,
+	}
+}
@@ -2,29 +2,125 @@ package main
 
 import (
 	"context"
-	"fmt"
-	routers "golang-clean-architecture/delivery/router"
-	"log"
+	"golang-clean-architecture/delivery/app"
+	"golang-clean-architecture/infrastructure"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func main() {
-	clientOptions := options.Client().ApplyURI("mongodb://localhost:27017")
+	logger := infrastructure.LoadLogger()
+	// the standard log package, which gin and the mongo driver write to,
+	// goes through the same handler
+	slog.SetDefault(logger)
+
+	provider, err := infrastructure.LoadTracerProvider(context.Background())
+	if err != nil {
+		fatal(err)
+	}
+	// deferred calls don't run on os.Exit, so fatal and the end of main
+	// flush the buffered spans explicitly
+	flushTraces = func() {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := provider.Shutdown(ctx); err != nil {
+			slog.Error("error while flushing traces", "error", err)
+		}
+	}
+	tracing := infrastructure.NewTracing(provider)
+
+	clientOptions := options.Client().ApplyURI("mongodb://localhost:27017").SetMonitor(tracing.CommandMonitor())
 	client, err := mongo.Connect(context.TODO(), clientOptions)
 	if err != nil {
-		log.Fatal(err)
+		fatal(err)
 	}
 
 	err = client.Ping(context.TODO(), nil)
 	if err != nil {
-		log.Fatal(err)
+		fatal(err)
+	}
+
+	logger.Info("database connected")
+	config := app.LoadConfig()
+	repositories, err := app.NewMongoRepositories(client.Database("task_management"), config, logger)
+	if err != nil {
+		fatal(err)
+	}
+	metrics := infrastructure.NewMetrics()
+	services, err := app.LoadServices(logger, metrics, tracing)
+	if err != nil {
+		fatal(err)
 	}
+	application := app.New(config, repositories, services)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	application.StartWorkers(ctx)
+
+	// gin.Default's logger would write query strings, which can carry
+	// tokens, so requests are logged by RequestIDMiddleWare instead
+	router := gin.New()
+	router.Use(gin.Recovery())
+	serveMetrics(router, metrics, logger)
+	application.Setup(router)
+
+	server := &http.Server{Addr: "localhost:8080", Handler: router}
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			fatal(err)
+		}
+	case <-ctx.Done():
+		logger.Info("shutting down")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logger.Error("error while shutting down the server", "error", err)
+		}
+	}
+	flushTraces()
+}
+
+// shutdownTimeout bounds how long in-flight requests and span exports may
+// hold up the exit.
+const shutdownTimeout = 10 * time.Second
+
+// flushTraces shuts the tracer provider down once it has been loaded.
+var flushTraces = func() {}
+
+// serveMetrics exposes /metrics on the API router, or on METRICS_ADDR when
+// it is set so that the metrics can be kept off the public port.
+func serveMetrics(router *gin.Engine, metrics *infrastructure.Metrics, logger *slog.Logger) {
+	addr := infrastructure.GetEnv("METRICS_ADDR", "")
+	if addr == "" {
+		router.GET("/metrics", gin.WrapH(metrics.Handler()))
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	go func() {
+		err := http.ListenAndServe(addr, mux)
+		logger.Error("metrics server stopped", "addr", addr, "error", err)
+	}()
+}
 
-	fmt.Println("Database Connected")
-	db := client.Database("task_management")
-	router := gin.Default()
-	routers.Setup(db, router)
-	router.Run("localhost:8080")
-}
\ No newline at end of file
+// fatal logs an error that keeps the server from starting, flushes the
+// spans recorded so far and exits.
+func fatal(err error) {
+	slog.Error("error while starting the server", "error", err)
+	flushTraces()
+	os.Exit(1)
+}
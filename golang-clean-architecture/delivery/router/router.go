@@ -2,65 +2,232 @@ package router
 
 import (
 	"golang-clean-architecture/delivery/controllers"
+	"golang-clean-architecture/delivery/docs"
+	"golang-clean-architecture/domain"
 	"golang-clean-architecture/infrastructure"
-	"golang-clean-architecture/repository"
-	usecase "golang-clean-architecture/use_cases"
+	"log/slog"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func Setup(db *mongo.Database, router *gin.Engine) {
-	publicRouter := router.Group("")
+// the unversioned routes were deprecated when /api/v1 was introduced, and
+// are kept for six months by default
+var (
+	unversionedAPIDeprecated = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	unversionedAPISunset     = unversionedAPIDeprecated.AddDate(0, 6, 0)
+)
+
+// Dependencies are the use cases and services the routes are served with.
+// They are built once, by the app package, so the router doesn't know where
+// the data is stored.
+type Dependencies struct {
+	Tasks         domain.TaskUseCase
+	Users         domain.UserUseCase
+	Verification  domain.EmailVerificationUseCase
+	TwoFactor     domain.TwoFactorUseCase
+	PasswordReset domain.PasswordResetUseCase
+	APITokens     domain.APITokenUseCase
+	Webhooks      domain.WebhookUseCase
+	Events        domain.EventStream
+	// Sessions is read to reject tokens of revoked sessions
+	Sessions   domain.UserRepository
+	Tokens     domain.TokenService
+	Keys       *infrastructure.KeyRing
+	RateLimits domain.RateLimitStore
+	Logger     *slog.Logger
+	Metrics    *infrastructure.Metrics
+	Tracing    *infrastructure.Tracing
+}
+
+// Config holds the settings of the routes themselves.
+type Config struct {
+	DefaultLimit  domain.RateLimit
+	RegisterLimit domain.RateLimit
+	LoginLimit    domain.RateLimit
+	// RequireAdminTwoFactor keeps admins without 2FA away from everything
+	// but setting it up
+	RequireAdminTwoFactor bool
+	// UnversionedSunset is announced on the deprecated routes without the
+	// /api/v1 prefix
+	UnversionedSunset time.Time
+}
+
+// LoadConfig reads the route settings from the environment.
+func LoadConfig() Config {
+	return Config{
+		DefaultLimit: infrastructure.GetEnvRateLimit("RATE_LIMIT_DEFAULT", domain.RateLimit{Requests: 120, Per: time.Minute}),
+		// signing up and logging in get stricter limits of their own on top
+		// of the default one
+		RegisterLimit:         infrastructure.GetEnvRateLimit("RATE_LIMIT_REGISTER", domain.RateLimit{Requests: 5, Per: 10 * time.Minute}),
+		LoginLimit:            infrastructure.GetEnvRateLimit("RATE_LIMIT_LOGIN", domain.RateLimit{Requests: 10, Per: time.Minute}),
+		RequireAdminTwoFactor: infrastructure.GetEnvBool("REQUIRE_ADMIN_2FA", false),
+		UnversionedSunset:     infrastructure.GetEnvTime("UNVERSIONED_API_SUNSET", unversionedAPISunset),
+	}
+}
+
+// Setup mounts the API on router under /api/v1. The same routes still answer
+// without the prefix, as deprecated aliases, until config.UnversionedSunset.
+func Setup(router *gin.Engine, deps Dependencies, config Config) {
+	router.Use(infrastructure.RequestIDMiddleWare(deps.Logger), deps.Metrics.MiddleWare(), deps.Tracing.MiddleWare())
+	guards := newGuards(deps, config)
+
+	// clients look the keys up at a well-known path, which isn't versioned
+	router.GET("/.well-known/jwks.json", guards.defaultLimit, infrastructure.JWKSHandler(deps.Keys))
+
+	newV1Router(router.Group("/api/v1"), deps, guards)
+	newV1Router(router.Group("", infrastructure.DeprecationMiddleWare(unversionedAPIDeprecated, config.UnversionedSunset, "/api/v1")), deps, guards)
+}
+
+// guards authenticate and throttle requests. Every version of the API uses
+// the same ones, so that a client has one set of limits whichever version
+// it calls.
+type guards struct {
+	defaultLimit  gin.HandlerFunc
+	registerLimit gin.HandlerFunc
+	loginLimit    gin.HandlerFunc
+	apiTokens     gin.HandlerFunc
+	bearerTokens  gin.HandlerFunc
+	sessions      gin.HandlerFunc
+	// requireAdminTwoFactor is checked per version since the middleware
+	// needs to know the prefix of the routes
+	requireAdminTwoFactor bool
+}
+
+func newGuards(deps Dependencies, config Config) guards {
+	limiter := infrastructure.NewRateLimiter(deps.RateLimits)
+	return guards{
+		defaultLimit:          limiter.Limit("default", config.DefaultLimit),
+		registerLimit:         limiter.Limit("register", config.RegisterLimit),
+		loginLimit:            limiter.Limit("login", config.LoginLimit),
+		apiTokens:             infrastructure.APITokenAuth(deps.APITokens),
+		bearerTokens:          infrastructure.AuthMiddleWare(deps.Tokens),
+		sessions:              infrastructure.SessionMiddleWare(deps.Sessions),
+		requireAdminTwoFactor: config.RequireAdminTwoFactor,
+	}
+}
+
+// newV1Router registers version 1 of the API on group. A version 2 would
+// get a router of its own, with its own controllers over the same use deps.
+func newV1Router(group *gin.RouterGroup, deps Dependencies, guards guards) {
+	twoFactor := infrastructure.TwoFactorMiddleWare(guards.requireAdminTwoFactor, group.BasePath())
+
+	publicRouter := group.Group("")
+	publicRouter.Use(guards.defaultLimit)
+	NewSignUpRouter(publicRouter, deps.Users, deps.Verification, guards.registerLimit)
+	loginRouter := publicRouter.Group("")
+	loginRouter.Use(guards.loginLimit)
+	NewLoginRouter(loginRouter, deps.Users)
+	NewPasswordRouter(publicRouter, deps.PasswordReset)
+	NewDocsRouter(publicRouter)
 
-	NewSignUpRouter(db, publicRouter)
-	NewLoginRouter(db, publicRouter)
+	privateRouter := group.Group("")
+	privateRouter.Use(guards.apiTokens, guards.bearerTokens, guards.sessions, twoFactor, guards.defaultLimit)
+	NewTaskRouter(privateRouter.Group("", infrastructure.RequireScope("tasks")), deps.Tasks)
+	EscalatePrevilige(privateRouter.Group("", infrastructure.RequireScope("users")), deps.Users)
+	NewProfileRouter(privateRouter.Group("", infrastructure.RequireScope("profile")), deps.Users, deps.TwoFactor)
+	NewAPITokenRouter(privateRouter.Group("", infrastructure.RequireSession()), deps.APITokens)
+	NewWebhookRouter(privateRouter.Group("", infrastructure.RequireScope("webhooks")), deps.Webhooks)
 
-	privateRouter := router.Group("")
-	privateRouter.Use(infrastructure.AuthMiddleWare())
-	NewTaskRouter(db, privateRouter)
-	EscalatePrevilige(db, privateRouter)
+	// browsers can't set headers on EventSource and WebSocket requests
+	streamRouter := group.Group("")
+	streamRouter.Use(infrastructure.QueryTokenAuth(), guards.apiTokens, guards.bearerTokens, guards.sessions, twoFactor, infrastructure.RequireScope("tasks"), guards.defaultLimit)
+	NewStreamRouter(streamRouter, deps.Events)
 }
 
-func EscalatePrevilige(db *mongo.Database, group *gin.RouterGroup) {
-	ur := repository.NewUserRepository(db, "users")
+func EscalatePrevilige(group *gin.RouterGroup, users domain.UserUseCase) {
 	uc := &controllers.UserController{
-		UserUseCase : usecase.NewUserUseCase(ur),
+		UserUseCase : users,
 	}
 
 	group.PUT("/promote/:id", uc.PromoteUser())
+	group.PUT("/users/:id/unlock", uc.UnlockAccount())
 }
 
-func NewLoginRouter(db *mongo.Database, group *gin.RouterGroup) {
-	//here we should make the appropriate invocations to the controller function and
-	//instantiate the userUseCase usecase and pass it as an argument. uc.register => uc.login
-	//but before that we have to assign somethings to the uc struct
-	//usercontroller.somestruct.taskRepository setup the db and context here
-	ur := repository.NewUserRepository(db, "users")
+func NewProfileRouter(group *gin.RouterGroup, users domain.UserUseCase, twoFactor domain.TwoFactorUseCase) {
+	uc := &controllers.UserController{
+		UserUseCase : users,
+		TwoFactorUseCase : twoFactor,
+	}
+	group.GET("/me", uc.Me())
+	group.PATCH("/me", uc.UpdateProfile())
+	// an API token with profile:write mustn't be able to take over the
+	// account's credentials
+	group.PUT("/me/password", infrastructure.RequireSession(), uc.ChangePassword())
+	group.POST("/me/2fa/enroll", infrastructure.RequireSession(), uc.EnrollTwoFactor())
+	group.POST("/me/2fa/confirm", infrastructure.RequireSession(), uc.ConfirmTwoFactor())
+}
+
+func NewAPITokenRouter(group *gin.RouterGroup, apiTokens domain.APITokenUseCase) {
+	ac := &controllers.APITokenController{
+		APITokenUseCase : apiTokens,
+	}
+	group.POST("/me/tokens", ac.CreateToken())
+	group.GET("/me/tokens", ac.GetTokens())
+	group.DELETE("/me/tokens/:id", ac.RevokeToken())
+}
+
+func NewLoginRouter(group *gin.RouterGroup, users domain.UserUseCase) {
 	uc := &controllers.UserController {
-		UserUseCase : usecase.NewUserUseCase(ur),
+		UserUseCase : users,
 	}
 	group.POST("/login", uc.Login())
+	group.POST("/login/2fa", uc.LoginTwoFactor())
 }
 
-func NewSignUpRouter(db *mongo.Database, group *gin.RouterGroup) {
-
-	ur := repository.NewUserRepository(db, "users")
+// NewSignUpRouter puts registerLimit on signing up only, so that verifying
+// the address afterwards doesn't count against it.
+func NewSignUpRouter(group *gin.RouterGroup, users domain.UserUseCase, verification domain.EmailVerificationUseCase, registerLimit gin.HandlerFunc) {
 	uc := &controllers.UserController{
-		UserUseCase : usecase.NewUserUseCase(ur),
+		UserUseCase : users,
+		VerificationUseCase : verification,
 	}
-	group.POST("/register", uc.Register())
+	group.POST("/register", registerLimit, uc.Register())
+	group.GET("/verify", uc.VerifyEmail())
+	group.POST("/verify/resend", uc.ResendVerification())
+}
+
+func NewDocsRouter(group *gin.RouterGroup) {
+	group.GET("/openapi.json", docs.SpecHandler())
+	group.GET("/docs", docs.SwaggerUIHandler())
 }
 
-func NewTaskRouter(db *mongo.Database, group *gin.RouterGroup) {
+func NewPasswordRouter(group *gin.RouterGroup, passwordReset domain.PasswordResetUseCase) {
+	pc := &controllers.PasswordController{
+		PasswordResetUseCase : passwordReset,
+	}
+	group.POST("/password/forgot", pc.ForgotPassword())
+	group.POST("/password/reset", pc.ResetPassword())
+}
+
+func NewTaskRouter(group *gin.RouterGroup, tasks domain.TaskUseCase) {
 	//now we prepare a task controller function that returns a handler when it is called
-	tr := repository.NewTaskRepository(db, "tasks")
 	tc := &controllers.TaskController{
-		TaskUseCase: usecase.NewTaskUseCase(tr),
+		TaskUseCase: tasks,
 	}
 	group.POST("/tasks", tc.PostTask())
 	group.GET("/tasks", tc.GetTasks())
+	group.GET("/tasks/search", tc.SearchTasks())
 	group.GET("/tasks/:id", tc.GetTask())
 	group.PUT("/tasks/:id", tc.UpdateTask())
 	group.DELETE("/tasks/:id", tc.DeleteTask())
+	group.PUT("/tasks/:id/recurrence", tc.UpdateRecurrence())
+	group.DELETE("/tasks/:id/recurrence", tc.StopRecurrence())
+}
+
+func NewWebhookRouter(group *gin.RouterGroup, webhooks domain.WebhookUseCase) {
+	wc := &controllers.WebhookController{
+		WebhookUseCase: webhooks,
+	}
+	group.POST("/webhooks", wc.CreateSubscription())
+	group.GET("/webhooks", wc.GetSubscriptions())
+	group.DELETE("/webhooks/:id", wc.DeleteSubscription())
+	group.GET("/webhooks/:id/deliveries", wc.GetDeliveries())
+}
+
+func NewStreamRouter(group *gin.RouterGroup, events domain.EventStream) {
+	sc := &controllers.StreamController{
+		Events: events,
+	}
+	group.GET("/tasks/stream", sc.StreamTasks())
 }
\ No newline at end of file
@@ -1,15 +1,47 @@
 package domain
 
 import (
+	"context"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"strings"
 	"time"
 )
 
 type User struct {
 	ID       primitive.ObjectID   `json:"id" bson:"_id"`
 	Email    string       		  `json:"email" bson:"email"`
-	Password string       		  `json:"password" bson:"password"`
+	Password string       		  `json:"-" bson:"password"`
 	Role     string				  `json:"role" bson:"role"`
+	Verified bool				  `json:"verified" bson:"verified"`
+	DisplayName	string			  `json:"display_name" bson:"display_name,omitempty"`
+	Timezone	string			  `json:"timezone" bson:"timezone,omitempty"`
+	TwoFactorEnabled	bool	  `json:"-" bson:"two_factor_enabled"`
+	SessionsRevokedAt	time.Time	  `json:"-" bson:"sessions_revoked_at,omitempty"`
+}
+
+// TwoFactor is the second factor state stored alongside a user. Recovery
+// codes are kept hashed.
+type TwoFactor struct {
+	Secret			string		  `bson:"two_factor_secret,omitempty"`
+	PendingSecret	string		  `bson:"two_factor_pending_secret,omitempty"`
+	RecoveryCodes	[]string	  `bson:"recovery_codes,omitempty"`
+	LastUsedStep	int64		  `bson:"two_factor_last_step,omitempty"`
+}
+
+type TwoFactorEnrollment struct {
+	Secret			string
+	ProvisioningURI	string
+}
+
+type LoginResult struct {
+	Token				AccessToken
+	TwoFactorRequired	bool
+	ChallengeToken		string
+}
+
+type ProfileUpdate struct {
+	DisplayName	*string			  `json:"display_name"`
+	Timezone	*string			  `json:"timezone"`
 }
 
 type Task struct {
@@ -18,39 +50,372 @@ type Task struct {
 	Description string    			 `json:"description" bson:"description"`
 	DueDate     time.Time 			 `json:"due_date" bson:"due_date"`
 	Status      string    			 `json:"status" bson:"status"`
+	Comments	[]string			 `json:"comments,omitempty" bson:"comments,omitempty"`
+	Recurrence	string				 `json:"recurrence,omitempty" bson:"recurrence,omitempty"`
+	SeriesID	string				 `json:"series_id,omitempty" bson:"series_id,omitempty"`
+	Occurrence	int					 `json:"occurrence,omitempty" bson:"occurrence,omitempty"`
+	RemindersSent	[]string		 `json:"-" bson:"reminders_sent,omitempty"`
+}
+
+type Notification struct {
+	Type		string				 `json:"type"`
+	Subject		string				 `json:"subject"`
+	Message		string				 `json:"message"`
+	Task		*Task				 `json:"task,omitempty"`
+}
+
+type RecurrenceRule struct {
+	Frequency	string
+	Interval	int
+	ByDay		[]time.Weekday
+	Until		time.Time
+	Count		int
+}
+
+type TaskSearchResult struct {
+	Task		*Task				 `json:"task"`
+	Score		float64				 `json:"score"`
+	Highlights	map[string]string	 `json:"highlights"`
+}
+
+type Event struct {
+	ID			string				 `json:"id"`
+	Type		string				 `json:"type"`
+	OccurredAt	time.Time			 `json:"occurred_at"`
+	Data		interface{}			 `json:"data"`
+}
+
+type EventSubscription struct {
+	Replay		[]Event
+	Missed		bool
+	Events		<-chan Event
+	Cancel		func()
+}
+
+type WebhookSubscription struct {
+	ID			primitive.ObjectID	 `json:"id" bson:"_id"`
+	URL			string				 `json:"url" bson:"url"`
+	Secret		string				 `json:"secret,omitempty" bson:"secret"`
+	EventTypes	[]string			 `json:"event_types" bson:"event_types"`
+	CreatedAt	time.Time			 `json:"created_at" bson:"created_at"`
+}
+
+// APIToken is a personal access token for scripts. Only a hash of the
+// secret is stored; Prefix is kept so users can tell their tokens apart.
+type APIToken struct {
+	ID			primitive.ObjectID	 `json:"id" bson:"_id"`
+	UserID		string				 `json:"-" bson:"user_id"`
+	Name		string				 `json:"name" bson:"name"`
+	Prefix		string				 `json:"prefix" bson:"prefix"`
+	TokenHash	string				 `json:"-" bson:"token_hash"`
+	Scopes		[]string			 `json:"scopes" bson:"scopes"`
+	ExpiresAt	*time.Time			 `json:"expires_at,omitempty" bson:"expires_at,omitempty"`
+	LastUsedAt	*time.Time			 `json:"last_used_at,omitempty" bson:"last_used_at,omitempty"`
+	CreatedAt	time.Time			 `json:"created_at" bson:"created_at"`
+}
+
+type WebhookAttempt struct {
+	AttemptedAt	time.Time			 `json:"attempted_at" bson:"attempted_at"`
+	StatusCode	int					 `json:"status_code" bson:"status_code"`
+	Error		string				 `json:"error,omitempty" bson:"error,omitempty"`
+	DurationMS	int64				 `json:"duration_ms" bson:"duration_ms"`
+}
+
+type WebhookDelivery struct {
+	ID				primitive.ObjectID	 `json:"id" bson:"_id"`
+	SubscriptionID	string				 `json:"subscription_id" bson:"subscription_id"`
+	EventID			string				 `json:"event_id" bson:"event_id"`
+	EventType		string				 `json:"event_type" bson:"event_type"`
+	Payload			string				 `json:"payload" bson:"payload"`
+	Status			string				 `json:"status" bson:"status"`
+	Attempts		[]WebhookAttempt	 `json:"attempts" bson:"attempts"`
+	NextAttemptAt	time.Time			 `json:"next_attempt_at" bson:"next_attempt_at"`
+	CreatedAt		time.Time			 `json:"created_at" bson:"created_at"`
+}
+
+type OneTimeToken struct {
+	ID			primitive.ObjectID	 `bson:"_id"`
+	Purpose		string				 `bson:"purpose"`
+	UserID		string				 `bson:"user_id"`
+	TokenHash	string				 `bson:"token_hash"`
+	ExpiresAt	time.Time			 `bson:"expires_at"`
+	UsedAt		time.Time			 `bson:"used_at,omitempty"`
+	CreatedAt	time.Time			 `bson:"created_at"`
+}
+
+type Mail struct {
+	To			string
+	Subject		string
+	Body		string
+}
+
+type LoginAttempts struct {
+	Failures	int					 `bson:"failures"`
+	LastFailure	time.Time			 `bson:"last_failure"`
+	LockedUntil	time.Time			 `bson:"locked_until,omitempty"`
+}
+
+// RetryError is returned when a request is refused until RetryAfter has
+// passed, so handlers can tell the client when to come back.
+type RetryError struct {
+	Message		string
+	RetryAfter	time.Duration
+}
+
+func (e *RetryError) Error() string {
+	return e.Message
+}
+
+// PasswordPolicyError lists every rule a password breaks, so that they can
+// all be shown at once instead of one per attempt.
+type PasswordPolicyError struct {
+	Violations	[]PasswordViolation
+}
+
+type PasswordViolation struct {
+	Rule		string	`json:"rule"`
+	Message		string	`json:"message"`
+}
+
+func (e *PasswordPolicyError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		messages[i] = violation.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// RateLimit allows Requests requests per Per, with bursts of up to Requests.
+type RateLimit struct {
+	Requests	int
+	Per			time.Duration
+}
+
+type RateLimitResult struct {
+	Allowed		bool
+	Remaining	int
+	// ResetAfter is how long until the bucket is full again.
+	ResetAfter	time.Duration
+	// RetryAfter is how long until the next request is allowed when it
+	// wasn't.
+	RetryAfter	time.Duration
+}
+
+// TokenClaims are what an access token says about its bearer.
+type TokenClaims struct {
+	Email		string
+	Role		string
+	TwoFactor	bool
+	IssuedAt	time.Time
+	ExpiresAt	time.Time
+}
+
+// AccessToken is a signed token and the time it stops being accepted.
+type AccessToken struct {
+	Token		string
+	ExpiresAt	time.Time
+}
+
+type TokenService interface {
+	IssueToken(*User)		(AccessToken, error)
+	ParseToken(string)		(TokenClaims, error)
+}
+
+// PasswordPolicy checks a new password for the account with the given
+// email, returning a *PasswordPolicyError when it is refused.
+type PasswordPolicy interface {
+	Validate(string, string)			error
+}
+
+// PasswordHasher stores the algorithm and parameters in every hash, so
+// hashes made before a change keep verifying until they are replaced.
+type PasswordHasher interface {
+	Hash(string)						(string, error)
+	Compare(string, string)				error
+	NeedsRehash(string)					bool
 }
 
 type AuthenticatedUser struct {
 	Role		string
 	Email		string
+	IssuedAt	time.Time
+	TwoFactor	bool
+	// Scopes is only set for requests made with an API token; session
+	// tokens carry every scope.
+	APITokenID	string
+	Scopes		[]string
 }
 
 type TaskRepository interface {
-	GetTasks()							([]*Task, error)
-	GetTask(string)						(Task, error)
-	PostTask(*Task)						error
-	DeleteTask(string)					error
-	UpdateTask(string, *Task)			error
+	GetTasks(context.Context)									([]*Task, error)
+	GetTask(context.Context, string)							(Task, error)
+	PostTask(context.Context, *Task)							error
+	DeleteTask(context.Context, string)							error
+	UpdateTask(context.Context, string, *Task)					error
+	GetRecurringTasks(context.Context)							([]*Task, error)
+	GetSeriesTasks(context.Context, string)						([]*Task, error)
+	UpdateSeriesRecurrence(context.Context, string, string)		error
+	GetTasksDueBefore(context.Context, time.Time, string)		([]*Task, error)
+	MarkReminderSent(context.Context, string, string)			(bool, error)
+	ClearReminderSent(context.Context, string, string)			error
 }
 
 type TaskUseCase interface {
-	GetTasks()							([]*Task, error)
-	GetTask(string)						(Task, error)
-	PostTask(Task)						error
-	DeleteTask(string)					error
-	UpdateTask(string, *Task)			error
+	GetTasks(context.Context)									([]*Task, error)
+	GetTask(context.Context, string)							(Task, error)
+	PostTask(context.Context, *Task)							error
+	DeleteTask(context.Context, string)							error
+	UpdateTask(context.Context, string, *Task)					error
+	SearchTasks(context.Context, string, int)					([]*TaskSearchResult, error)
+	UpdateRecurrence(context.Context, string, string)			error
+	StopRecurrence(context.Context, string)						error
+	MaterializeRecurringTasks(context.Context, time.Time)		error
+}
+
+type ReminderUseCase interface {
+	CheckDueTasks(context.Context, time.Time)	error
+}
+
+type Notifier interface {
+	Notify(context.Context, Notification)	error
+}
+
+type EventPublisher interface {
+	Publish(context.Context, Event)
+}
+
+type EventStream interface {
+	EventPublisher
+	Subscribe(string)					*EventSubscription
+}
+
+type WebhookRepository interface {
+	CreateSubscription(context.Context, *WebhookSubscription)		error
+	GetSubscriptions(context.Context)								([]*WebhookSubscription, error)
+	GetSubscription(context.Context, string)						(WebhookSubscription, error)
+	GetSubscriptionsForEvent(context.Context, string)				([]*WebhookSubscription, error)
+	DeleteSubscription(context.Context, string)						error
+	CreateDelivery(context.Context, *WebhookDelivery)				error
+	ClaimPendingDelivery(context.Context, time.Time, time.Duration)	(*WebhookDelivery, error)
+	RecordAttempt(context.Context, string, WebhookAttempt, string, time.Time)	error
+	GetDeliveries(context.Context, string)							([]*WebhookDelivery, error)
+}
+
+type WebhookSender interface {
+	Send(*WebhookSubscription, *WebhookDelivery)	(int, error)
+}
+
+type WebhookUseCase interface {
+	EventPublisher
+	CreateSubscription(context.Context, *WebhookSubscription)		error
+	GetSubscriptions(context.Context)								([]*WebhookSubscription, error)
+	DeleteSubscription(context.Context, string)						error
+	GetDeliveries(context.Context, string)							([]*WebhookDelivery, error)
+	DeliverPending(context.Context, time.Time)						error
+}
+
+type OneTimeTokenRepository interface {
+	CreateToken(context.Context, *OneTimeToken)						error
+	GetToken(context.Context, string, string, time.Time)			(OneTimeToken, error)
+	ConsumeToken(context.Context, string, string, time.Time)		(OneTimeToken, error)
+	RevokeTokens(context.Context, string, string)					error
+}
+
+type LoginAttemptStore interface {
+	Get(context.Context, string)									(LoginAttempts, error)
+	RecordFailure(context.Context, string, time.Time, time.Duration)	(LoginAttempts, error)
+	Lock(context.Context, string, time.Time)						error
+	Reset(context.Context, string)									error
+}
+
+type RateLimitStore interface {
+	Take(string, RateLimit, time.Time)	(RateLimitResult, error)
+}
+
+// Cache holds serialized values for a while. Get reports whether the key was
+// found; values that expired or were evicted are not.
+type Cache interface {
+	Get(context.Context, string)		([]byte, bool, error)
+	Set(context.Context, string, []byte, time.Duration)	error
+	Delete(context.Context, ...string)	error
+}
+
+type TwoFactorRepository interface {
+	GetTwoFactor(context.Context, string)							(TwoFactor, error)
+	SetPendingSecret(context.Context, string, string)				error
+	EnableTwoFactor(context.Context, string, string, []string)		error
+	UseTimeStep(context.Context, string, int64)						(bool, error)
+	UseRecoveryCode(context.Context, string, string)				(bool, error)
+}
+
+type TwoFactorUseCase interface {
+	BeginEnrollment(context.Context, string)						(TwoFactorEnrollment, error)
+	ConfirmEnrollment(context.Context, string, string)				([]string, error)
+	Challenge(context.Context, *User)								(string, error)
+	Verify(context.Context, string, string)							(User, error)
+}
+
+type APITokenRepository interface {
+	CreateAPIToken(context.Context, *APIToken)						error
+	GetAPITokens(context.Context, string)							([]*APIToken, error)
+	GetAPITokenByHash(context.Context, string)						(APIToken, error)
+	DeleteAPIToken(context.Context, string, string)					error
+	TouchAPIToken(context.Context, string, time.Time)				error
+}
+
+type APITokenUseCase interface {
+	CreateToken(context.Context, string, *APIToken)					(string, error)
+	GetTokens(context.Context, string)								([]*APIToken, error)
+	RevokeToken(context.Context, string, string)					error
+	Authenticate(context.Context, string)							(AuthenticatedUser, error)
+}
+
+type LoginGuard interface {
+	Check(context.Context, string, string, time.Time)				error
+	RecordFailure(context.Context, string, string, time.Time)		error
+	RecordSuccess(context.Context, string)							error
+	Unlock(context.Context, string)									error
+}
+
+type Mailer interface {
+	Send(context.Context, Mail)			error
+}
+
+type TaskSearcher interface {
+	SearchTasks(context.Context, string, int)	([]*TaskSearchResult, error)
 }
 
 type UserRepository interface {
-	Register(*User)						error
-	VerifyFirst(*User)					error
-	UserExists(*User)					error
-	GetUserByEmail(string)				User
-	PromoteUser(string)					error
+	Register(context.Context, *User)								error
+	VerifyFirst(context.Context, *User)								error
+	UserExists(context.Context, *User)								error
+	GetUserByEmail(context.Context, string)							User
+	GetUserByID(context.Context, string)							(User, error)
+	PromoteUser(context.Context, string)							error
+	MarkVerified(context.Context, string)							error
+	UpdateProfile(context.Context, string, ProfileUpdate)			error
+	UpdatePassword(context.Context, string, string, time.Time)		error
+	UpdatePasswordHash(context.Context, string, string)				error
 }
 
 type UserUseCase interface {
-	Register(*User)						error
-	Login(*User)						(string, error)
-	PromoteUser(string)					error
+	Register(context.Context, *User)							error
+	Login(context.Context, *User, string)						(LoginResult, error)
+	CompleteTwoFactorLogin(context.Context, string, string, string)	(AccessToken, error)
+	PromoteUser(context.Context, string)						error
+	UnlockAccount(context.Context, string)						error
+	GetCurrentUser(context.Context, string)						(User, error)
+	UpdateProfile(context.Context, string, ProfileUpdate)		(User, error)
+	ChangePassword(context.Context, string, string, string)		(AccessToken, error)
+}
+
+type PasswordResetUseCase interface {
+	ForgotPassword(context.Context, string)							error
+	ResetPassword(context.Context, string, string)					error
+}
+
+type EmailVerificationUseCase interface {
+	SendVerification(context.Context, *User)						error
+	VerifyEmail(context.Context, string)							error
+	ResendVerification(context.Context, string)						error
 }
@@ -1,10 +1,14 @@
 // Code generated by mockery v2.44.1. DO NOT EDIT.
+
 package mocks
 
 import (
+	context "context"
 	domain "golang-clean-architecture/domain"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // TaskRepository is an autogenerated mock type for the TaskRepository type
@@ -12,17 +16,35 @@ type TaskRepository struct {
 	mock.Mock
 }
 
-// DeleteTask provides a mock function with given fields: _a0
-func (_m *TaskRepository) DeleteTask(_a0 string) error {
-	ret := _m.Called(_a0)
+// ClearReminderSent provides a mock function with given fields: _a0, _a1, _a2
+func (_m *TaskRepository) ClearReminderSent(_a0 context.Context, _a1 string, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClearReminderSent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteTask provides a mock function with given fields: _a0, _a1
+func (_m *TaskRepository) DeleteTask(_a0 context.Context, _a1 string) error {
+	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteTask")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -30,26 +52,116 @@ func (_m *TaskRepository) DeleteTask(_a0 string) error {
 	return r0
 }
 
-// GetTask provides a mock function with given fields: _a0
-func (_m *TaskRepository) GetTask(_a0 string) (domain.Task, error) {
+// GetRecurringTasks provides a mock function with given fields: _a0
+func (_m *TaskRepository) GetRecurringTasks(_a0 context.Context) ([]*domain.Task, error) {
 	ret := _m.Called(_a0)
 
+	if len(ret) == 0 {
+		panic("no return value specified for GetRecurringTasks")
+	}
+
+	var r0 []*domain.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*domain.Task, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*domain.Task); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSeriesTasks provides a mock function with given fields: _a0, _a1
+func (_m *TaskRepository) GetSeriesTasks(_a0 context.Context, _a1 string) ([]*domain.Task, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSeriesTasks")
+	}
+
+	var r0 []*domain.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*domain.Task, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*domain.Task); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTask provides a mock function with given fields: _a0, _a1
+func (_m *TaskRepository) GetTask(_a0 context.Context, _a1 string) (domain.Task, error) {
+	ret := _m.Called(_a0, _a1)
+
 	if len(ret) == 0 {
 		panic("no return value specified for GetTask")
 	}
 
 	var r0 domain.Task
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (domain.Task, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.Task, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.Task); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(domain.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTasks provides a mock function with given fields: _a0
+func (_m *TaskRepository) GetTasks(_a0 context.Context) ([]*domain.Task, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTasks")
+	}
+
+	var r0 []*domain.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*domain.Task, error)); ok {
 		return rf(_a0)
 	}
-	if rf, ok := ret.Get(0).(func(string) domain.Task); ok {
+	if rf, ok := ret.Get(0).(func(context.Context) []*domain.Task); ok {
 		r0 = rf(_a0)
 	} else {
-		r0 = ret.Get(0).(domain.Task)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Task)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
 		r1 = rf(_a0)
 	} else {
 		r1 = ret.Error(1)
@@ -58,29 +170,29 @@ func (_m *TaskRepository) GetTask(_a0 string) (domain.Task, error) {
 	return r0, r1
 }
 
-// GetTasks provides a mock function with given fields:
-func (_m *TaskRepository) GetTasks() ([]*domain.Task, error) {
-	ret := _m.Called()
+// GetTasksDueBefore provides a mock function with given fields: _a0, _a1, _a2
+func (_m *TaskRepository) GetTasksDueBefore(_a0 context.Context, _a1 time.Time, _a2 string) ([]*domain.Task, error) {
+	ret := _m.Called(_a0, _a1, _a2)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetTasks")
+		panic("no return value specified for GetTasksDueBefore")
 	}
 
 	var r0 []*domain.Task
 	var r1 error
-	if rf, ok := ret.Get(0).(func() ([]*domain.Task, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, string) ([]*domain.Task, error)); ok {
+		return rf(_a0, _a1, _a2)
 	}
-	if rf, ok := ret.Get(0).(func() []*domain.Task); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, string) []*domain.Task); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*domain.Task)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, string) error); ok {
+		r1 = rf(_a0, _a1, _a2)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -88,17 +200,45 @@ func (_m *TaskRepository) GetTasks() ([]*domain.Task, error) {
 	return r0, r1
 }
 
-// PostTask provides a mock function with given fields: _a0
-func (_m *TaskRepository) PostTask(_a0 *domain.Task) error {
-	ret := _m.Called(_a0)
+// MarkReminderSent provides a mock function with given fields: _a0, _a1, _a2
+func (_m *TaskRepository) MarkReminderSent(_a0 context.Context, _a1 string, _a2 string) (bool, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkReminderSent")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (bool, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) bool); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PostTask provides a mock function with given fields: _a0, _a1
+func (_m *TaskRepository) PostTask(_a0 context.Context, _a1 *domain.Task) error {
+	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
 		panic("no return value specified for PostTask")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*domain.Task) error); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Task) error); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -106,17 +246,35 @@ func (_m *TaskRepository) PostTask(_a0 *domain.Task) error {
 	return r0
 }
 
-// UpdateTask provides a mock function with given fields: _a0, _a1
-func (_m *TaskRepository) UpdateTask(_a0 string, _a1 *domain.Task) error {
-	ret := _m.Called(_a0, _a1)
+// UpdateSeriesRecurrence provides a mock function with given fields: _a0, _a1, _a2
+func (_m *TaskRepository) UpdateSeriesRecurrence(_a0 context.Context, _a1 string, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateSeriesRecurrence")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateTask provides a mock function with given fields: _a0, _a1, _a2
+func (_m *TaskRepository) UpdateTask(_a0 context.Context, _a1 string, _a2 *domain.Task) error {
+	ret := _m.Called(_a0, _a1, _a2)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateTask")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *domain.Task) error); ok {
-		r0 = rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.Task) error); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
 		r0 = ret.Error(0)
 	}
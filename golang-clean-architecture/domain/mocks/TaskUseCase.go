@@ -3,9 +3,12 @@
 package mocks
 
 import (
+	context "context"
 	domain "golang-clean-architecture/domain"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // TaskUseCase is an autogenerated mock type for the TaskUseCase type
@@ -13,17 +16,17 @@ type TaskUseCase struct {
 	mock.Mock
 }
 
-// DeleteTask provides a mock function with given fields: _a0
-func (_m *TaskUseCase) DeleteTask(_a0 string) error {
-	ret := _m.Called(_a0)
+// DeleteTask provides a mock function with given fields: _a0, _a1
+func (_m *TaskUseCase) DeleteTask(_a0 context.Context, _a1 string) error {
+	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteTask")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -31,9 +34,9 @@ func (_m *TaskUseCase) DeleteTask(_a0 string) error {
 	return r0
 }
 
-// GetTask provides a mock function with given fields: _a0
-func (_m *TaskUseCase) GetTask(_a0 string) (domain.Task, error) {
-	ret := _m.Called(_a0)
+// GetTask provides a mock function with given fields: _a0, _a1
+func (_m *TaskUseCase) GetTask(_a0 context.Context, _a1 string) (domain.Task, error) {
+	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetTask")
@@ -41,17 +44,17 @@ func (_m *TaskUseCase) GetTask(_a0 string) (domain.Task, error) {
 
 	var r0 domain.Task
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (domain.Task, error)); ok {
-		return rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.Task, error)); ok {
+		return rf(_a0, _a1)
 	}
-	if rf, ok := ret.Get(0).(func(string) domain.Task); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.Task); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		r0 = ret.Get(0).(domain.Task)
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(_a0)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -59,9 +62,9 @@ func (_m *TaskUseCase) GetTask(_a0 string) (domain.Task, error) {
 	return r0, r1
 }
 
-// GetTasks provides a mock function with given fields:
-func (_m *TaskUseCase) GetTasks() ([]*domain.Task, error) {
-	ret := _m.Called()
+// GetTasks provides a mock function with given fields: _a0
+func (_m *TaskUseCase) GetTasks(_a0 context.Context) ([]*domain.Task, error) {
+	ret := _m.Called(_a0)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetTasks")
@@ -69,19 +72,19 @@ func (_m *TaskUseCase) GetTasks() ([]*domain.Task, error) {
 
 	var r0 []*domain.Task
 	var r1 error
-	if rf, ok := ret.Get(0).(func() ([]*domain.Task, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*domain.Task, error)); ok {
+		return rf(_a0)
 	}
-	if rf, ok := ret.Get(0).(func() []*domain.Task); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []*domain.Task); ok {
+		r0 = rf(_a0)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*domain.Task)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -89,17 +92,35 @@ func (_m *TaskUseCase) GetTasks() ([]*domain.Task, error) {
 	return r0, r1
 }
 
-// PostTask provides a mock function with given fields: _a0
-func (_m *TaskUseCase) PostTask(_a0 domain.Task) error {
-	ret := _m.Called(_a0)
+// MaterializeRecurringTasks provides a mock function with given fields: _a0, _a1
+func (_m *TaskUseCase) MaterializeRecurringTasks(_a0 context.Context, _a1 time.Time) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MaterializeRecurringTasks")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PostTask provides a mock function with given fields: _a0, _a1
+func (_m *TaskUseCase) PostTask(_a0 context.Context, _a1 *domain.Task) error {
+	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
 		panic("no return value specified for PostTask")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(domain.Task) error); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Task) error); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -107,16 +128,46 @@ func (_m *TaskUseCase) PostTask(_a0 domain.Task) error {
 	return r0
 }
 
-// UpdateTask provides a mock function with given fields: _a0, _a1
-func (_m *TaskUseCase) UpdateTask(_a0 string, _a1 *domain.Task) error {
+// SearchTasks provides a mock function with given fields: _a0, _a1, _a2
+func (_m *TaskUseCase) SearchTasks(_a0 context.Context, _a1 string, _a2 int) ([]*domain.TaskSearchResult, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchTasks")
+	}
+
+	var r0 []*domain.TaskSearchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]*domain.TaskSearchResult, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []*domain.TaskSearchResult); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.TaskSearchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StopRecurrence provides a mock function with given fields: _a0, _a1
+func (_m *TaskUseCase) StopRecurrence(_a0 context.Context, _a1 string) error {
 	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateTask")
+		panic("no return value specified for StopRecurrence")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *domain.Task) error); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
 		r0 = rf(_a0, _a1)
 	} else {
 		r0 = ret.Error(0)
@@ -125,6 +176,42 @@ func (_m *TaskUseCase) UpdateTask(_a0 string, _a1 *domain.Task) error {
 	return r0
 }
 
+// UpdateRecurrence provides a mock function with given fields: _a0, _a1, _a2
+func (_m *TaskUseCase) UpdateRecurrence(_a0 context.Context, _a1 string, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateRecurrence")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateTask provides a mock function with given fields: _a0, _a1, _a2
+func (_m *TaskUseCase) UpdateTask(_a0 context.Context, _a1 string, _a2 *domain.Task) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTask")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.Task) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewTaskUseCase creates a new instance of TaskUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewTaskUseCase(t interface {
@@ -3,9 +3,12 @@
 package mocks
 
 import (
+	context "context"
 	domain "golang-clean-architecture/domain"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // UserRepository is an autogenerated mock type for the UserRepository type
@@ -13,35 +16,81 @@ type UserRepository struct {
 	mock.Mock
 }
 
-// GetUserByEmail provides a mock function with given fields: _a0
-func (_m *UserRepository) GetUserByEmail(_a0 string) domain.User {
-	ret := _m.Called(_a0)
+// GetUserByEmail provides a mock function with given fields: _a0, _a1
+func (_m *UserRepository) GetUserByEmail(_a0 context.Context, _a1 string) domain.User {
+	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetUserByEmail")
 	}
 
 	var r0 domain.User
-	if rf, ok := ret.Get(0).(func(string) domain.User); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.User); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	return r0
+}
+
+// GetUserByID provides a mock function with given fields: _a0, _a1
+func (_m *UserRepository) GetUserByID(_a0 context.Context, _a1 string) (domain.User, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByID")
+	}
+
+	var r0 domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.User, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.User); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		r0 = ret.Get(0).(domain.User)
 	}
 
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkVerified provides a mock function with given fields: _a0, _a1
+func (_m *UserRepository) MarkVerified(_a0 context.Context, _a1 string) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkVerified")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
 	return r0
 }
 
-// PromoteUser provides a mock function with given fields: _a0
-func (_m *UserRepository) PromoteUser(_a0 string) error {
-	ret := _m.Called(_a0)
+// PromoteUser provides a mock function with given fields: _a0, _a1
+func (_m *UserRepository) PromoteUser(_a0 context.Context, _a1 string) error {
+	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
 		panic("no return value specified for PromoteUser")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -49,17 +98,71 @@ func (_m *UserRepository) PromoteUser(_a0 string) error {
 	return r0
 }
 
-// Register provides a mock function with given fields: _a0
-func (_m *UserRepository) Register(_a0 *domain.User) error {
-	ret := _m.Called(_a0)
+// Register provides a mock function with given fields: _a0, _a1
+func (_m *UserRepository) Register(_a0 context.Context, _a1 *domain.User) error {
+	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Register")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*domain.User) error); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.User) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePassword provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *UserRepository) UpdatePassword(_a0 context.Context, _a1 string, _a2 string, _a3 time.Time) error {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) error); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePasswordHash provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserRepository) UpdatePasswordHash(_a0 context.Context, _a1 string, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePasswordHash")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateProfile provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserRepository) UpdateProfile(_a0 context.Context, _a1 string, _a2 domain.ProfileUpdate) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateProfile")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.ProfileUpdate) error); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -67,17 +170,17 @@ func (_m *UserRepository) Register(_a0 *domain.User) error {
 	return r0
 }
 
-// UserExists provides a mock function with given fields: _a0
-func (_m *UserRepository) UserExists(_a0 *domain.User) error {
-	ret := _m.Called(_a0)
+// UserExists provides a mock function with given fields: _a0, _a1
+func (_m *UserRepository) UserExists(_a0 context.Context, _a1 *domain.User) error {
+	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UserExists")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*domain.User) error); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.User) error); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -85,17 +188,17 @@ func (_m *UserRepository) UserExists(_a0 *domain.User) error {
 	return r0
 }
 
-// VerifyFirst provides a mock function with given fields: _a0
-func (_m *UserRepository) VerifyFirst(_a0 *domain.User) error {
-	ret := _m.Called(_a0)
+// VerifyFirst provides a mock function with given fields: _a0, _a1
+func (_m *UserRepository) VerifyFirst(_a0 context.Context, _a1 *domain.User) error {
+	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
 		panic("no return value specified for VerifyFirst")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*domain.User) error); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.User) error); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		r0 = ret.Error(0)
 	}
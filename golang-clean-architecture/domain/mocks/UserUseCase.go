@@ -3,6 +3,7 @@
 package mocks
 
 import (
+	context "context"
 	domain "golang-clean-architecture/domain"
 
 	mock "github.com/stretchr/testify/mock"
@@ -13,27 +14,111 @@ type UserUseCase struct {
 	mock.Mock
 }
 
-// Login provides a mock function with given fields: _a0
-func (_m *UserUseCase) Login(_a0 *domain.User) (string, error) {
-	ret := _m.Called(_a0)
+// ChangePassword provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *UserUseCase) ChangePassword(_a0 context.Context, _a1 string, _a2 string, _a3 string) (domain.AccessToken, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangePassword")
+	}
+
+	var r0 domain.AccessToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (domain.AccessToken, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) domain.AccessToken); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Get(0).(domain.AccessToken)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CompleteTwoFactorLogin provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *UserUseCase) CompleteTwoFactorLogin(_a0 context.Context, _a1 string, _a2 string, _a3 string) (domain.AccessToken, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteTwoFactorLogin")
+	}
+
+	var r0 domain.AccessToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (domain.AccessToken, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) domain.AccessToken); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Get(0).(domain.AccessToken)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCurrentUser provides a mock function with given fields: _a0, _a1
+func (_m *UserUseCase) GetCurrentUser(_a0 context.Context, _a1 string) (domain.User, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCurrentUser")
+	}
+
+	var r0 domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.User, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.User); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Login provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserUseCase) Login(_a0 context.Context, _a1 *domain.User, _a2 string) (domain.LoginResult, error) {
+	ret := _m.Called(_a0, _a1, _a2)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Login")
 	}
 
-	var r0 string
+	var r0 domain.LoginResult
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*domain.User) (string, error)); ok {
-		return rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.User, string) (domain.LoginResult, error)); ok {
+		return rf(_a0, _a1, _a2)
 	}
-	if rf, ok := ret.Get(0).(func(*domain.User) string); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.User, string) domain.LoginResult); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
-		r0 = ret.Get(0).(string)
+		r0 = ret.Get(0).(domain.LoginResult)
 	}
 
-	if rf, ok := ret.Get(1).(func(*domain.User) error); ok {
-		r1 = rf(_a0)
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.User, string) error); ok {
+		r1 = rf(_a0, _a1, _a2)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -41,17 +126,17 @@ func (_m *UserUseCase) Login(_a0 *domain.User) (string, error) {
 	return r0, r1
 }
 
-// PromoteUser provides a mock function with given fields: _a0
-func (_m *UserUseCase) PromoteUser(_a0 string) error {
-	ret := _m.Called(_a0)
+// PromoteUser provides a mock function with given fields: _a0, _a1
+func (_m *UserUseCase) PromoteUser(_a0 context.Context, _a1 string) error {
+	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
 		panic("no return value specified for PromoteUser")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -59,17 +144,35 @@ func (_m *UserUseCase) PromoteUser(_a0 string) error {
 	return r0
 }
 
-// Register provides a mock function with given fields: _a0
-func (_m *UserUseCase) Register(_a0 *domain.User) error {
-	ret := _m.Called(_a0)
+// Register provides a mock function with given fields: _a0, _a1
+func (_m *UserUseCase) Register(_a0 context.Context, _a1 *domain.User) error {
+	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Register")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*domain.User) error); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.User) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UnlockAccount provides a mock function with given fields: _a0, _a1
+func (_m *UserUseCase) UnlockAccount(_a0 context.Context, _a1 string) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnlockAccount")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -77,6 +180,34 @@ func (_m *UserUseCase) Register(_a0 *domain.User) error {
 	return r0
 }
 
+// UpdateProfile provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserUseCase) UpdateProfile(_a0 context.Context, _a1 string, _a2 domain.ProfileUpdate) (domain.User, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateProfile")
+	}
+
+	var r0 domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.ProfileUpdate) (domain.User, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.ProfileUpdate) domain.User); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.ProfileUpdate) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewUserUseCase creates a new instance of UserUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUserUseCase(t interface {
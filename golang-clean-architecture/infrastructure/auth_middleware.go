@@ -1,17 +1,24 @@
 package infrastructure
 
 import (
-	"errors"
 	"net/http"
-	"os"
 	"strings"
-	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
 	"golang-clean-architecture/domain"
 )
 
-func AuthMiddleWare() gin.HandlerFunc {
+// APITokenPrefix starts every API token, which tells them apart from JWTs
+// and makes leaked tokens easy to scan for.
+const APITokenPrefix = "tm_"
+
+func AuthMiddleWare(tokens domain.TokenService) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// already authenticated by APITokenAuth
+		if _, ok := c.Get("AuthorizedUser"); ok {
+			c.Next()
+			return
+		}
+
 		authHeader := c.GetHeader("Authorization")
 
 		if authHeader == "" {
@@ -26,44 +33,161 @@ func AuthMiddleWare() gin.HandlerFunc {
 			return
 		}
 
-		token, err :=  jwt.Parse(headerSlice[1], func(token *jwt.Token) (interface{}, error) {
-			if _,ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, errors.New("incompatible tokenization method")
-			}
-			return []byte(os.Getenv("JWT_SECRET")), nil
-		})	
-		
-		if err != nil || !token.Valid {
+		claims, err := tokens.ParseToken(headerSlice[1])
+		if err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error" : "invalid token"})
 			c.Abort()
 			return
 		}
 
-		claims, ok := token.Claims.(jwt.MapClaims)
+		c.Set("AuthorizedUser", &domain.AuthenticatedUser{
+			Role : claims.Role,
+			Email : claims.Email,
+			IssuedAt : claims.IssuedAt,
+			TwoFactor : claims.TwoFactor,
+		})
+		c.Next()
+	}
+}
+
+// APITokenAuth authenticates requests whose bearer token is an API token and
+// leaves JWTs to AuthMiddleWare, so it must run before it.
+func APITokenAuth(tokens domain.APITokenUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scheme, secret, found := strings.Cut(c.GetHeader("Authorization"), " ")
+		if !found || strings.ToLower(scheme) != "bearer" || !strings.HasPrefix(secret, APITokenPrefix) {
+			c.Next()
+			return
+		}
+
+		authUser, err := tokens.Authenticate(c.Request.Context(), secret)
+		if err != nil {
+			if err.Error() == "internal server error" {
+				c.IndentedJSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+			} else {
+				c.IndentedJSON(http.StatusUnauthorized, gin.H{"error" : err.Error()})
+			}
+			c.Abort()
+			return
+		}
+		c.Set("AuthorizedUser", &authUser)
+		c.Next()
+	}
+}
+
+// RequireScope checks that API tokens were granted resource's read scope for
+// GET requests and its write scope for anything else. Session tokens carry
+// every scope.
+func RequireScope(resource string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		AuthUser, ok := c.Get("AuthorizedUser")
 		if !ok {
 			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error" : "internal server error"})
 			c.Abort()
 			return
 		}
+		authUser := AuthUser.(*domain.AuthenticatedUser)
+		if authUser.APITokenID == "" {
+			c.Next()
+			return
+		}
 
-		email, ok := claims["email"].(string)
+		scope := resource + ":write"
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			scope = resource + ":read"
+		}
+		for _, granted := range authUser.Scopes {
+			if granted == scope {
+				c.Next()
+				return
+			}
+		}
+		c.IndentedJSON(http.StatusForbidden, gin.H{"error" : "api token is missing the " + scope + " scope"})
+		c.Abort()
+	}
+}
+
+// RequireSession refuses API tokens, for routes such as token management
+// that need the user to have signed in with their password.
+func RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		AuthUser, ok := c.Get("AuthorizedUser")
+		if !ok || AuthUser.(*domain.AuthenticatedUser).APITokenID != "" {
+			c.IndentedJSON(http.StatusForbidden, gin.H{"error" : "this action can't be performed with an api token"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// QueryTokenAuth lets clients that can't set headers, such as the browser
+// EventSource and WebSocket APIs, pass their token as ?access_token=. It only
+// fills in a missing Authorization header, so it must run before AuthMiddleWare.
+func QueryTokenAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			if token := c.Query("access_token"); token != "" {
+				c.Request.Header.Set("Authorization", "Bearer " + token)
+			}
+		}
+		c.Next()
+	}
+}
+
+// SessionMiddleWare rejects tokens issued before the user's sessions were
+// last revoked, for example by a password reset. It must run after
+// AuthMiddleWare.
+func SessionMiddleWare(ur domain.UserRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		AuthUser, ok := c.Get("AuthorizedUser")
 		if !ok {
-			c.IndentedJSON(http.StatusBadRequest, gin.H{"error" : "invalid token"})
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error" : "internal server error"})
 			c.Abort()
 			return
 		}
+		authUser := AuthUser.(*domain.AuthenticatedUser)
+		// API tokens are revoked one by one rather than with the sessions
+		if authUser.APITokenID != "" {
+			c.Next()
+			return
+		}
 
-		role, ok := claims["role"].(string)
+		user := ur.GetUserByEmail(c.Request.Context(), authUser.Email)
+		if user == (domain.User{}) || authUser.IssuedAt.Before(user.SessionsRevokedAt) {
+			c.IndentedJSON(http.StatusUnauthorized, gin.H{"error" : "session has been revoked"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// TwoFactorMiddleWare, when required is set, keeps admins who haven't signed
+// in with 2FA away from everything but their profile and 2FA enrollment, so
+// that they can still set it up. basePath is the prefix the routes are
+// mounted under. It must run after AuthMiddleWare.
+func TwoFactorMiddleWare(required bool, basePath string) gin.HandlerFunc {
+	basePath = strings.TrimSuffix(basePath, "/")
+	return func(c *gin.Context) {
+		if !required {
+			c.Next()
+			return
+		}
+		AuthUser, ok := c.Get("AuthorizedUser")
 		if !ok {
-			c.IndentedJSON(http.StatusBadRequest, gin.H{"error" : "invalid token"})
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error" : "internal server error"})
 			c.Abort()
 			return
 		}
+		authUser := AuthUser.(*domain.AuthenticatedUser)
 
-		c.Set("AuthorizedUser", &domain.AuthenticatedUser{
-			Role : role,
-			Email : email,
-		})
+		path := strings.TrimPrefix(c.FullPath(), basePath)
+		if authUser.Role == "admin" && !authUser.TwoFactor && path != "/me" && !strings.HasPrefix(path, "/me/2fa/") {
+			c.IndentedJSON(http.StatusForbidden, gin.H{"error" : "two-factor authentication is required for admins"})
+			c.Abort()
+			return
+		}
 		c.Next()
 	}
 }
\ No newline at end of file
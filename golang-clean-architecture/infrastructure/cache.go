@@ -0,0 +1,154 @@
+package infrastructure
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"golang-clean-architecture/domain"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// LoadTaskCache returns the cache named by TASK_CACHE: "memory", the
+// default, holding up to TASK_CACHE_SIZE entries, "redis" at REDIS_URL, or
+// "none", for which it returns nil.
+func LoadTaskCache() (domain.Cache, error) {
+	switch value := GetEnv("TASK_CACHE", "memory"); value {
+	case "none":
+		return nil, nil
+	case "redis":
+		options, err := redis.ParseURL(GetEnv("REDIS_URL", "redis://localhost:6379/0"))
+		if err != nil {
+			return nil, err
+		}
+		return NewRedisCache(redis.NewClient(options), "task-manager:"), nil
+	case "memory":
+	default:
+		slog.Warn("ignoring invalid environment variable", "key", "TASK_CACHE", "value", value, "using", "memory")
+	}
+	return NewMemoryCache(GetEnvInt("TASK_CACHE_SIZE", 1000)), nil
+}
+
+// MemoryCache keeps up to Capacity values in process memory, evicting the
+// least recently used one to make room. Like the other memory stores it is
+// fine for a single instance; instances that have to see each other's
+// invalidations should share a RedisCache.
+type MemoryCache struct {
+	mutex   sync.Mutex
+	entries map[string]*list.Element
+	// order holds the entries, most recently used first.
+	order    *list.List
+	Capacity int
+	Now      func() time.Time
+}
+
+type memoryCacheEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+func NewMemoryCache(capacity int) *MemoryCache {
+	return &MemoryCache{
+		entries:  map[string]*list.Element{},
+		order:    list.New(),
+		Capacity: capacity,
+		Now:      time.Now,
+	}
+}
+
+func (mc *MemoryCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	element, ok := mc.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	entry := element.Value.(*memoryCacheEntry)
+	if !mc.Now().Before(entry.expires) {
+		mc.remove(element)
+		return nil, false, nil
+	}
+	mc.order.MoveToFront(element)
+	return entry.value, true, nil
+}
+
+func (mc *MemoryCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	expires := mc.Now().Add(ttl)
+	if element, ok := mc.entries[key]; ok {
+		entry := element.Value.(*memoryCacheEntry)
+		entry.value = value
+		entry.expires = expires
+		mc.order.MoveToFront(element)
+		return nil
+	}
+
+	mc.entries[key] = mc.order.PushFront(&memoryCacheEntry{key: key, value: value, expires: expires})
+	for mc.order.Len() > mc.Capacity {
+		mc.remove(mc.order.Back())
+	}
+	return nil
+}
+
+func (mc *MemoryCache) Delete(ctx context.Context, keys ...string) error {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	for _, key := range keys {
+		if element, ok := mc.entries[key]; ok {
+			mc.remove(element)
+		}
+	}
+	return nil
+}
+
+// remove must be called with the lock held.
+func (mc *MemoryCache) remove(element *list.Element) {
+	mc.order.Remove(element)
+	delete(mc.entries, element.Value.(*memoryCacheEntry).key)
+}
+
+// RedisCache keeps values in Redis, or any server speaking its protocol, so
+// that every instance sees the same entries and invalidations.
+type RedisCache struct {
+	Client redis.UniversalClient
+	// Prefix keeps the keys apart from those of other users of the server.
+	Prefix string
+}
+
+func NewRedisCache(client redis.UniversalClient, prefix string) *RedisCache {
+	return &RedisCache{
+		Client: client,
+		Prefix: prefix,
+	}
+}
+
+func (rc *RedisCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := rc.Client.Get(ctx, rc.Prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (rc *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return rc.Client.Set(ctx, rc.Prefix+key, value, ttl).Err()
+}
+
+func (rc *RedisCache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = rc.Prefix + key
+	}
+	return rc.Client.Del(ctx, prefixed...).Err()
+}
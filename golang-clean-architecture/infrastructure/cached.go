@@ -0,0 +1,163 @@
+package infrastructure
+
+import (
+	"context"
+	"golang-clean-architecture/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const allTasksKey = "tasks"
+
+// CacheTaskRepository serves GetTask and GetTasks from cache for up to ttl
+// and drops the entries a write could have changed. Tasks are stored
+// serialized, so callers modifying a task can't change the cached copy.
+// Without a cache tasks is returned as is.
+func CacheTaskRepository(tasks domain.TaskRepository, cache domain.Cache, ttl time.Duration, metrics *Metrics) domain.TaskRepository {
+	if cache == nil {
+		return tasks
+	}
+	return &cachedTaskRepository{next: tasks, cache: cache, ttl: ttl, metrics: metrics}
+}
+
+type cachedTaskRepository struct {
+	next    domain.TaskRepository
+	cache   domain.Cache
+	ttl     time.Duration
+	metrics *Metrics
+}
+
+// bson can only encode documents, so values are wrapped in one.
+type cachedTask struct {
+	Task domain.Task `bson:"task"`
+}
+
+type cachedTasks struct {
+	Tasks []*domain.Task `bson:"tasks"`
+}
+
+func taskKey(taskID string) string {
+	return "task:" + taskID
+}
+
+// load decodes the entry at key into value and reports whether it was
+// found. A cache that fails is treated as empty.
+func (r *cachedTaskRepository) load(ctx context.Context, key string, value interface{}) bool {
+	data, ok, err := r.cache.Get(ctx, key)
+	if err == nil && ok {
+		err = bson.Unmarshal(data, value)
+	}
+	switch {
+	case err != nil:
+		r.metrics.observeCache("tasks", "error")
+		return false
+	case !ok:
+		r.metrics.observeCache("tasks", "miss")
+		return false
+	}
+	r.metrics.observeCache("tasks", "hit")
+	return true
+}
+
+// store is best effort: a value that isn't cached is just read again.
+func (r *cachedTaskRepository) store(ctx context.Context, key string, value interface{}) {
+	data, err := bson.Marshal(value)
+	if err != nil {
+		return
+	}
+	r.cache.Set(ctx, key, data, r.ttl)
+}
+
+// invalidate drops the list of tasks and the given tasks. Writes call it
+// even when they fail, since a failure doesn't mean nothing was written.
+// Entries that can't be dropped expire after ttl.
+func (r *cachedTaskRepository) invalidate(ctx context.Context, taskIDs ...string) {
+	keys := []string{allTasksKey}
+	for _, taskID := range taskIDs {
+		keys = append(keys, taskKey(taskID))
+	}
+	r.cache.Delete(ctx, keys...)
+}
+
+func (r *cachedTaskRepository) GetTasks(ctx context.Context) ([]*domain.Task, error) {
+	var cached cachedTasks
+	if r.load(ctx, allTasksKey, &cached) {
+		return cached.Tasks, nil
+	}
+	tasks, err := r.next.GetTasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store(ctx, allTasksKey, cachedTasks{Tasks: tasks})
+	return tasks, nil
+}
+
+func (r *cachedTaskRepository) GetTask(ctx context.Context, taskID string) (domain.Task, error) {
+	var cached cachedTask
+	if r.load(ctx, taskKey(taskID), &cached) {
+		return cached.Task, nil
+	}
+	task, err := r.next.GetTask(ctx, taskID)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	r.store(ctx, taskKey(taskID), cachedTask{Task: task})
+	return task, nil
+}
+
+func (r *cachedTaskRepository) PostTask(ctx context.Context, task *domain.Task) error {
+	err := r.next.PostTask(ctx, task)
+	r.invalidate(ctx)
+	return err
+}
+
+func (r *cachedTaskRepository) DeleteTask(ctx context.Context, taskID string) error {
+	err := r.next.DeleteTask(ctx, taskID)
+	r.invalidate(ctx, taskID)
+	return err
+}
+
+func (r *cachedTaskRepository) UpdateTask(ctx context.Context, taskID string, task *domain.Task) error {
+	err := r.next.UpdateTask(ctx, taskID, task)
+	r.invalidate(ctx, taskID)
+	return err
+}
+
+func (r *cachedTaskRepository) GetRecurringTasks(ctx context.Context) ([]*domain.Task, error) {
+	return r.next.GetRecurringTasks(ctx)
+}
+
+func (r *cachedTaskRepository) GetSeriesTasks(ctx context.Context, seriesID string) ([]*domain.Task, error) {
+	return r.next.GetSeriesTasks(ctx, seriesID)
+}
+
+func (r *cachedTaskRepository) UpdateSeriesRecurrence(ctx context.Context, seriesID string, recurrence string) error {
+	err := r.next.UpdateSeriesRecurrence(ctx, seriesID, recurrence)
+	// the update doesn't say which tasks it changed, so the series is read
+	// back to find them
+	taskIDs := []string{seriesID}
+	series, seriesErr := r.next.GetSeriesTasks(ctx, seriesID)
+	if seriesErr == nil {
+		for _, task := range series {
+			taskIDs = append(taskIDs, task.ID.Hex())
+		}
+	}
+	r.invalidate(ctx, taskIDs...)
+	return err
+}
+
+func (r *cachedTaskRepository) GetTasksDueBefore(ctx context.Context, before time.Time, reminder string) ([]*domain.Task, error) {
+	return r.next.GetTasksDueBefore(ctx, before, reminder)
+}
+
+// Sent reminders are never served to clients and the reminder job reads
+// them through GetTasksDueBefore, which bypasses the cache, so recording
+// them leaves the cached tasks in place.
+func (r *cachedTaskRepository) MarkReminderSent(ctx context.Context, taskID string, reminder string) (bool, error) {
+	return r.next.MarkReminderSent(ctx, taskID, reminder)
+}
+
+func (r *cachedTaskRepository) ClearReminderSent(ctx context.Context, taskID string, reminder string) error {
+	return r.next.ClearReminderSent(ctx, taskID, reminder)
+}
@@ -0,0 +1,23 @@
+package infrastructure
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeprecationMiddleWare announces that routes will be removed at sunset,
+// with the Deprecation (RFC 9745) and Sunset (RFC 8594) headers, and links
+// to the same path under successor, where the routes live on.
+func DeprecationMiddleWare(deprecated time.Time, sunset time.Time, successor string) gin.HandlerFunc {
+	deprecation := "@" + strconv.FormatInt(deprecated.Unix(), 10)
+	sunsetDate := sunset.UTC().Format(http.TimeFormat)
+	return func(c *gin.Context) {
+		c.Header("Deprecation", deprecation)
+		c.Header("Sunset", sunsetDate)
+		c.Header("Link", "<"+successor+c.Request.URL.Path+`>; rel="successor-version"`)
+		c.Next()
+	}
+}
@@ -0,0 +1,100 @@
+package infrastructure
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GetEnv reads a string from the environment, falling back to the given
+// default when the variable is unset.
+func GetEnv(key string, fallback string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// GetEnvBool reads a boolean such as "true" or "0" from the environment,
+// falling back to the given default when the variable is unset or malformed.
+func GetEnvBool(key string, fallback bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		slog.Warn("ignoring invalid environment variable", "key", key, "value", value, "using", fallback)
+		return fallback
+	}
+	return enabled
+}
+
+// GetEnvInt reads a positive integer from the environment, falling back to
+// the given default when the variable is unset or malformed.
+func GetEnvInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil || number <= 0 {
+		slog.Warn("ignoring invalid environment variable", "key", key, "value", value, "using", fallback)
+		return fallback
+	}
+	return number
+}
+
+// GetEnvDuration reads a duration such as "15m" from the environment, falling
+// back to the given default when the variable is unset or malformed.
+func GetEnvDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		slog.Warn("ignoring invalid environment variable", "key", key, "value", value, "using", fallback)
+		return fallback
+	}
+	return duration
+}
+
+// GetEnvTime reads an RFC 3339 time such as "2027-04-30T00:00:00Z" from the
+// environment, falling back to the given default when the variable is unset
+// or malformed.
+func GetEnvTime(key string, fallback time.Time) time.Time {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		slog.Warn("ignoring invalid environment variable", "key", key, "value", value, "using", fallback)
+		return fallback
+	}
+	return parsed
+}
+
+// GetEnvDurations reads a comma separated list of durations such as "24h,1h".
+// The whole list falls back to the default if any entry is malformed.
+func GetEnvDurations(key string, fallback []time.Duration) []time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	var durations []time.Duration
+	for _, part := range strings.Split(value, ",") {
+		duration, err := time.ParseDuration(strings.TrimSpace(part))
+		if err != nil || duration <= 0 {
+			slog.Warn("ignoring invalid environment variable", "key", key, "value", value, "using", fallback)
+			return fallback
+		}
+		durations = append(durations, duration)
+	}
+	return durations
+}
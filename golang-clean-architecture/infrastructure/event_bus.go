@@ -0,0 +1,91 @@
+package infrastructure
+
+import (
+	"context"
+	"golang-clean-architecture/domain"
+	"sync"
+)
+
+const subscriberBuffer = 64
+
+// EventBus fans events published by the use cases out to live subscribers,
+// such as open task streams, and forwards them to a fixed set of sinks like
+// the webhook dispatcher. The most recent events are kept in a bounded buffer
+// so reconnecting clients can resume from the last event they saw.
+type EventBus struct {
+	mutex       sync.Mutex
+	capacity    int
+	buffer      []domain.Event
+	subscribers map[chan domain.Event]struct{}
+	sinks       []domain.EventPublisher
+}
+
+func NewEventBus(capacity int, sinks ...domain.EventPublisher) *EventBus {
+	return &EventBus{
+		capacity:    capacity,
+		buffer:      make([]domain.Event, 0, capacity),
+		subscribers: map[chan domain.Event]struct{}{},
+		sinks:       sinks,
+	}
+}
+
+func (eb *EventBus) Publish(ctx context.Context, event domain.Event) {
+	eb.mutex.Lock()
+	if len(eb.buffer) < eb.capacity {
+		eb.buffer = append(eb.buffer, event)
+	} else if eb.capacity > 0 {
+		copy(eb.buffer, eb.buffer[1:])
+		eb.buffer[len(eb.buffer)-1] = event
+	}
+
+	for events := range eb.subscribers {
+		select {
+		case events <- event:
+		default:
+			// a subscriber that can't keep up is dropped rather than
+			// allowed to block publishers; it resumes from the buffer
+			// when it reconnects
+			delete(eb.subscribers, events)
+			close(events)
+		}
+	}
+	eb.mutex.Unlock()
+
+	for _, sink := range eb.sinks {
+		sink.Publish(ctx, event)
+	}
+}
+
+// Subscribe registers a live subscriber. When lastEventID is set the buffered
+// events published after it are returned for replay; Missed is set if that
+// event has already left the buffer and the client has to resynchronise.
+func (eb *EventBus) Subscribe(lastEventID string) *domain.EventSubscription {
+	eb.mutex.Lock()
+	defer eb.mutex.Unlock()
+
+	events := make(chan domain.Event, subscriberBuffer)
+	subscription := &domain.EventSubscription{Events: events}
+
+	if lastEventID != "" {
+		found := false
+		for i, event := range eb.buffer {
+			if event.ID == lastEventID {
+				subscription.Replay = append([]domain.Event{}, eb.buffer[i+1:]...)
+				found = true
+				break
+			}
+		}
+		subscription.Missed = !found
+	}
+
+	eb.subscribers[events] = struct{}{}
+	subscription.Cancel = func() {
+		eb.mutex.Lock()
+		defer eb.mutex.Unlock()
+		if _, ok := eb.subscribers[events]; ok {
+			delete(eb.subscribers, events)
+			close(events)
+		}
+	}
+	return subscription
+}
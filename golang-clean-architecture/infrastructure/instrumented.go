@@ -0,0 +1,216 @@
+package infrastructure
+
+import (
+	"context"
+	"golang-clean-architecture/domain"
+	"time"
+)
+
+// InstrumentTaskRepository records how long every call to tasks takes.
+func InstrumentTaskRepository(tasks domain.TaskRepository, metrics *Metrics) domain.TaskRepository {
+	return &instrumentedTaskRepository{next: tasks, metrics: metrics}
+}
+
+type instrumentedTaskRepository struct {
+	next    domain.TaskRepository
+	metrics *Metrics
+}
+
+func (r *instrumentedTaskRepository) observe(method string, start time.Time, err error) {
+	r.metrics.observeRepository("tasks", method, start, err)
+}
+
+func (r *instrumentedTaskRepository) GetTasks(ctx context.Context) ([]*domain.Task, error) {
+	start := time.Now()
+	result, err := r.next.GetTasks(ctx)
+	r.observe("GetTasks", start, err)
+	return result, err
+}
+
+func (r *instrumentedTaskRepository) GetTask(ctx context.Context, taskID string) (domain.Task, error) {
+	start := time.Now()
+	result, err := r.next.GetTask(ctx, taskID)
+	r.observe("GetTask", start, err)
+	return result, err
+}
+
+func (r *instrumentedTaskRepository) PostTask(ctx context.Context, task *domain.Task) error {
+	start := time.Now()
+	err := r.next.PostTask(ctx, task)
+	r.observe("PostTask", start, err)
+	return err
+}
+
+func (r *instrumentedTaskRepository) DeleteTask(ctx context.Context, taskID string) error {
+	start := time.Now()
+	err := r.next.DeleteTask(ctx, taskID)
+	r.observe("DeleteTask", start, err)
+	return err
+}
+
+func (r *instrumentedTaskRepository) UpdateTask(ctx context.Context, taskID string, task *domain.Task) error {
+	start := time.Now()
+	err := r.next.UpdateTask(ctx, taskID, task)
+	r.observe("UpdateTask", start, err)
+	return err
+}
+
+func (r *instrumentedTaskRepository) GetRecurringTasks(ctx context.Context) ([]*domain.Task, error) {
+	start := time.Now()
+	result, err := r.next.GetRecurringTasks(ctx)
+	r.observe("GetRecurringTasks", start, err)
+	return result, err
+}
+
+func (r *instrumentedTaskRepository) GetSeriesTasks(ctx context.Context, seriesID string) ([]*domain.Task, error) {
+	start := time.Now()
+	result, err := r.next.GetSeriesTasks(ctx, seriesID)
+	r.observe("GetSeriesTasks", start, err)
+	return result, err
+}
+
+func (r *instrumentedTaskRepository) UpdateSeriesRecurrence(ctx context.Context, seriesID string, recurrence string) error {
+	start := time.Now()
+	err := r.next.UpdateSeriesRecurrence(ctx, seriesID, recurrence)
+	r.observe("UpdateSeriesRecurrence", start, err)
+	return err
+}
+
+func (r *instrumentedTaskRepository) GetTasksDueBefore(ctx context.Context, before time.Time, reminder string) ([]*domain.Task, error) {
+	start := time.Now()
+	result, err := r.next.GetTasksDueBefore(ctx, before, reminder)
+	r.observe("GetTasksDueBefore", start, err)
+	return result, err
+}
+
+func (r *instrumentedTaskRepository) MarkReminderSent(ctx context.Context, taskID string, reminder string) (bool, error) {
+	start := time.Now()
+	result, err := r.next.MarkReminderSent(ctx, taskID, reminder)
+	r.observe("MarkReminderSent", start, err)
+	return result, err
+}
+
+func (r *instrumentedTaskRepository) ClearReminderSent(ctx context.Context, taskID string, reminder string) error {
+	start := time.Now()
+	err := r.next.ClearReminderSent(ctx, taskID, reminder)
+	r.observe("ClearReminderSent", start, err)
+	return err
+}
+
+// InstrumentUserRepository records how long every call to users takes.
+func InstrumentUserRepository(users domain.UserRepository, metrics *Metrics) domain.UserRepository {
+	return &instrumentedUserRepository{next: users, metrics: metrics}
+}
+
+type instrumentedUserRepository struct {
+	next    domain.UserRepository
+	metrics *Metrics
+}
+
+func (r *instrumentedUserRepository) observe(method string, start time.Time, err error) {
+	r.metrics.observeRepository("users", method, start, err)
+}
+
+func (r *instrumentedUserRepository) Register(ctx context.Context, user *domain.User) error {
+	start := time.Now()
+	err := r.next.Register(ctx, user)
+	r.observe("Register", start, err)
+	return err
+}
+
+func (r *instrumentedUserRepository) VerifyFirst(ctx context.Context, user *domain.User) error {
+	start := time.Now()
+	err := r.next.VerifyFirst(ctx, user)
+	r.observe("VerifyFirst", start, err)
+	return err
+}
+
+func (r *instrumentedUserRepository) UserExists(ctx context.Context, user *domain.User) error {
+	start := time.Now()
+	err := r.next.UserExists(ctx, user)
+	r.observe("UserExists", start, err)
+	return err
+}
+
+func (r *instrumentedUserRepository) GetUserByEmail(ctx context.Context, email string) domain.User {
+	start := time.Now()
+	result := r.next.GetUserByEmail(ctx, email)
+	r.observe("GetUserByEmail", start, nil)
+	return result
+}
+
+func (r *instrumentedUserRepository) GetUserByID(ctx context.Context, userID string) (domain.User, error) {
+	start := time.Now()
+	result, err := r.next.GetUserByID(ctx, userID)
+	r.observe("GetUserByID", start, err)
+	return result, err
+}
+
+func (r *instrumentedUserRepository) PromoteUser(ctx context.Context, userID string) error {
+	start := time.Now()
+	err := r.next.PromoteUser(ctx, userID)
+	r.observe("PromoteUser", start, err)
+	return err
+}
+
+func (r *instrumentedUserRepository) MarkVerified(ctx context.Context, userID string) error {
+	start := time.Now()
+	err := r.next.MarkVerified(ctx, userID)
+	r.observe("MarkVerified", start, err)
+	return err
+}
+
+func (r *instrumentedUserRepository) UpdateProfile(ctx context.Context, userID string, update domain.ProfileUpdate) error {
+	start := time.Now()
+	err := r.next.UpdateProfile(ctx, userID, update)
+	r.observe("UpdateProfile", start, err)
+	return err
+}
+
+func (r *instrumentedUserRepository) UpdatePassword(ctx context.Context, userID string, hashedPassword string, sessionsRevokedAt time.Time) error {
+	start := time.Now()
+	err := r.next.UpdatePassword(ctx, userID, hashedPassword, sessionsRevokedAt)
+	r.observe("UpdatePassword", start, err)
+	return err
+}
+
+func (r *instrumentedUserRepository) UpdatePasswordHash(ctx context.Context, userID string, hashedPassword string) error {
+	start := time.Now()
+	err := r.next.UpdatePasswordHash(ctx, userID, hashedPassword)
+	r.observe("UpdatePasswordHash", start, err)
+	return err
+}
+
+// InstrumentLoginGuard counts logins by result. The guard hears about every
+// attempt: Check refusals are counted as blocked, then RecordFailure and
+// RecordSuccess tell wrong credentials from right ones.
+func InstrumentLoginGuard(guard domain.LoginGuard, metrics *Metrics) domain.LoginGuard {
+	return &instrumentedLoginGuard{next: guard, metrics: metrics}
+}
+
+type instrumentedLoginGuard struct {
+	next    domain.LoginGuard
+	metrics *Metrics
+}
+
+func (g *instrumentedLoginGuard) Check(ctx context.Context, email string, ip string, now time.Time) error {
+	err := g.next.Check(ctx, email, ip, now)
+	if err != nil {
+		g.metrics.logins.WithLabelValues("blocked").Inc()
+	}
+	return err
+}
+
+func (g *instrumentedLoginGuard) RecordFailure(ctx context.Context, email string, ip string, now time.Time) error {
+	g.metrics.logins.WithLabelValues("failure").Inc()
+	return g.next.RecordFailure(ctx, email, ip, now)
+}
+
+func (g *instrumentedLoginGuard) RecordSuccess(ctx context.Context, email string) error {
+	g.metrics.logins.WithLabelValues("success").Inc()
+	return g.next.RecordSuccess(ctx, email)
+}
+
+func (g *instrumentedLoginGuard) Unlock(ctx context.Context, email string) error {
+	return g.next.Unlock(ctx, email)
+}
@@ -2,26 +2,141 @@ package infrastructure
 
 import (
 	"errors"
-	"fmt"
 	"golang-clean-architecture/domain"
-	"os"
+	"log/slog"
+	"net/http"
 	"time"
 
-	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 )
 
-func GenerateToken(userInfo *domain.User) (string, error) {
-	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"email" : userInfo.Email,
-		"role" : userInfo.Role,
-		"exp" : time.Now().Add(time.Hour * 72).Unix(),
+// JWTConfig controls how tokens are signed and which ones are accepted.
+type JWTConfig struct {
+	Keys     *KeyRing
+	Issuer   string
+	Audience string
+	TTL      time.Duration
+}
+
+// LoadJWTConfig reads the token settings from the environment and the key
+// ring from JWT_KEYS_DIR. Without a key directory an ephemeral key is used,
+// so tokens stop working when the process restarts.
+func LoadJWTConfig() (JWTConfig, error) {
+	config := JWTConfig{
+		Issuer:   GetEnv("JWT_ISSUER", "task-manager"),
+		Audience: GetEnv("JWT_AUDIENCE", "task-manager-api"),
+		TTL:      GetEnvDuration("JWT_TTL", 72*time.Hour),
+	}
+	var err error
+	if dir := GetEnv("JWT_KEYS_DIR", ""); dir != "" {
+		config.Keys, err = LoadKeyRing(dir, GetEnv("JWT_ACTIVE_KEY", ""))
+	} else {
+		slog.Warn("JWT_KEYS_DIR is not set, signing tokens with an ephemeral key")
+		config.Keys, err = NewEphemeralKeyRing()
+	}
+	if err != nil {
+		return JWTConfig{}, err
+	}
+	return config, nil
+}
+
+// accessClaims is the payload of the access tokens we issue.
+type accessClaims struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+	// only set once the user passed their second factor, since users with
+	// 2FA can't get a token otherwise
+	TwoFactor bool `json:"2fa"`
+	jwt.RegisteredClaims
+}
+
+// JWTService issues and verifies access tokens signed with the active key of
+// a KeyRing.
+type JWTService struct {
+	Config JWTConfig
+	Now    func() time.Time
+}
+
+func NewJWTService(config JWTConfig) domain.TokenService {
+	return &JWTService{
+		Config: config,
+		Now:    time.Now,
+	}
+}
+
+func (js *JWTService) IssueToken(userInfo *domain.User) (domain.AccessToken, error) {
+	key, err := js.Config.Keys.Active()
+	if err != nil {
+		return domain.AccessToken{}, errors.New("error while generating token")
+	}
+
+	now := js.Now()
+	// the claim only holds whole seconds
+	expiresAt := now.Add(js.Config.TTL).Truncate(time.Second)
+	jwtToken := jwt.NewWithClaims(key.Method, accessClaims{
+		Email:     userInfo.Email,
+		Role:      userInfo.Role,
+		TwoFactor: userInfo.TwoFactorEnabled,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    js.Config.Issuer,
+			Audience:  jwt.ClaimStrings{js.Config.Audience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
 	})
+	jwtToken.Header["kid"] = key.ID
 
-	token, err := jwtToken.SignedString([]byte(os.Getenv("JWT_SECRET")))
+	token, err := jwtToken.SignedString(key.Private)
 	if err != nil {
-		fmt.Println(err)
-		return "", errors.New("error while generating token")
+		return domain.AccessToken{}, errors.New("error while generating token")
 	}
+	return domain.AccessToken{Token: token, ExpiresAt: expiresAt}, nil
+}
 
-	return token, nil
+// ParseToken verifies a token against the key named by its kid header and
+// checks its expiry, not-before time, issuer and audience.
+func (js *JWTService) ParseToken(tokenString string) (domain.TokenClaims, error) {
+	claims := &accessClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := js.Config.Keys.Key(kid)
+		if !ok {
+			return nil, errors.New("unknown signing key")
+		}
+		// the algorithm is pinned by the key, never taken from the token
+		if token.Method.Alg() != key.Method.Alg() {
+			return nil, errors.New("incompatible tokenization method")
+		}
+		return key.Public, nil
+	},
+		jwt.WithIssuer(js.Config.Issuer),
+		jwt.WithAudience(js.Config.Audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithTimeFunc(js.Now),
+	)
+	// iat is needed to tell whether the session was revoked since
+	if err != nil || !token.Valid || claims.IssuedAt == nil || claims.NotBefore == nil || claims.Email == "" || claims.Role == "" {
+		return domain.TokenClaims{}, errors.New("invalid token")
+	}
+
+	return domain.TokenClaims{
+		Email:     claims.Email,
+		Role:      claims.Role,
+		TwoFactor: claims.TwoFactor,
+		IssuedAt:  claims.IssuedAt.Time,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}, nil
+}
+
+// JWKSHandler serves the public keys of the ring at /.well-known/jwks.json.
+// Retired keys stay listed until they are removed from the ring, so
+// verifiers can keep checking tokens signed before a rotation.
+func JWKSHandler(keys *KeyRing) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=300")
+		c.IndentedJSON(http.StatusOK, keys.JWKS())
+	}
 }
\ No newline at end of file
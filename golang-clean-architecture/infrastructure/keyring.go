@@ -0,0 +1,235 @@
+package infrastructure
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SigningKey is one key of a KeyRing. Retired keys have no private half and
+// are only used to verify tokens they signed before being rotated out.
+type SigningKey struct {
+	ID      string
+	Method  jwt.SigningMethod
+	Private crypto.PrivateKey
+	Public  crypto.PublicKey
+}
+
+// KeyRing holds the keys tokens are verified with and the one new tokens are
+// signed with. Rotating means adding a key and making it active while the
+// previous one stays in the ring until the tokens it signed have expired.
+type KeyRing struct {
+	mutex    sync.RWMutex
+	keys     map[string]*SigningKey
+	activeID string
+}
+
+func NewKeyRing() *KeyRing {
+	return &KeyRing{keys: map[string]*SigningKey{}}
+}
+
+func (kr *KeyRing) Add(key *SigningKey) {
+	kr.mutex.Lock()
+	defer kr.mutex.Unlock()
+	kr.keys[key.ID] = key
+}
+
+// SetActive makes the key with the given id sign new tokens.
+func (kr *KeyRing) SetActive(id string) error {
+	kr.mutex.Lock()
+	defer kr.mutex.Unlock()
+	key, ok := kr.keys[id]
+	if !ok {
+		return fmt.Errorf("unknown signing key %q", id)
+	}
+	if key.Private == nil {
+		return fmt.Errorf("signing key %q has no private key", id)
+	}
+	kr.activeID = id
+	return nil
+}
+
+func (kr *KeyRing) Active() (*SigningKey, error) {
+	kr.mutex.RLock()
+	defer kr.mutex.RUnlock()
+	key, ok := kr.keys[kr.activeID]
+	if !ok {
+		return nil, errors.New("no active signing key")
+	}
+	return key, nil
+}
+
+func (kr *KeyRing) Key(id string) (*SigningKey, bool) {
+	kr.mutex.RLock()
+	defer kr.mutex.RUnlock()
+	key, ok := kr.keys[id]
+	return key, ok
+}
+
+// Remove drops a retired key; tokens it signed stop being accepted.
+func (kr *KeyRing) Remove(id string) error {
+	kr.mutex.Lock()
+	defer kr.mutex.Unlock()
+	if id == kr.activeID {
+		return errors.New("the active signing key can't be removed")
+	}
+	delete(kr.keys, id)
+	return nil
+}
+
+// JSONWebKey is the public half of a SigningKey as published in the JWKS.
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	Modulus   string `json:"n,omitempty"`
+	Exponent  string `json:"e,omitempty"`
+}
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// JWKS returns the public keys of the ring, sorted by id, for services that
+// verify our tokens.
+func (kr *KeyRing) JWKS() JSONWebKeySet {
+	kr.mutex.RLock()
+	defer kr.mutex.RUnlock()
+	set := JSONWebKeySet{Keys: []JSONWebKey{}}
+	for _, key := range kr.keys {
+		jwk := JSONWebKey{KeyID: key.ID, Algorithm: key.Method.Alg(), Use: "sig"}
+		switch public := key.Public.(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.Modulus = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.Exponent = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		default:
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].KeyID < set.Keys[j].KeyID })
+	return set
+}
+
+// GenerateEd25519Key creates a fresh signing key, for development setups that
+// haven't configured any.
+func GenerateEd25519Key(id string) (*SigningKey, error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, errors.New("error while generating signing key")
+	}
+	return &SigningKey{ID: id, Method: jwt.SigningMethodEdDSA, Private: private, Public: public}, nil
+}
+
+// NewEphemeralKeyRing holds a single freshly generated Ed25519 key. Tokens it
+// signs become unverifiable once the process exits.
+func NewEphemeralKeyRing() (*KeyRing, error) {
+	key, err := GenerateEd25519Key("ephemeral")
+	if err != nil {
+		return nil, err
+	}
+	ring := NewKeyRing()
+	ring.Add(key)
+	if err := ring.SetActive(key.ID); err != nil {
+		return nil, err
+	}
+	return ring, nil
+}
+
+// ParseSigningKey reads a PEM encoded RSA or Ed25519 key. Private keys
+// (PKCS#1 or PKCS#8) can sign and verify, public keys (PKIX) only verify.
+func ParseSigningKey(id string, data []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("signing key %q is not PEM encoded", id)
+	}
+
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("signing key %q has unsupported PEM type %q", id, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing signing key %q", id)
+	}
+
+	switch key := parsed.(type) {
+	case *rsa.PrivateKey:
+		return &SigningKey{ID: id, Method: jwt.SigningMethodRS256, Private: key, Public: &key.PublicKey}, nil
+	case *rsa.PublicKey:
+		return &SigningKey{ID: id, Method: jwt.SigningMethodRS256, Public: key}, nil
+	case ed25519.PrivateKey:
+		return &SigningKey{ID: id, Method: jwt.SigningMethodEdDSA, Private: key, Public: key.Public()}, nil
+	case ed25519.PublicKey:
+		return &SigningKey{ID: id, Method: jwt.SigningMethodEdDSA, Public: key}, nil
+	default:
+		return nil, fmt.Errorf("signing key %q must be an RSA or Ed25519 key", id)
+	}
+}
+
+// LoadKeyRing reads every *.pem file in dir, using the file name without
+// its extension as the key id, and activates activeID. When activeID is
+// empty the directory must hold exactly one private key.
+func LoadKeyRing(dir string, activeID string) (*KeyRing, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	ring := NewKeyRing()
+	privateIDs := []string{}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		id := strings.TrimSuffix(filepath.Base(path), ".pem")
+		key, err := ParseSigningKey(id, data)
+		if err != nil {
+			return nil, err
+		}
+		ring.Add(key)
+		if key.Private != nil {
+			privateIDs = append(privateIDs, id)
+		}
+	}
+
+	if activeID == "" {
+		if len(privateIDs) != 1 {
+			return nil, fmt.Errorf("%s holds %d private keys, choose the active one", dir, len(privateIDs))
+		}
+		activeID = privateIDs[0]
+	}
+	if err := ring.SetActive(activeID); err != nil {
+		return nil, err
+	}
+	return ring, nil
+}
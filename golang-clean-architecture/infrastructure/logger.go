@@ -0,0 +1,133 @@
+package infrastructure
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDKey is the context key RequestIDMiddleWare stores the id under.
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx that carries requestID.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFrom returns the request id ctx carries, or "" if it has none.
+func RequestIDFrom(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// NewLogger writes "json" or "text" formatted records of at least level to w.
+// Records logged with a context that carries a request id get a request_id
+// attribute, so use cases and repositories only need to pass the context on.
+func NewLogger(w io.Writer, format string, level slog.Level) *slog.Logger {
+	options := &slog.HandlerOptions{Level: level}
+	if strings.EqualFold(format, "json") {
+		return slog.New(&requestIDHandler{Handler: slog.NewJSONHandler(w, options)})
+	}
+	return slog.New(&requestIDHandler{Handler: slog.NewTextHandler(w, options)})
+}
+
+// requestIDHandler adds the request id of the record's context. Loggers that
+// already have it bound, such as the one LoggerFrom returns, are left alone
+// so that the attribute isn't written twice.
+type requestIDHandler struct {
+	slog.Handler
+	bound bool
+}
+
+func (h *requestIDHandler) Handle(ctx context.Context, record slog.Record) error {
+	if !h.bound {
+		if requestID := RequestIDFrom(ctx); requestID != "" {
+			record.AddAttrs(slog.String("request_id", requestID))
+		}
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *requestIDHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	bound := h.bound
+	for _, attr := range attrs {
+		bound = bound || attr.Key == "request_id"
+	}
+	return &requestIDHandler{Handler: h.Handler.WithAttrs(attrs), bound: bound}
+}
+
+func (h *requestIDHandler) WithGroup(name string) slog.Handler {
+	return &requestIDHandler{Handler: h.Handler.WithGroup(name), bound: h.bound}
+}
+
+// LoadLogger writes to stderr in the LOG_FORMAT ("text" or "json") at
+// LOG_LEVEL ("debug", "info", "warn" or "error").
+func LoadLogger() *slog.Logger {
+	level := slog.LevelInfo
+	value := GetEnv("LOG_LEVEL", "info")
+	if err := level.UnmarshalText([]byte(value)); err != nil {
+		level = slog.LevelInfo
+		slog.Warn("ignoring invalid environment variable", "key", "LOG_LEVEL", "value", value, "using", level)
+	}
+	return NewLogger(os.Stderr, GetEnv("LOG_FORMAT", "text"), level)
+}
+
+// requestIDPattern keeps IDs set by clients or proxies from smuggling
+// arbitrary text into the logs.
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// RequestIDMiddleWare reuses the X-Request-ID a proxy or client sent, or
+// assigns a new one, and echoes it in the response. The id is put on the
+// request context, so that lines logged with it further down carry the id,
+// and handlers can log through LoggerFrom. One line is logged for every
+// finished request. Query strings aren't logged since they
+// can hold tokens.
+func RequestIDMiddleWare(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !requestIDPattern.MatchString(requestID) {
+			generated, err := GenerateRandomToken(16)
+			if err != nil {
+				logger.Error("error while generating request id", "error", err)
+			}
+			requestID = generated
+		}
+		c.Header(RequestIDHeader, requestID)
+		c.Set("RequestID", requestID)
+		c.Request = c.Request.WithContext(WithRequestID(c.Request.Context(), requestID))
+		c.Set("Logger", logger.With("request_id", requestID))
+
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		if status >= 500 {
+			level = slog.LevelError
+		}
+		logger.Log(c.Request.Context(), level, "request",
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"status", status,
+			"duration", time.Since(start),
+			"client_ip", c.ClientIP(),
+		)
+	}
+}
+
+// LoggerFrom returns the logger RequestIDMiddleWare set up for the request,
+// or the default logger outside of one.
+func LoggerFrom(c *gin.Context) *slog.Logger {
+	if logger, ok := c.Get("Logger"); ok {
+		return logger.(*slog.Logger)
+	}
+	return slog.Default()
+}
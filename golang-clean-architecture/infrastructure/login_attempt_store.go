@@ -0,0 +1,81 @@
+package infrastructure
+
+import (
+	"context"
+	"golang-clean-architecture/domain"
+	"sync"
+	"time"
+)
+
+// MemoryLoginAttemptStore keeps failed login counters in process memory. It
+// is the default store and is fine for a single instance; deployments with
+// several instances should use the MongoDB store so that an attacker can't
+// spread attempts across them.
+type MemoryLoginAttemptStore struct {
+	mutex    sync.Mutex
+	attempts map[string]domain.LoginAttempts
+	pruned   time.Time
+	// Retention is how long an idle entry is kept before it is pruned.
+	Retention time.Duration
+}
+
+func NewMemoryLoginAttemptStore(retention time.Duration) *MemoryLoginAttemptStore {
+	return &MemoryLoginAttemptStore{
+		attempts:  map[string]domain.LoginAttempts{},
+		Retention: retention,
+	}
+}
+
+func (ms *MemoryLoginAttemptStore) Get(ctx context.Context, key string) (domain.LoginAttempts, error) {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+	return ms.attempts[key], nil
+}
+
+// RecordFailure counts a failure, restarting the count when the previous
+// failure is older than window.
+func (ms *MemoryLoginAttemptStore) RecordFailure(ctx context.Context, key string, now time.Time, window time.Duration) (domain.LoginAttempts, error) {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+	ms.prune(now)
+
+	attempts := ms.attempts[key]
+	if now.Sub(attempts.LastFailure) > window {
+		attempts.Failures = 0
+	}
+	attempts.Failures++
+	attempts.LastFailure = now
+	ms.attempts[key] = attempts
+	return attempts, nil
+}
+
+func (ms *MemoryLoginAttemptStore) Lock(ctx context.Context, key string, until time.Time) error {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+	attempts := ms.attempts[key]
+	attempts.LockedUntil = until
+	ms.attempts[key] = attempts
+	return nil
+}
+
+func (ms *MemoryLoginAttemptStore) Reset(ctx context.Context, key string) error {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+	delete(ms.attempts, key)
+	return nil
+}
+
+// prune drops entries that are neither recent nor locked, so that attempts
+// against many different emails don't grow the map without bound. It runs
+// at most once per minute to keep failures cheap under attack.
+func (ms *MemoryLoginAttemptStore) prune(now time.Time) {
+	if now.Sub(ms.pruned) < time.Minute {
+		return
+	}
+	ms.pruned = now
+	for key, attempts := range ms.attempts {
+		if now.Sub(attempts.LastFailure) > ms.Retention && now.After(attempts.LockedUntil) {
+			delete(ms.attempts, key)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang-clean-architecture/domain"
+	"log/slog"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LogMailer writes mail to the default logger instead of sending it. It is
+// meant for local development. Bodies hold single use tokens, so they are
+// only logged at debug level, where the links can be copied from the log.
+type LogMailer struct{}
+
+func (LogMailer) Send(ctx context.Context, mail domain.Mail) error {
+	slog.InfoContext(ctx, "mail", "to", mail.To, "subject", mail.Subject)
+	slog.DebugContext(ctx, "mail body", "to", mail.To, "body", mail.Body)
+	return nil
+}
+
+// FileMailer writes every mail as a separate .eml file in Dir.
+type FileMailer struct {
+	Dir string
+}
+
+func (fm *FileMailer) Send(ctx context.Context, mail domain.Mail) error {
+	if err := os.MkdirAll(fm.Dir, 0o700); err != nil {
+		return errors.New("error while creating mail directory")
+	}
+
+	name := fmt.Sprintf("%d.eml", time.Now().UnixNano())
+	err := os.WriteFile(filepath.Join(fm.Dir, name), []byte(formatMail("", mail)), 0o600)
+	if err != nil {
+		return errors.New("error while writing mail")
+	}
+	return nil
+}
+
+// SMTPMailer delivers mail through an SMTP relay.
+type SMTPMailer struct {
+	Addr     string
+	From     string
+	Username string
+	Password string
+}
+
+func (sm *SMTPMailer) Send(ctx context.Context, mail domain.Mail) error {
+	var auth smtp.Auth
+	if sm.Username != "" {
+		host := strings.Split(sm.Addr, ":")[0]
+		auth = smtp.PlainAuth("", sm.Username, sm.Password, host)
+	}
+
+	err := smtp.SendMail(sm.Addr, auth, sm.From, []string{mail.To}, []byte(formatMail(sm.From, mail)))
+	if err != nil {
+		return errors.New("error while sending mail")
+	}
+	return nil
+}
+
+func formatMail(from string, mail domain.Mail) string {
+	message := ""
+	if from != "" {
+		message += "From: " + from + "\r\n"
+	}
+	return message +
+		"To: " + mail.To + "\r\n" +
+		"Subject: " + mail.Subject + "\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"\r\n" +
+		mail.Body + "\r\n"
+}
+
+// FakeMailer keeps mail in memory so tests can assert on it. Setting Err
+// makes every Send call fail with it.
+type FakeMailer struct {
+	mutex sync.Mutex
+	Mails []domain.Mail
+	Err   error
+}
+
+func (fm *FakeMailer) Send(ctx context.Context, mail domain.Mail) error {
+	fm.mutex.Lock()
+	defer fm.mutex.Unlock()
+	if fm.Err != nil {
+		return fm.Err
+	}
+	fm.Mails = append(fm.Mails, mail)
+	return nil
+}
+
+func (fm *FakeMailer) Sent() []domain.Mail {
+	fm.mutex.Lock()
+	defer fm.mutex.Unlock()
+	return append([]domain.Mail{}, fm.Mails...)
+}
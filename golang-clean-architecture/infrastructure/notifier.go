@@ -0,0 +1,115 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"golang-clean-architecture/domain"
+	"log/slog"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LogNotifier writes notifications to the default logger. It is the default
+// when no other notifier is configured.
+type LogNotifier struct{}
+
+func (LogNotifier) Notify(ctx context.Context, notification domain.Notification) error {
+	slog.InfoContext(ctx, "notification", "type", notification.Type, "subject", notification.Subject, "message", notification.Message)
+	return nil
+}
+
+// WebhookNotifier POSTs every notification as JSON to a fixed URL.
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		URL:    url,
+		Client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (wn *WebhookNotifier) Notify(ctx context.Context, notification domain.Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return errors.New("error while encoding notification")
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, wn.URL, bytes.NewReader(body))
+	if err != nil {
+		return errors.New("error while sending notification")
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := wn.Client.Do(request)
+	if err != nil {
+		return errors.New("error while sending notification")
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("notification webhook responded with %d", response.StatusCode)
+	}
+	return nil
+}
+
+// SMTPNotifier emails every notification to a fixed list of recipients.
+type SMTPNotifier struct {
+	Addr     string
+	From     string
+	To       []string
+	Username string
+	Password string
+}
+
+func (sn *SMTPNotifier) Notify(ctx context.Context, notification domain.Notification) error {
+	var auth smtp.Auth
+	if sn.Username != "" {
+		host := strings.Split(sn.Addr, ":")[0]
+		auth = smtp.PlainAuth("", sn.Username, sn.Password, host)
+	}
+
+	message := "From: " + sn.From + "\r\n" +
+		"To: " + strings.Join(sn.To, ", ") + "\r\n" +
+		"Subject: " + notification.Subject + "\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"\r\n" +
+		notification.Message + "\r\n"
+
+	err := smtp.SendMail(sn.Addr, auth, sn.From, sn.To, []byte(message))
+	if err != nil {
+		return errors.New("error while sending notification email")
+	}
+	return nil
+}
+
+// FakeNotifier keeps notifications in memory so tests can assert on them.
+// Setting Err makes every Notify call fail with it.
+type FakeNotifier struct {
+	mutex         sync.Mutex
+	Notifications []domain.Notification
+	Err           error
+}
+
+func (fn *FakeNotifier) Notify(ctx context.Context, notification domain.Notification) error {
+	fn.mutex.Lock()
+	defer fn.mutex.Unlock()
+	if fn.Err != nil {
+		return fn.Err
+	}
+	fn.Notifications = append(fn.Notifications, notification)
+	return nil
+}
+
+func (fn *FakeNotifier) Sent() []domain.Notification {
+	fn.mutex.Lock()
+	defer fn.mutex.Unlock()
+	return append([]domain.Notification{}, fn.Notifications...)
+}
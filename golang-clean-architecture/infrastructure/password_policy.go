@@ -0,0 +1,120 @@
+package infrastructure
+
+import (
+	_ "embed"
+	"fmt"
+	"golang-clean-architecture/domain"
+	"strings"
+	"unicode"
+)
+
+// commonPasswords is a list of passwords that show up first in every
+// credential stuffing dictionary, one per line in lower case.
+//
+//go:embed common_passwords.txt
+var commonPasswords string
+
+var commonPasswordSet = func() map[string]struct{} {
+	set := map[string]struct{}{}
+	for _, password := range strings.Split(commonPasswords, "\n") {
+		if password = strings.TrimSpace(password); password != "" {
+			set[password] = struct{}{}
+		}
+	}
+	return set
+}()
+
+// PasswordRules configure a PasswordPolicy. MinLength counts characters while
+// MaxBytes counts bytes, because bcrypt ignores everything after the 72nd
+// byte and hashes made with it still have to verify.
+type PasswordRules struct {
+	MinLength int
+	MaxBytes  int
+	// MinCharacterClasses is how many of lower case letters, upper case
+	// letters, digits and symbols a password has to mix. 0 turns it off.
+	MinCharacterClasses int
+	RejectEmail         bool
+	RejectCommon        bool
+}
+
+func DefaultPasswordRules() PasswordRules {
+	return PasswordRules{MinLength: 8, MaxBytes: 72, RejectEmail: true, RejectCommon: true}
+}
+
+// LoadPasswordRules reads PASSWORD_MIN_LENGTH, PASSWORD_MAX_BYTES,
+// PASSWORD_MIN_CHARACTER_CLASSES, PASSWORD_REJECT_EMAIL and
+// PASSWORD_REJECT_COMMON.
+func LoadPasswordRules() PasswordRules {
+	rules := DefaultPasswordRules()
+	rules.MinLength = GetEnvInt("PASSWORD_MIN_LENGTH", rules.MinLength)
+	rules.MaxBytes = GetEnvInt("PASSWORD_MAX_BYTES", rules.MaxBytes)
+	rules.MinCharacterClasses = GetEnvInt("PASSWORD_MIN_CHARACTER_CLASSES", rules.MinCharacterClasses)
+	rules.RejectEmail = GetEnvBool("PASSWORD_REJECT_EMAIL", rules.RejectEmail)
+	rules.RejectCommon = GetEnvBool("PASSWORD_REJECT_COMMON", rules.RejectCommon)
+	return rules
+}
+
+type PasswordPolicy struct {
+	Rules PasswordRules
+}
+
+func NewPasswordPolicy(rules PasswordRules) domain.PasswordPolicy {
+	return &PasswordPolicy{Rules: rules}
+}
+
+// Validate checks password against every rule and reports all the broken
+// ones in a *domain.PasswordPolicyError. The email rule is skipped when email
+// is empty.
+func (pp *PasswordPolicy) Validate(password string, email string) error {
+	var violations []domain.PasswordViolation
+	violate := func(rule string, format string, args ...interface{}) {
+		violations = append(violations, domain.PasswordViolation{Rule: rule, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len([]rune(password)) < pp.Rules.MinLength {
+		violate("min_length", "password must be at least %d characters long", pp.Rules.MinLength)
+	}
+	if len(password) > pp.Rules.MaxBytes {
+		violate("max_length", "password must be at most %d bytes long", pp.Rules.MaxBytes)
+	}
+	if characterClasses(password) < pp.Rules.MinCharacterClasses {
+		violate("character_classes", "password must mix at least %d of lower case letters, upper case letters, digits and symbols", pp.Rules.MinCharacterClasses)
+	}
+
+	folded := strings.ToLower(strings.TrimSpace(password))
+	if pp.Rules.RejectEmail && email != "" {
+		email = strings.ToLower(strings.TrimSpace(email))
+		localPart := email
+		if at := strings.LastIndex(email, "@"); at >= 0 {
+			localPart = email[:at]
+		}
+		if folded == email || folded == localPart {
+			violate("email", "password must not be your email address")
+		}
+	}
+	if _, common := commonPasswordSet[folded]; pp.Rules.RejectCommon && common {
+		violate("common", "password is too common")
+	}
+
+	if len(violations) > 0 {
+		return &domain.PasswordPolicyError{Violations: violations}
+	}
+	return nil
+}
+
+func characterClasses(password string) int {
+	var lower, upper, digit, symbol int
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+	return lower + upper + digit + symbol
+}
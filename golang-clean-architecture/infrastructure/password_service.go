@@ -1,24 +1,122 @@
 package infrastructure
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
 	"errors"
+	"fmt"
 	"golang-clean-architecture/domain"
+	"strings"
 
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
-func HashPassword(password string) ([]byte, error) {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
-		return []byte{}, errors.New("error while hashing password")
+// Argon2Params are the Argon2id costs new hashes are made with. Memory is in
+// KiB.
+type Argon2Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2Params follows the OWASP recommendation for Argon2id.
+func DefaultArgon2Params() Argon2Params {
+	return Argon2Params{Memory: 64 * 1024, Iterations: 3, Parallelism: 2, SaltLength: 16, KeyLength: 32}
+}
+
+// LoadArgon2Params reads PASSWORD_ARGON2_MEMORY (KiB),
+// PASSWORD_ARGON2_ITERATIONS and PASSWORD_ARGON2_PARALLELISM. Raising any of
+// them upgrades stored hashes as their owners log in.
+func LoadArgon2Params() Argon2Params {
+	params := DefaultArgon2Params()
+	params.Memory = uint32(GetEnvInt("PASSWORD_ARGON2_MEMORY", int(params.Memory)))
+	params.Iterations = uint32(GetEnvInt("PASSWORD_ARGON2_ITERATIONS", int(params.Iterations)))
+	params.Parallelism = uint8(GetEnvInt("PASSWORD_ARGON2_PARALLELISM", int(params.Parallelism)))
+	return params
+}
+
+const argon2idPrefix = "$argon2id$"
+
+// PasswordHasher makes Argon2id hashes in the PHC string format, e.g.
+// "$argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>", so every hash carries the
+// parameters it was made with. bcrypt hashes from before the switch still
+// verify and are reported as needing a rehash.
+type PasswordHasher struct {
+	Params Argon2Params
+}
+
+func NewPasswordHasher(params Argon2Params) domain.PasswordHasher {
+	return &PasswordHasher{Params: params}
+}
+
+func (ph *PasswordHasher) Hash(password string) (string, error) {
+	salt := make([]byte, ph.Params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", errors.New("error while hashing password")
 	}
-	return hashedPassword, nil
+	key := argon2.IDKey([]byte(password), salt, ph.Params.Iterations, ph.Params.Memory, ph.Params.Parallelism, ph.Params.KeyLength)
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2idPrefix, argon2.Version,
+		ph.Params.Memory, ph.Params.Iterations, ph.Params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
 }
 
-func ComparePasswords(existingUser *domain.User, userInfo *domain.User) error {
-	err := bcrypt.CompareHashAndPassword([]byte(existingUser.Password), []byte(userInfo.Password))
+func (ph *PasswordHasher) Compare(hash string, password string) error {
+	if !strings.HasPrefix(hash, argon2idPrefix) {
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) != nil {
+			return errors.New("passwords don't match")
+		}
+		return nil
+	}
+
+	params, salt, key, err := decodeArgon2Hash(hash)
 	if err != nil {
 		return errors.New("passwords don't match")
 	}
+	computed := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+	if subtle.ConstantTimeCompare(computed, key) != 1 {
+		return errors.New("passwords don't match")
+	}
 	return nil
-}
\ No newline at end of file
+}
+
+// NeedsRehash reports whether hash was made with another algorithm or with
+// parameters other than the current ones.
+func (ph *PasswordHasher) NeedsRehash(hash string) bool {
+	if !strings.HasPrefix(hash, argon2idPrefix) {
+		return true
+	}
+	params, _, _, err := decodeArgon2Hash(hash)
+	return err != nil || params != ph.Params
+}
+
+func decodeArgon2Hash(hash string) (Argon2Params, []byte, []byte, error) {
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", salt, key
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return Argon2Params{}, nil, nil, errors.New("malformed argon2id hash")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return Argon2Params{}, nil, nil, errors.New("unsupported argon2 version")
+	}
+	var params Argon2Params
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return Argon2Params{}, nil, nil, errors.New("malformed argon2id hash")
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return Argon2Params{}, nil, nil, errors.New("malformed argon2id hash")
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return Argon2Params{}, nil, nil, errors.New("malformed argon2id hash")
+	}
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+	return params, salt, key, nil
+}
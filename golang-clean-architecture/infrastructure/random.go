@@ -0,0 +1,25 @@
+package infrastructure
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+)
+
+// GenerateRandomToken returns size cryptographically random bytes, hex encoded.
+func GenerateRandomToken(size int) (string, error) {
+	buffer := make([]byte, size)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", errors.New("error while generating random token")
+	}
+	return hex.EncodeToString(buffer), nil
+}
+
+
+// HashToken returns the hex encoded SHA-256 of a random token. Tokens carry
+// enough entropy that a fast, unsalted hash is sufficient for storage.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
\ No newline at end of file
@@ -0,0 +1,162 @@
+package infrastructure
+
+import (
+	"errors"
+	"golang-clean-architecture/domain"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+	per     time.Duration
+}
+
+// MemoryRateLimitStore keeps a token bucket per key in process memory, so
+// limits apply per instance.
+type MemoryRateLimitStore struct {
+	mutex   sync.Mutex
+	buckets map[string]*tokenBucket
+	pruned  time.Time
+}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		buckets: map[string]*tokenBucket{},
+	}
+}
+
+func (ms *MemoryRateLimitStore) Take(key string, limit domain.RateLimit, now time.Time) (domain.RateLimitResult, error) {
+	if limit.Requests <= 0 || limit.Per <= 0 {
+		return domain.RateLimitResult{}, errors.New("invalid rate limit")
+	}
+	capacity := float64(limit.Requests)
+	perToken := limit.Per / time.Duration(limit.Requests)
+
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+	ms.prune(now)
+
+	bucket, ok := ms.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: capacity, updated: now}
+		ms.buckets[key] = bucket
+	}
+	bucket.per = limit.Per
+	if elapsed := now.Sub(bucket.updated); elapsed > 0 {
+		bucket.tokens = math.Min(capacity, bucket.tokens+float64(elapsed)/float64(perToken))
+		bucket.updated = now
+	}
+
+	result := domain.RateLimitResult{}
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - bucket.tokens) * float64(perToken))
+	}
+	result.Remaining = int(bucket.tokens)
+	result.ResetAfter = time.Duration((capacity - bucket.tokens) * float64(perToken))
+	return result, nil
+}
+
+// prune drops buckets that have been idle long enough to be full again, at
+// most once per minute.
+func (ms *MemoryRateLimitStore) prune(now time.Time) {
+	if now.Sub(ms.pruned) < time.Minute {
+		return
+	}
+	ms.pruned = now
+	for key, bucket := range ms.buckets {
+		if now.Sub(bucket.updated) > bucket.per {
+			delete(ms.buckets, key)
+		}
+	}
+}
+
+// RateLimiter builds middlewares that throttle requests with token buckets
+// held in a RateLimitStore.
+type RateLimiter struct {
+	Store domain.RateLimitStore
+}
+
+func NewRateLimiter(store domain.RateLimitStore) *RateLimiter {
+	return &RateLimiter{Store: store}
+}
+
+// Limit throttles requests to limit per client, counted separately for each
+// scope. Clients are identified by their email once AuthMiddleWare has run
+// and by IP address otherwise, so it should come after AuthMiddleWare on
+// authenticated routes.
+func (rl *RateLimiter) Limit(scope string, limit domain.RateLimit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := scope + ":ip:" + c.ClientIP()
+		if AuthUser, ok := c.Get("AuthorizedUser"); ok {
+			key = scope + ":user:" + AuthUser.(*domain.AuthenticatedUser).Email
+		}
+
+		result, err := rl.Store.Take(key, limit, time.Now())
+		if err != nil {
+			// an unavailable limiter shouldn't take the API down with it
+			LoggerFrom(c).Error("error while applying rate limit", "error", err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(limit.Requests))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			c.IndentedJSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded, try again later"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func ceilSeconds(duration time.Duration) int {
+	return int(math.Ceil(duration.Seconds()))
+}
+
+// ParseRateLimit reads limits written as "requests/period", for example
+// "100/1m" or "5/30s".
+func ParseRateLimit(value string) (domain.RateLimit, error) {
+	requests, period, found := strings.Cut(strings.TrimSpace(value), "/")
+	if !found {
+		return domain.RateLimit{}, errors.New("rate limit must look like 100/1m")
+	}
+	count, err := strconv.Atoi(requests)
+	if err != nil || count <= 0 {
+		return domain.RateLimit{}, errors.New("rate limit request count must be a positive integer")
+	}
+	per, err := time.ParseDuration(period)
+	if err != nil || per <= 0 {
+		return domain.RateLimit{}, errors.New("rate limit period must be a positive duration")
+	}
+	return domain.RateLimit{Requests: count, Per: per}, nil
+}
+
+// GetEnvRateLimit reads a rate limit such as "100/1m" from the environment,
+// falling back to the given default when the variable is unset or malformed.
+func GetEnvRateLimit(key string, fallback domain.RateLimit) domain.RateLimit {
+	value := GetEnv(key, "")
+	if value == "" {
+		return fallback
+	}
+	limit, err := ParseRateLimit(value)
+	if err != nil {
+		slog.Warn("ignoring invalid environment variable", "key", key, "value", value, "error", err)
+		return fallback
+	}
+	return limit
+}
@@ -0,0 +1,188 @@
+package infrastructure
+
+import (
+	"errors"
+	"golang-clean-architecture/domain"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// ParseRecurrenceRule parses the subset of iCalendar RRULEs we support:
+// FREQ=DAILY|WEEKLY|MONTHLY with optional INTERVAL, BYDAY (daily and weekly
+// rules only), and either UNTIL or COUNT. A leading "RRULE:" is accepted.
+func ParseRecurrenceRule(rule string) (*domain.RecurrenceRule, error) {
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	parsed := &domain.RecurrenceRule{Interval: 1}
+
+	for _, part := range strings.Split(rule, ";") {
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, errors.New("invalid recurrence rule: malformed part " + part)
+		}
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			value = strings.ToUpper(value)
+			if value != "DAILY" && value != "WEEKLY" && value != "MONTHLY" {
+				return nil, errors.New("invalid recurrence rule: unsupported frequency " + value)
+			}
+			parsed.Frequency = value
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 {
+				return nil, errors.New("invalid recurrence rule: INTERVAL must be a positive integer")
+			}
+			parsed.Interval = interval
+		case "COUNT":
+			count, err := strconv.Atoi(value)
+			if err != nil || count < 1 {
+				return nil, errors.New("invalid recurrence rule: COUNT must be a positive integer")
+			}
+			parsed.Count = count
+		case "UNTIL":
+			until, err := parseRuleDate(value)
+			if err != nil {
+				return nil, err
+			}
+			parsed.Until = until
+		case "BYDAY":
+			for _, code := range strings.Split(strings.ToUpper(value), ",") {
+				weekday, ok := weekdayCodes[code]
+				if !ok {
+					return nil, errors.New("invalid recurrence rule: unsupported BYDAY value " + code)
+				}
+				parsed.ByDay = append(parsed.ByDay, weekday)
+			}
+		default:
+			return nil, errors.New("invalid recurrence rule: unsupported part " + key)
+		}
+	}
+
+	if parsed.Frequency == "" {
+		return nil, errors.New("invalid recurrence rule: FREQ is required")
+	}
+	if parsed.Count > 0 && !parsed.Until.IsZero() {
+		return nil, errors.New("invalid recurrence rule: COUNT and UNTIL can't be combined")
+	}
+	if parsed.Frequency == "MONTHLY" && len(parsed.ByDay) > 0 {
+		return nil, errors.New("invalid recurrence rule: BYDAY is only supported for DAILY and WEEKLY rules")
+	}
+	return parsed, nil
+}
+
+func parseRuleDate(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			if layout == "20060102" {
+				// a bare date includes the whole day
+				parsed = parsed.Add(24*time.Hour - time.Second)
+			}
+			return parsed, nil
+		}
+	}
+	return time.Time{}, errors.New("invalid recurrence rule: UNTIL must be a date like 20240131 or 20240131T090000Z")
+}
+
+// NextOccurrence returns the occurrence that follows previous, which is the
+// occurrence-th one of the series. The second result is false once the series
+// is exhausted by COUNT or UNTIL.
+func NextOccurrence(rule *domain.RecurrenceRule, previous time.Time, occurrence int) (time.Time, bool) {
+	if rule.Count > 0 && occurrence >= rule.Count {
+		return time.Time{}, false
+	}
+
+	var next time.Time
+	switch rule.Frequency {
+	case "DAILY":
+		next = nextDaily(rule, previous)
+	case "WEEKLY":
+		next = nextWeekly(rule, previous)
+	case "MONTHLY":
+		next = nextMonthly(rule, previous)
+	}
+
+	if next.IsZero() || (!rule.Until.IsZero() && next.After(rule.Until)) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+func nextDaily(rule *domain.RecurrenceRule, previous time.Time) time.Time {
+	// the weekdays repeat at least every 7 steps, so a matching day exists
+	// within 7 candidates or not at all
+	for step := 1; step <= 7; step++ {
+		candidate := previous.AddDate(0, 0, step*rule.Interval)
+		if matchesByDay(rule, candidate) {
+			return candidate
+		}
+	}
+	return time.Time{}
+}
+
+func nextWeekly(rule *domain.RecurrenceRule, previous time.Time) time.Time {
+	if len(rule.ByDay) == 0 {
+		return previous.AddDate(0, 0, 7*rule.Interval)
+	}
+
+	previousWeek := startOfWeek(previous)
+	for day := 1; day <= 7*rule.Interval+7; day++ {
+		candidate := previous.AddDate(0, 0, day)
+		weeks := int(startOfWeek(candidate).Sub(previousWeek).Hours()/24+0.5) / 7
+		if weeks%rule.Interval == 0 && matchesByDay(rule, candidate) {
+			return candidate
+		}
+	}
+	return time.Time{}
+}
+
+func nextMonthly(rule *domain.RecurrenceRule, previous time.Time) time.Time {
+	year, month, day := previous.Date()
+	// months without the anchor day (e.g. the 31st) are skipped, as RFC 5545
+	// requires; 12 attempts always reach a month that has it
+	for step := 1; step <= 12; step++ {
+		firstOfMonth := time.Date(year, month+time.Month(step*rule.Interval), 1, 0, 0, 0, 0, previous.Location())
+		if day <= daysIn(firstOfMonth) {
+			return time.Date(firstOfMonth.Year(), firstOfMonth.Month(), day,
+				previous.Hour(), previous.Minute(), previous.Second(), previous.Nanosecond(), previous.Location())
+		}
+	}
+	return time.Time{}
+}
+
+func matchesByDay(rule *domain.RecurrenceRule, candidate time.Time) bool {
+	if len(rule.ByDay) == 0 {
+		return true
+	}
+	for _, weekday := range rule.ByDay {
+		if candidate.Weekday() == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// startOfWeek returns midnight of the Monday starting t's week, the RRULE
+// default WKST.
+func startOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	year, month, day := t.AddDate(0, 0, -offset).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+func daysIn(firstOfMonth time.Time) int {
+	return firstOfMonth.AddDate(0, 1, -1).Day()
+}
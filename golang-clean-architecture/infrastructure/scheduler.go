@@ -0,0 +1,23 @@
+package infrastructure
+
+import (
+	"context"
+	"time"
+)
+
+// RunEvery calls job straight away and then once per interval until ctx is
+// cancelled. It blocks, so callers usually start it in its own goroutine.
+func RunEvery(ctx context.Context, interval time.Duration, job func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	job()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			job()
+		}
+	}
+}
@@ -0,0 +1,336 @@
+package infrastructure
+
+import (
+	"context"
+	"golang-clean-architecture/domain"
+	"time"
+)
+
+// TraceTaskUseCase starts a span for every call to tasks.
+func TraceTaskUseCase(tasks domain.TaskUseCase, tracing *Tracing) domain.TaskUseCase {
+	return &tracedTaskUseCase{next: tasks, tracing: tracing}
+}
+
+type tracedTaskUseCase struct {
+	next    domain.TaskUseCase
+	tracing *Tracing
+}
+
+func (u *tracedTaskUseCase) GetTasks(ctx context.Context) ([]*domain.Task, error) {
+	ctx, span := u.tracing.start(ctx, "TaskUseCase.GetTasks")
+	result, err := u.next.GetTasks(ctx)
+	u.tracing.end(span, err)
+	return result, err
+}
+
+func (u *tracedTaskUseCase) GetTask(ctx context.Context, taskID string) (domain.Task, error) {
+	ctx, span := u.tracing.start(ctx, "TaskUseCase.GetTask")
+	result, err := u.next.GetTask(ctx, taskID)
+	u.tracing.end(span, err)
+	return result, err
+}
+
+func (u *tracedTaskUseCase) PostTask(ctx context.Context, task *domain.Task) error {
+	ctx, span := u.tracing.start(ctx, "TaskUseCase.PostTask")
+	err := u.next.PostTask(ctx, task)
+	u.tracing.end(span, err)
+	return err
+}
+
+func (u *tracedTaskUseCase) DeleteTask(ctx context.Context, taskID string) error {
+	ctx, span := u.tracing.start(ctx, "TaskUseCase.DeleteTask")
+	err := u.next.DeleteTask(ctx, taskID)
+	u.tracing.end(span, err)
+	return err
+}
+
+func (u *tracedTaskUseCase) UpdateTask(ctx context.Context, taskID string, task *domain.Task) error {
+	ctx, span := u.tracing.start(ctx, "TaskUseCase.UpdateTask")
+	err := u.next.UpdateTask(ctx, taskID, task)
+	u.tracing.end(span, err)
+	return err
+}
+
+func (u *tracedTaskUseCase) SearchTasks(ctx context.Context, query string, limit int) ([]*domain.TaskSearchResult, error) {
+	ctx, span := u.tracing.start(ctx, "TaskUseCase.SearchTasks")
+	result, err := u.next.SearchTasks(ctx, query, limit)
+	u.tracing.end(span, err)
+	return result, err
+}
+
+func (u *tracedTaskUseCase) UpdateRecurrence(ctx context.Context, taskID string, rule string) error {
+	ctx, span := u.tracing.start(ctx, "TaskUseCase.UpdateRecurrence")
+	err := u.next.UpdateRecurrence(ctx, taskID, rule)
+	u.tracing.end(span, err)
+	return err
+}
+
+func (u *tracedTaskUseCase) StopRecurrence(ctx context.Context, taskID string) error {
+	ctx, span := u.tracing.start(ctx, "TaskUseCase.StopRecurrence")
+	err := u.next.StopRecurrence(ctx, taskID)
+	u.tracing.end(span, err)
+	return err
+}
+
+func (u *tracedTaskUseCase) MaterializeRecurringTasks(ctx context.Context, until time.Time) error {
+	ctx, span := u.tracing.start(ctx, "TaskUseCase.MaterializeRecurringTasks")
+	err := u.next.MaterializeRecurringTasks(ctx, until)
+	u.tracing.end(span, err)
+	return err
+}
+
+// TraceUserUseCase starts a span for every call to users.
+func TraceUserUseCase(users domain.UserUseCase, tracing *Tracing) domain.UserUseCase {
+	return &tracedUserUseCase{next: users, tracing: tracing}
+}
+
+type tracedUserUseCase struct {
+	next    domain.UserUseCase
+	tracing *Tracing
+}
+
+func (u *tracedUserUseCase) Register(ctx context.Context, user *domain.User) error {
+	ctx, span := u.tracing.start(ctx, "UserUseCase.Register")
+	err := u.next.Register(ctx, user)
+	u.tracing.end(span, err)
+	return err
+}
+
+func (u *tracedUserUseCase) Login(ctx context.Context, user *domain.User, clientIP string) (domain.LoginResult, error) {
+	ctx, span := u.tracing.start(ctx, "UserUseCase.Login")
+	result, err := u.next.Login(ctx, user, clientIP)
+	u.tracing.end(span, err)
+	return result, err
+}
+
+func (u *tracedUserUseCase) CompleteTwoFactorLogin(ctx context.Context, challenge string, code string, clientIP string) (domain.AccessToken, error) {
+	ctx, span := u.tracing.start(ctx, "UserUseCase.CompleteTwoFactorLogin")
+	result, err := u.next.CompleteTwoFactorLogin(ctx, challenge, code, clientIP)
+	u.tracing.end(span, err)
+	return result, err
+}
+
+func (u *tracedUserUseCase) PromoteUser(ctx context.Context, userID string) error {
+	ctx, span := u.tracing.start(ctx, "UserUseCase.PromoteUser")
+	err := u.next.PromoteUser(ctx, userID)
+	u.tracing.end(span, err)
+	return err
+}
+
+func (u *tracedUserUseCase) UnlockAccount(ctx context.Context, userID string) error {
+	ctx, span := u.tracing.start(ctx, "UserUseCase.UnlockAccount")
+	err := u.next.UnlockAccount(ctx, userID)
+	u.tracing.end(span, err)
+	return err
+}
+
+func (u *tracedUserUseCase) GetCurrentUser(ctx context.Context, email string) (domain.User, error) {
+	ctx, span := u.tracing.start(ctx, "UserUseCase.GetCurrentUser")
+	result, err := u.next.GetCurrentUser(ctx, email)
+	u.tracing.end(span, err)
+	return result, err
+}
+
+func (u *tracedUserUseCase) UpdateProfile(ctx context.Context, email string, update domain.ProfileUpdate) (domain.User, error) {
+	ctx, span := u.tracing.start(ctx, "UserUseCase.UpdateProfile")
+	result, err := u.next.UpdateProfile(ctx, email, update)
+	u.tracing.end(span, err)
+	return result, err
+}
+
+func (u *tracedUserUseCase) ChangePassword(ctx context.Context, email string, currentPassword string, newPassword string) (domain.AccessToken, error) {
+	ctx, span := u.tracing.start(ctx, "UserUseCase.ChangePassword")
+	result, err := u.next.ChangePassword(ctx, email, currentPassword, newPassword)
+	u.tracing.end(span, err)
+	return result, err
+}
+
+// TraceTaskRepository starts a span for every call to tasks.
+func TraceTaskRepository(tasks domain.TaskRepository, tracing *Tracing) domain.TaskRepository {
+	return &tracedTaskRepository{next: tasks, tracing: tracing}
+}
+
+type tracedTaskRepository struct {
+	next    domain.TaskRepository
+	tracing *Tracing
+}
+
+func (r *tracedTaskRepository) GetTasks(ctx context.Context) ([]*domain.Task, error) {
+	ctx, span := r.tracing.start(ctx, "TaskRepository.GetTasks")
+	result, err := r.next.GetTasks(ctx)
+	r.tracing.end(span, err)
+	return result, err
+}
+
+func (r *tracedTaskRepository) GetTask(ctx context.Context, taskID string) (domain.Task, error) {
+	ctx, span := r.tracing.start(ctx, "TaskRepository.GetTask")
+	result, err := r.next.GetTask(ctx, taskID)
+	r.tracing.end(span, err)
+	return result, err
+}
+
+func (r *tracedTaskRepository) PostTask(ctx context.Context, task *domain.Task) error {
+	ctx, span := r.tracing.start(ctx, "TaskRepository.PostTask")
+	err := r.next.PostTask(ctx, task)
+	r.tracing.end(span, err)
+	return err
+}
+
+func (r *tracedTaskRepository) DeleteTask(ctx context.Context, taskID string) error {
+	ctx, span := r.tracing.start(ctx, "TaskRepository.DeleteTask")
+	err := r.next.DeleteTask(ctx, taskID)
+	r.tracing.end(span, err)
+	return err
+}
+
+func (r *tracedTaskRepository) UpdateTask(ctx context.Context, taskID string, task *domain.Task) error {
+	ctx, span := r.tracing.start(ctx, "TaskRepository.UpdateTask")
+	err := r.next.UpdateTask(ctx, taskID, task)
+	r.tracing.end(span, err)
+	return err
+}
+
+func (r *tracedTaskRepository) GetRecurringTasks(ctx context.Context) ([]*domain.Task, error) {
+	ctx, span := r.tracing.start(ctx, "TaskRepository.GetRecurringTasks")
+	result, err := r.next.GetRecurringTasks(ctx)
+	r.tracing.end(span, err)
+	return result, err
+}
+
+func (r *tracedTaskRepository) GetSeriesTasks(ctx context.Context, seriesID string) ([]*domain.Task, error) {
+	ctx, span := r.tracing.start(ctx, "TaskRepository.GetSeriesTasks")
+	result, err := r.next.GetSeriesTasks(ctx, seriesID)
+	r.tracing.end(span, err)
+	return result, err
+}
+
+func (r *tracedTaskRepository) UpdateSeriesRecurrence(ctx context.Context, seriesID string, recurrence string) error {
+	ctx, span := r.tracing.start(ctx, "TaskRepository.UpdateSeriesRecurrence")
+	err := r.next.UpdateSeriesRecurrence(ctx, seriesID, recurrence)
+	r.tracing.end(span, err)
+	return err
+}
+
+func (r *tracedTaskRepository) GetTasksDueBefore(ctx context.Context, before time.Time, reminder string) ([]*domain.Task, error) {
+	ctx, span := r.tracing.start(ctx, "TaskRepository.GetTasksDueBefore")
+	result, err := r.next.GetTasksDueBefore(ctx, before, reminder)
+	r.tracing.end(span, err)
+	return result, err
+}
+
+func (r *tracedTaskRepository) MarkReminderSent(ctx context.Context, taskID string, reminder string) (bool, error) {
+	ctx, span := r.tracing.start(ctx, "TaskRepository.MarkReminderSent")
+	result, err := r.next.MarkReminderSent(ctx, taskID, reminder)
+	r.tracing.end(span, err)
+	return result, err
+}
+
+func (r *tracedTaskRepository) ClearReminderSent(ctx context.Context, taskID string, reminder string) error {
+	ctx, span := r.tracing.start(ctx, "TaskRepository.ClearReminderSent")
+	err := r.next.ClearReminderSent(ctx, taskID, reminder)
+	r.tracing.end(span, err)
+	return err
+}
+
+// TraceTaskSearcher starts a span for every search.
+func TraceTaskSearcher(searcher domain.TaskSearcher, tracing *Tracing) domain.TaskSearcher {
+	return &tracedTaskSearcher{next: searcher, tracing: tracing}
+}
+
+type tracedTaskSearcher struct {
+	next    domain.TaskSearcher
+	tracing *Tracing
+}
+
+func (s *tracedTaskSearcher) SearchTasks(ctx context.Context, query string, limit int) ([]*domain.TaskSearchResult, error) {
+	ctx, span := s.tracing.start(ctx, "TaskSearcher.SearchTasks")
+	result, err := s.next.SearchTasks(ctx, query, limit)
+	s.tracing.end(span, err)
+	return result, err
+}
+
+// TraceUserRepository starts a span for every call to users.
+func TraceUserRepository(users domain.UserRepository, tracing *Tracing) domain.UserRepository {
+	return &tracedUserRepository{next: users, tracing: tracing}
+}
+
+type tracedUserRepository struct {
+	next    domain.UserRepository
+	tracing *Tracing
+}
+
+func (r *tracedUserRepository) Register(ctx context.Context, user *domain.User) error {
+	ctx, span := r.tracing.start(ctx, "UserRepository.Register")
+	err := r.next.Register(ctx, user)
+	r.tracing.end(span, err)
+	return err
+}
+
+func (r *tracedUserRepository) VerifyFirst(ctx context.Context, user *domain.User) error {
+	ctx, span := r.tracing.start(ctx, "UserRepository.VerifyFirst")
+	err := r.next.VerifyFirst(ctx, user)
+	// finding a user is how VerifyFirst reports that user isn't the first,
+	// which is what happens on every registration but one
+	if err != nil && err.Error() == "a user is found on db" {
+		r.tracing.end(span, nil)
+		return err
+	}
+	r.tracing.end(span, err)
+	return err
+}
+
+func (r *tracedUserRepository) UserExists(ctx context.Context, user *domain.User) error {
+	ctx, span := r.tracing.start(ctx, "UserRepository.UserExists")
+	err := r.next.UserExists(ctx, user)
+	r.tracing.end(span, err)
+	return err
+}
+
+func (r *tracedUserRepository) GetUserByEmail(ctx context.Context, email string) domain.User {
+	ctx, span := r.tracing.start(ctx, "UserRepository.GetUserByEmail")
+	result := r.next.GetUserByEmail(ctx, email)
+	r.tracing.end(span, nil)
+	return result
+}
+
+func (r *tracedUserRepository) GetUserByID(ctx context.Context, userID string) (domain.User, error) {
+	ctx, span := r.tracing.start(ctx, "UserRepository.GetUserByID")
+	result, err := r.next.GetUserByID(ctx, userID)
+	r.tracing.end(span, err)
+	return result, err
+}
+
+func (r *tracedUserRepository) PromoteUser(ctx context.Context, userID string) error {
+	ctx, span := r.tracing.start(ctx, "UserRepository.PromoteUser")
+	err := r.next.PromoteUser(ctx, userID)
+	r.tracing.end(span, err)
+	return err
+}
+
+func (r *tracedUserRepository) MarkVerified(ctx context.Context, userID string) error {
+	ctx, span := r.tracing.start(ctx, "UserRepository.MarkVerified")
+	err := r.next.MarkVerified(ctx, userID)
+	r.tracing.end(span, err)
+	return err
+}
+
+func (r *tracedUserRepository) UpdateProfile(ctx context.Context, userID string, update domain.ProfileUpdate) error {
+	ctx, span := r.tracing.start(ctx, "UserRepository.UpdateProfile")
+	err := r.next.UpdateProfile(ctx, userID, update)
+	r.tracing.end(span, err)
+	return err
+}
+
+func (r *tracedUserRepository) UpdatePassword(ctx context.Context, userID string, hashedPassword string, sessionsRevokedAt time.Time) error {
+	ctx, span := r.tracing.start(ctx, "UserRepository.UpdatePassword")
+	err := r.next.UpdatePassword(ctx, userID, hashedPassword, sessionsRevokedAt)
+	r.tracing.end(span, err)
+	return err
+}
+
+func (r *tracedUserRepository) UpdatePasswordHash(ctx context.Context, userID string, hashedPassword string) error {
+	ctx, span := r.tracing.start(ctx, "UserRepository.UpdatePasswordHash")
+	err := r.next.UpdatePasswordHash(ctx, userID, hashedPassword)
+	r.tracing.end(span, err)
+	return err
+}
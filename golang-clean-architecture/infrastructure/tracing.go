@@ -0,0 +1,161 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// LoadTracerProvider exports spans to OTEL_TRACES_EXPORTER: "otlp" sends
+// them over OTLP/HTTP to the collector set by the standard
+// OTEL_EXPORTER_OTLP_* variables, "stdout" prints them for local runs and
+// "none", the default, drops them. The provider has to be shut down for the
+// spans it still buffers to be exported.
+func LoadTracerProvider(ctx context.Context) (*sdktrace.TracerProvider, error) {
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch value := GetEnv("OTEL_TRACES_EXPORTER", "none"); value {
+	case "otlp":
+		exporter, err = otlptracehttp.New(ctx)
+	case "stdout":
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case "none":
+	default:
+		slog.Warn("ignoring invalid environment variable", "key", "OTEL_TRACES_EXPORTER", "value", value, "using", "none")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	service, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", GetEnv("OTEL_SERVICE_NAME", "task-manager")),
+	))
+	if err != nil {
+		return nil, err
+	}
+	options := []sdktrace.TracerProviderOption{sdktrace.WithResource(service)}
+	if exporter != nil {
+		options = append(options, sdktrace.WithBatcher(exporter))
+	}
+	return sdktrace.NewTracerProvider(options...), nil
+}
+
+// Tracing starts the spans of the API on the provider it was created with,
+// so tests can record them in memory.
+type Tracing struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+func NewTracing(provider trace.TracerProvider) *Tracing {
+	return &Tracing{
+		tracer:     provider.Tracer("golang-clean-architecture"),
+		propagator: propagation.TraceContext{},
+	}
+}
+
+// MiddleWare starts a span for every request, continuing the trace of a
+// W3C traceparent header when the caller sent one. Spans are named after
+// the route template, like the metrics, and handlers pass the span on
+// through the request's context.
+func (t *Tracing) MiddleWare() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := t.propagator.Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		ctx, span := t.tracer.Start(ctx, c.Request.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", c.Request.Method),
+				attribute.String("http.route", route),
+				attribute.String("url.path", c.Request.URL.Path),
+				attribute.String("http.request.id", c.GetString("RequestID")),
+			),
+		)
+		defer span.End()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
+
+// CommandMonitor starts a span for every command the Mongo driver sends,
+// as a child of the span in the context the operation was called with.
+// Commands are not recorded since they hold password hashes and tokens.
+func (t *Tracing) CommandMonitor() *event.CommandMonitor {
+	var spans sync.Map
+	key := func(connectionID string, requestID int64) string {
+		return fmt.Sprintf("%s/%d", connectionID, requestID)
+	}
+	finish := func(connectionID string, requestID int64, failure string) {
+		value, ok := spans.LoadAndDelete(key(connectionID, requestID))
+		if !ok {
+			return
+		}
+		span := value.(trace.Span)
+		if failure != "" {
+			span.SetStatus(codes.Error, failure)
+		}
+		span.End()
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, started *event.CommandStartedEvent) {
+			name := started.CommandName
+			attributes := []attribute.KeyValue{
+				attribute.String("db.system", "mongodb"),
+				attribute.String("db.name", started.DatabaseName),
+				attribute.String("db.operation", started.CommandName),
+			}
+			// most commands name their collection in their first field
+			if element, err := started.Command.IndexErr(0); err == nil {
+				if collection, ok := element.Value().StringValueOK(); ok {
+					name += " " + collection
+					attributes = append(attributes, attribute.String("db.mongodb.collection", collection))
+				}
+			}
+			_, span := t.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attributes...))
+			spans.Store(key(started.ConnectionID, started.RequestID), span)
+		},
+		Succeeded: func(ctx context.Context, succeeded *event.CommandSucceededEvent) {
+			finish(succeeded.ConnectionID, succeeded.RequestID, "")
+		},
+		Failed: func(ctx context.Context, failed *event.CommandFailedEvent) {
+			finish(failed.ConnectionID, failed.RequestID, failed.Failure)
+		},
+	}
+}
+
+// start begins a span for a use case or repository method.
+func (t *Tracing) start(ctx context.Context, name string) (context.Context, trace.Span) {
+	return t.tracer.Start(ctx, name)
+}
+
+// end marks span as failed when err is set and ends it.
+func (t *Tracing) end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
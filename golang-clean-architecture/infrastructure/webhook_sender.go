@@ -0,0 +1,60 @@
+package infrastructure
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"golang-clean-architecture/domain"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// HTTPWebhookSender POSTs webhook payloads signed with the subscription's
+// secret. Receivers verify X-Webhook-Signature against
+// SignWebhookPayload(secret, X-Webhook-Timestamp, body).
+type HTTPWebhookSender struct {
+	Client *http.Client
+}
+
+func NewHTTPWebhookSender() *HTTPWebhookSender {
+	return &HTTPWebhookSender{
+		Client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (hs *HTTPWebhookSender) Send(subscription *domain.WebhookSubscription, delivery *domain.WebhookDelivery) (int, error) {
+	payload := []byte(delivery.Payload)
+	timestamp := time.Now().Unix()
+
+	request, err := http.NewRequest(http.MethodPost, subscription.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, errors.New("invalid webhook url")
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "golang-clean-architecture-webhooks")
+	request.Header.Set("X-Webhook-ID", delivery.ID.Hex())
+	request.Header.Set("X-Webhook-Event", delivery.EventType)
+	request.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+	request.Header.Set("X-Webhook-Signature", SignWebhookPayload(subscription.Secret, timestamp, payload))
+
+	response, err := hs.Client.Do(request)
+	if err != nil {
+		return 0, errors.New("error while sending webhook")
+	}
+	defer response.Body.Close()
+	return response.StatusCode, nil
+}
+
+// SignWebhookPayload returns "sha256=" followed by the hex HMAC-SHA256 of
+// "<timestamp>.<payload>". Including the timestamp lets receivers reject
+// replayed deliveries.
+func SignWebhookPayload(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,68 @@
+package infrastructure_test
+
+import (
+	"errors"
+	"golang-clean-architecture/domain"
+	"golang-clean-architecture/domain/mocks"
+	"golang-clean-architecture/infrastructure"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type APITokenMiddlewareTestSuite struct {
+	suite.Suite
+	mockTokens *mocks.APITokenUseCase
+	tokens     domain.TokenService
+	router     *gin.Engine
+}
+
+func (suite *APITokenMiddlewareTestSuite) SetupTest() {
+	suite.mockTokens = new(mocks.APITokenUseCase)
+	suite.mockTokens.On("Authenticate", mock.Anything, "tm_reader").Return(domain.AuthenticatedUser{Email: "kidusm3l@gmail.com", Role: "user", APITokenID: "1", Scopes: []string{"tasks:read"}}, nil)
+	suite.mockTokens.On("Authenticate", mock.Anything, "tm_revoked").Return(domain.AuthenticatedUser{}, errors.New("invalid api token"))
+
+	suite.tokens = newTestTokenService(suite.T())
+	handler := func(c *gin.Context) { c.Status(http.StatusOK) }
+	suite.router = gin.New()
+	suite.router.Use(infrastructure.APITokenAuth(suite.mockTokens), infrastructure.AuthMiddleWare(suite.tokens))
+	suite.router.GET("/tasks", infrastructure.RequireScope("tasks"), handler)
+	suite.router.POST("/tasks", infrastructure.RequireScope("tasks"), handler)
+	suite.router.GET("/me/tokens", infrastructure.RequireSession(), handler)
+}
+
+func (suite *APITokenMiddlewareTestSuite) request(method string, path string, token string) int {
+	req, _ := http.NewRequest(method, path, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	recorder := httptest.NewRecorder()
+	suite.router.ServeHTTP(recorder, req)
+	return recorder.Code
+}
+
+func (suite *APITokenMiddlewareTestSuite) TestScopesAreEnforced() {
+	suite.Equal(http.StatusOK, suite.request(http.MethodGet, "/tasks", "tm_reader"))
+	suite.Equal(http.StatusForbidden, suite.request(http.MethodPost, "/tasks", "tm_reader"))
+	suite.Equal(http.StatusForbidden, suite.request(http.MethodGet, "/me/tokens", "tm_reader"))
+}
+
+func (suite *APITokenMiddlewareTestSuite) TestInvalidTokenIsRejected() {
+	suite.Equal(http.StatusUnauthorized, suite.request(http.MethodGet, "/tasks", "tm_revoked"))
+}
+
+func (suite *APITokenMiddlewareTestSuite) TestJWTsKeepEveryScope() {
+	issued, err := suite.tokens.IssueToken(&domain.User{Email: "kidusm3l@gmail.com", Role: "user"})
+	suite.Require().NoError(err)
+	token := issued.Token
+
+	suite.Equal(http.StatusOK, suite.request(http.MethodPost, "/tasks", token))
+	suite.Equal(http.StatusOK, suite.request(http.MethodGet, "/me/tokens", token))
+	suite.mockTokens.AssertNotCalled(suite.T(), "Authenticate", mock.Anything, token)
+}
+
+func TestAPITokenMiddlewareTestSuite(t *testing.T) {
+	suite.Run(t, new(APITokenMiddlewareTestSuite))
+}
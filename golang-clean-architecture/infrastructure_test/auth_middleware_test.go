@@ -0,0 +1,64 @@
+package infrastructure_test
+
+import (
+	"golang-clean-architecture/domain"
+	"golang-clean-architecture/domain/mocks"
+	"golang-clean-architecture/infrastructure"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type SessionMiddlewareTestSuite struct {
+	suite.Suite
+	mockUsers *mocks.UserRepository
+	router    *gin.Engine
+	token     string
+}
+
+func (suite *SessionMiddlewareTestSuite) SetupTest() {
+	suite.mockUsers = new(mocks.UserRepository)
+	tokens := newTestTokenService(suite.T())
+	suite.router = gin.New()
+	suite.router.GET("/tasks", infrastructure.AuthMiddleWare(tokens), infrastructure.SessionMiddleWare(suite.mockUsers), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	token, err := tokens.IssueToken(&domain.User{Email: "kidusm3l@gmail.com", Role: "user"})
+	suite.Require().NoError(err)
+	suite.token = token.Token
+}
+
+func (suite *SessionMiddlewareTestSuite) request() int {
+	req, _ := http.NewRequest(http.MethodGet, "/tasks", nil)
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	return w.Code
+}
+
+func (suite *SessionMiddlewareTestSuite) TestTokenIssuedAfterRevocationIsAccepted() {
+	suite.mockUsers.On("GetUserByEmail", mock.Anything, "kidusm3l@gmail.com").
+		Return(domain.User{Email: "kidusm3l@gmail.com", SessionsRevokedAt: time.Now().Add(-time.Minute)})
+	suite.Equal(http.StatusOK, suite.request())
+}
+
+func (suite *SessionMiddlewareTestSuite) TestTokenIssuedBeforeRevocationIsRejected() {
+	suite.mockUsers.On("GetUserByEmail", mock.Anything, "kidusm3l@gmail.com").
+		Return(domain.User{Email: "kidusm3l@gmail.com", SessionsRevokedAt: time.Now().Add(time.Minute)})
+	suite.Equal(http.StatusUnauthorized, suite.request())
+}
+
+func (suite *SessionMiddlewareTestSuite) TestTokenOfDeletedUserIsRejected() {
+	suite.mockUsers.On("GetUserByEmail", mock.Anything, "kidusm3l@gmail.com").Return(domain.User{})
+	suite.Equal(http.StatusUnauthorized, suite.request())
+}
+
+func TestSessionMiddlewareTestSuite(t *testing.T) {
+	suite.Run(t, new(SessionMiddlewareTestSuite))
+}
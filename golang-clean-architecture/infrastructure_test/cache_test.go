@@ -0,0 +1,185 @@
+package infrastructure_test
+
+import (
+	"context"
+	"errors"
+	"golang-clean-architecture/domain"
+	"golang-clean-architecture/domain/mocks"
+	"golang-clean-architecture/infrastructure"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CacheTestSuite struct {
+	suite.Suite
+	now   time.Time
+	cache *infrastructure.MemoryCache
+}
+
+func (suite *CacheTestSuite) SetupTest() {
+	suite.now = time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)
+	suite.cache = infrastructure.NewMemoryCache(2)
+	suite.cache.Now = func() time.Time { return suite.now }
+}
+
+func (suite *CacheTestSuite) TestMemoryCacheEvictsTheLeastRecentlyUsed() {
+	ctx := context.Background()
+	suite.NoError(suite.cache.Set(ctx, "a", []byte("1"), time.Minute))
+	suite.NoError(suite.cache.Set(ctx, "b", []byte("2"), time.Minute))
+	_, ok, _ := suite.cache.Get(ctx, "a")
+	suite.True(ok)
+	suite.NoError(suite.cache.Set(ctx, "c", []byte("3"), time.Minute))
+
+	_, ok, _ = suite.cache.Get(ctx, "b")
+	suite.False(ok, "b was used least recently")
+	value, ok, err := suite.cache.Get(ctx, "a")
+	suite.NoError(err)
+	suite.True(ok)
+	suite.Equal([]byte("1"), value)
+	_, ok, _ = suite.cache.Get(ctx, "c")
+	suite.True(ok)
+}
+
+func (suite *CacheTestSuite) TestMemoryCacheEntriesExpire() {
+	ctx := context.Background()
+	suite.NoError(suite.cache.Set(ctx, "a", []byte("1"), time.Minute))
+
+	suite.now = suite.now.Add(59 * time.Second)
+	_, ok, _ := suite.cache.Get(ctx, "a")
+	suite.True(ok)
+	suite.now = suite.now.Add(time.Second)
+	_, ok, _ = suite.cache.Get(ctx, "a")
+	suite.False(ok)
+}
+
+func (suite *CacheTestSuite) TestRedisCache() {
+	server := miniredis.RunT(suite.T())
+	cache := infrastructure.NewRedisCache(redis.NewClient(&redis.Options{Addr: server.Addr()}), "test:")
+	ctx := context.Background()
+
+	_, ok, err := cache.Get(ctx, "a")
+	suite.NoError(err)
+	suite.False(ok, "a missing key isn't an error")
+
+	suite.NoError(cache.Set(ctx, "a", []byte("1"), time.Minute))
+	suite.NoError(cache.Set(ctx, "b", []byte("2"), time.Minute))
+	suite.True(server.Exists("test:a"), "keys are prefixed")
+	value, ok, err := cache.Get(ctx, "a")
+	suite.NoError(err)
+	suite.True(ok)
+	suite.Equal([]byte("1"), value)
+
+	suite.NoError(cache.Delete(ctx, "a", "b"))
+	_, ok, _ = cache.Get(ctx, "b")
+	suite.False(ok)
+
+	suite.NoError(cache.Set(ctx, "c", []byte("3"), time.Minute))
+	server.FastForward(time.Minute)
+	_, ok, _ = cache.Get(ctx, "c")
+	suite.False(ok)
+}
+
+func (suite *CacheTestSuite) TestCachedRepositoryServesRepeatedReads() {
+	metrics := infrastructure.NewMetrics()
+	task := domain.Task{ID: primitive.NewObjectID(), Title: "Write report"}
+	tasks := new(mocks.TaskRepository)
+	tasks.On("GetTask", mock.Anything, task.ID.Hex()).Return(task, nil).Once()
+	tasks.On("GetTasks", mock.Anything).Return([]*domain.Task{&task}, nil).Once()
+	tasks.On("GetTask", mock.Anything, "missing").Return(domain.Task{}, errors.New("task not found"))
+	cached := infrastructure.CacheTaskRepository(tasks, suite.cache, time.Minute, metrics)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		found, err := cached.GetTask(ctx, task.ID.Hex())
+		suite.NoError(err)
+		suite.Equal("Write report", found.Title)
+		all, err := cached.GetTasks(ctx)
+		suite.NoError(err)
+		suite.Len(all, 1)
+	}
+	_, err := cached.GetTask(ctx, "missing")
+	suite.EqualError(err, "task not found", "errors aren't cached")
+	tasks.AssertExpectations(suite.T())
+
+	recorder := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	suite.Contains(recorder.Body.String(), `cache_requests_total{cache="tasks",result="hit"} 2`)
+	suite.Contains(recorder.Body.String(), `cache_requests_total{cache="tasks",result="miss"} 3`)
+}
+
+func (suite *CacheTestSuite) TestCachedRepositoryDropsWrittenTasks() {
+	suite.cache.Capacity = 10
+	task := domain.Task{ID: primitive.NewObjectID(), Title: "Write report", SeriesID: "series"}
+	taskID := task.ID.Hex()
+	tasks := new(mocks.TaskRepository)
+	tasks.On("GetTask", mock.Anything, taskID).Return(task, nil)
+	tasks.On("GetTasks", mock.Anything).Return([]*domain.Task{&task}, nil)
+	tasks.On("PostTask", mock.Anything, mock.Anything).Return(nil)
+	tasks.On("UpdateTask", mock.Anything, taskID, mock.Anything).Return(errors.New("task not found"))
+	tasks.On("DeleteTask", mock.Anything, taskID).Return(nil)
+	tasks.On("UpdateSeriesRecurrence", mock.Anything, "series", "FREQ=DAILY").Return(nil)
+	tasks.On("GetSeriesTasks", mock.Anything, "series").Return([]*domain.Task{&task}, nil)
+	cached := infrastructure.CacheTaskRepository(tasks, suite.cache, time.Minute, infrastructure.NewMetrics())
+	ctx := context.Background()
+
+	writes := map[string]func() error{
+		"PostTask":               func() error { return cached.PostTask(ctx, &domain.Task{}) },
+		"UpdateTask":             func() error { return cached.UpdateTask(ctx, taskID, &task) },
+		"DeleteTask":             func() error { return cached.DeleteTask(ctx, taskID) },
+		"UpdateSeriesRecurrence": func() error { return cached.UpdateSeriesRecurrence(ctx, "series", "FREQ=DAILY") },
+	}
+	for name, write := range writes {
+		_, err := cached.GetTask(ctx, taskID)
+		suite.NoError(err)
+		_, err = cached.GetTasks(ctx)
+		suite.NoError(err)
+		write()
+
+		_, ok, _ := suite.cache.Get(ctx, "tasks")
+		suite.False(ok, name+" drops the list")
+		if name != "PostTask" {
+			_, ok, _ = suite.cache.Get(ctx, "task:"+taskID)
+			suite.False(ok, name+" drops the task")
+		}
+	}
+}
+
+func (suite *CacheTestSuite) TestCachedRepositoryKeepsTasksWhenRemindersAreRecorded() {
+	task := domain.Task{ID: primitive.NewObjectID(), Title: "Write report"}
+	taskID := task.ID.Hex()
+	tasks := new(mocks.TaskRepository)
+	tasks.On("GetTask", mock.Anything, taskID).Return(task, nil).Once()
+	tasks.On("MarkReminderSent", mock.Anything, taskID, "overdue").Return(true, nil)
+	tasks.On("ClearReminderSent", mock.Anything, taskID, "overdue").Return(nil)
+	cached := infrastructure.CacheTaskRepository(tasks, suite.cache, time.Minute, infrastructure.NewMetrics())
+	ctx := context.Background()
+
+	_, err := cached.GetTask(ctx, taskID)
+	suite.NoError(err)
+	claimed, err := cached.MarkReminderSent(ctx, taskID, "overdue")
+	suite.NoError(err)
+	suite.True(claimed)
+	suite.NoError(cached.ClearReminderSent(ctx, taskID, "overdue"))
+
+	found, err := cached.GetTask(ctx, taskID)
+	suite.NoError(err)
+	suite.Equal("Write report", found.Title)
+	tasks.AssertExpectations(suite.T())
+}
+
+func (suite *CacheTestSuite) TestNoCacheLeavesTheRepository() {
+	tasks := new(mocks.TaskRepository)
+	suite.Equal(domain.TaskRepository(tasks), infrastructure.CacheTaskRepository(tasks, nil, time.Minute, infrastructure.NewMetrics()))
+}
+
+func TestCacheTestSuite(t *testing.T) {
+	suite.Run(t, new(CacheTestSuite))
+}
@@ -0,0 +1,87 @@
+package infrastructure_test
+
+import (
+	"context"
+	"fmt"
+	"golang-clean-architecture/domain"
+	"golang-clean-architecture/infrastructure"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type EventBusTestSuite struct {
+	suite.Suite
+}
+
+func event(id string) domain.Event {
+	return domain.Event{ID: id, Type: "task.created"}
+}
+
+func (suite *EventBusTestSuite) TestSubscribersAndSinksReceiveEvents() {
+	sink := &recordingPublisher{}
+	bus := infrastructure.NewEventBus(10, sink)
+	subscription := bus.Subscribe("")
+	defer subscription.Cancel()
+
+	bus.Publish(context.Background(), event("1"))
+
+	suite.Equal("1", (<-subscription.Events).ID)
+	suite.Equal([]domain.Event{event("1")}, sink.events)
+}
+
+func (suite *EventBusTestSuite) TestResumeReplaysEventsAfterLastEventID() {
+	bus := infrastructure.NewEventBus(10)
+	for i := 1; i <= 4; i++ {
+		bus.Publish(context.Background(), event(fmt.Sprint(i)))
+	}
+
+	subscription := bus.Subscribe("2")
+	defer subscription.Cancel()
+	suite.False(subscription.Missed)
+	suite.Equal([]domain.Event{event("3"), event("4")}, subscription.Replay)
+}
+
+func (suite *EventBusTestSuite) TestResumeFromEvictedEventIsMissed() {
+	bus := infrastructure.NewEventBus(3)
+	for i := 1; i <= 5; i++ {
+		bus.Publish(context.Background(), event(fmt.Sprint(i)))
+	}
+
+	subscription := bus.Subscribe("1")
+	defer subscription.Cancel()
+	suite.True(subscription.Missed)
+	suite.Empty(subscription.Replay)
+
+	resumed := bus.Subscribe("3")
+	defer resumed.Cancel()
+	suite.Equal([]domain.Event{event("4"), event("5")}, resumed.Replay)
+}
+
+func (suite *EventBusTestSuite) TestSlowSubscriberIsDropped() {
+	bus := infrastructure.NewEventBus(10)
+	subscription := bus.Subscribe("")
+	for i := 0; i < 100; i++ {
+		bus.Publish(context.Background(), event(fmt.Sprint(i)))
+	}
+
+	received := 0
+	for range subscription.Events {
+		received++
+	}
+	suite.Less(received, 100)
+	// cancelling an already dropped subscription is harmless
+	subscription.Cancel()
+}
+
+type recordingPublisher struct {
+	events []domain.Event
+}
+
+func (rp *recordingPublisher) Publish(ctx context.Context, event domain.Event) {
+	rp.events = append(rp.events, event)
+}
+
+func TestEventBusTestSuite(t *testing.T) {
+	suite.Run(t, new(EventBusTestSuite))
+}
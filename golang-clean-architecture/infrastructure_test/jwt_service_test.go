@@ -0,0 +1,190 @@
+package infrastructure_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"golang-clean-architecture/domain"
+	"golang-clean-architecture/infrastructure"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/suite"
+)
+
+type JWTServiceTestSuite struct {
+	suite.Suite
+	rsaKey  *rsa.PrivateKey
+	ring    *infrastructure.KeyRing
+	config  infrastructure.JWTConfig
+	service domain.TokenService
+	user    *domain.User
+}
+
+// newTestTokenService signs tokens with a key that only lives as long as
+// the test.
+func newTestTokenService(t *testing.T) domain.TokenService {
+	keys, err := infrastructure.NewEphemeralKeyRing()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return infrastructure.NewJWTService(infrastructure.JWTConfig{Keys: keys, Issuer: "task-manager", Audience: "task-manager-api", TTL: time.Hour})
+}
+
+func (suite *JWTServiceTestSuite) SetupSuite() {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	suite.Require().NoError(err)
+	suite.rsaKey = key
+}
+
+func (suite *JWTServiceTestSuite) SetupTest() {
+	suite.ring = infrastructure.NewKeyRing()
+	suite.ring.Add(&infrastructure.SigningKey{ID: "2024-01", Method: jwt.SigningMethodRS256, Private: suite.rsaKey, Public: &suite.rsaKey.PublicKey})
+	suite.Require().NoError(suite.ring.SetActive("2024-01"))
+	suite.config = infrastructure.JWTConfig{Keys: suite.ring, Issuer: "task-manager", Audience: "task-manager-api", TTL: time.Hour}
+	suite.service = infrastructure.NewJWTService(suite.config)
+	suite.user = &domain.User{Email: "kidusm3l@gmail.com", Role: "user"}
+}
+
+func (suite *JWTServiceTestSuite) sign(method jwt.SigningMethod, kid string, key interface{}, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	suite.Require().NoError(err)
+	return signed
+}
+
+func (suite *JWTServiceTestSuite) TestRotationKeepsOutstandingTokensValid() {
+	oldToken, err := suite.service.IssueToken(suite.user)
+	suite.NoError(err)
+
+	newKey, err := infrastructure.GenerateEd25519Key("2024-06")
+	suite.NoError(err)
+	suite.ring.Add(newKey)
+	suite.NoError(suite.ring.SetActive("2024-06"))
+
+	newToken, err := suite.service.IssueToken(suite.user)
+	suite.NoError(err)
+	parsed, _, err := jwt.NewParser().ParseUnverified(newToken.Token, jwt.MapClaims{})
+	suite.NoError(err)
+	suite.Equal("2024-06", parsed.Header["kid"])
+	suite.Equal("EdDSA", parsed.Header["alg"])
+
+	claims, err := suite.service.ParseToken(oldToken.Token)
+	suite.NoError(err, "tokens signed with the previous key are still accepted")
+	suite.True(oldToken.ExpiresAt.Equal(claims.ExpiresAt), "the expiry is reported as it was signed")
+	suite.Equal("kidusm3l@gmail.com", claims.Email)
+	suite.Equal("user", claims.Role)
+	suite.False(claims.TwoFactor)
+	_, err = suite.service.ParseToken(newToken.Token)
+	suite.NoError(err)
+
+	suite.NoError(suite.ring.Remove("2024-01"))
+	_, err = suite.service.ParseToken(oldToken.Token)
+	suite.Error(err, "removing a key retires its tokens")
+	suite.Error(suite.ring.Remove("2024-06"), "the active key can't be removed")
+}
+
+func (suite *JWTServiceTestSuite) TestRegisteredClaimsAreValidated() {
+	now := time.Now()
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"email": "kidusm3l@gmail.com",
+			"role":  "user",
+			"iss":   "task-manager",
+			"aud":   "task-manager-api",
+			"iat":   now.Unix(),
+			"nbf":   now.Unix(),
+			"exp":   now.Add(time.Hour).Unix(),
+		}
+	}
+	_, err := suite.service.ParseToken(suite.sign(jwt.SigningMethodRS256, "2024-01", suite.rsaKey, valid()))
+	suite.NoError(err)
+
+	for name, change := range map[string]func(jwt.MapClaims){
+		"wrong issuer":   func(claims jwt.MapClaims) { claims["iss"] = "someone-else" },
+		"missing issuer": func(claims jwt.MapClaims) { delete(claims, "iss") },
+		"wrong audience": func(claims jwt.MapClaims) { claims["aud"] = "another-api" },
+		"not yet valid":  func(claims jwt.MapClaims) { claims["nbf"] = now.Add(time.Hour).Unix() },
+		"missing nbf":    func(claims jwt.MapClaims) { delete(claims, "nbf") },
+		"missing iat":    func(claims jwt.MapClaims) { delete(claims, "iat") },
+		"expired":        func(claims jwt.MapClaims) { claims["exp"] = now.Add(-time.Minute).Unix() },
+	} {
+		claims := valid()
+		change(claims)
+		_, err := suite.service.ParseToken(suite.sign(jwt.SigningMethodRS256, "2024-01", suite.rsaKey, claims))
+		suite.Error(err, name)
+	}
+}
+
+func (suite *JWTServiceTestSuite) TestAlgorithmIsPinnedByKey() {
+	claims := jwt.MapClaims{"email": "kidusm3l@gmail.com", "role": "admin", "iss": "task-manager", "aud": "task-manager-api", "nbf": time.Now().Unix()}
+	publicDER, err := x509.MarshalPKIXPublicKey(&suite.rsaKey.PublicKey)
+	suite.NoError(err)
+
+	// an HMAC token keyed with the published RSA key must not pass
+	forged := suite.sign(jwt.SigningMethodHS256, "2024-01", publicDER, claims)
+	_, err = suite.service.ParseToken(forged)
+	suite.Error(err)
+
+	_, err = suite.service.ParseToken(suite.sign(jwt.SigningMethodRS256, "unknown", suite.rsaKey, claims))
+	suite.Error(err)
+}
+
+func (suite *JWTServiceTestSuite) TestJWKSEndpoint() {
+	edKey, err := infrastructure.GenerateEd25519Key("2024-06")
+	suite.NoError(err)
+	suite.ring.Add(edKey)
+
+	router := gin.New()
+	router.GET("/.well-known/jwks.json", infrastructure.JWKSHandler(suite.ring))
+	req, _ := http.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	suite.Equal(http.StatusOK, recorder.Code)
+	var set infrastructure.JSONWebKeySet
+	suite.NoError(json.Unmarshal(recorder.Body.Bytes(), &set))
+	suite.Require().Len(set.Keys, 2)
+	suite.Equal("2024-01", set.Keys[0].KeyID)
+	suite.Equal("RSA", set.Keys[0].KeyType)
+	suite.Equal("RS256", set.Keys[0].Algorithm)
+	suite.Equal("AQAB", set.Keys[0].Exponent)
+	suite.Equal("2024-06", set.Keys[1].KeyID)
+	suite.Equal("OKP", set.Keys[1].KeyType)
+	suite.Equal("Ed25519", set.Keys[1].Curve)
+	suite.NotContains(recorder.Body.String(), `"d"`, "private key material isn't published")
+}
+
+func (suite *JWTServiceTestSuite) TestLoadKeyRing() {
+	dir := suite.T().TempDir()
+	private := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(suite.rsaKey)})
+	suite.NoError(os.WriteFile(filepath.Join(dir, "2024-06.pem"), private, 0600))
+
+	retired, err := x509.MarshalPKIXPublicKey(&suite.rsaKey.PublicKey)
+	suite.NoError(err)
+	suite.NoError(os.WriteFile(filepath.Join(dir, "2024-01.pem"), pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: retired}), 0600))
+
+	ring, err := infrastructure.LoadKeyRing(dir, "")
+	suite.NoError(err)
+	active, err := ring.Active()
+	suite.NoError(err)
+	suite.Equal("2024-06", active.ID, "the only private key becomes active")
+	_, ok := ring.Key("2024-01")
+	suite.True(ok, "public keys are kept for verification")
+
+	_, err = infrastructure.LoadKeyRing(dir, "2024-01")
+	suite.Error(err, "a public key can't sign")
+}
+
+func TestJWTServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(JWTServiceTestSuite))
+}
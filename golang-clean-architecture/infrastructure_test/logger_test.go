@@ -0,0 +1,122 @@
+package infrastructure_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"golang-clean-architecture/domain"
+	"golang-clean-architecture/infrastructure"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+)
+
+type RequestIDTestSuite struct {
+	suite.Suite
+	output *bytes.Buffer
+	router *gin.Engine
+}
+
+func (suite *RequestIDTestSuite) SetupTest() {
+	suite.output = &bytes.Buffer{}
+	logger := infrastructure.NewLogger(suite.output, "json", slog.LevelInfo)
+	suite.router = gin.New()
+	suite.router.Use(infrastructure.RequestIDMiddleWare(logger))
+	suite.router.GET("/tasks", func(c *gin.Context) {
+		infrastructure.LoggerFrom(c).Info("listing tasks")
+		c.Status(http.StatusOK)
+	})
+	suite.router.POST("/tasks", func(c *gin.Context) {
+		// use cases get the request context rather than the gin one
+		logger.InfoContext(c.Request.Context(), "creating task")
+		infrastructure.LoggerFrom(c).InfoContext(c.Request.Context(), "created task")
+		c.Status(http.StatusCreated)
+	})
+}
+
+func (suite *RequestIDTestSuite) request(requestID string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodGet, "/tasks?access_token=tm_secret", nil)
+	if requestID != "" {
+		req.Header.Set("X-Request-ID", requestID)
+	}
+	recorder := httptest.NewRecorder()
+	suite.router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func (suite *RequestIDTestSuite) logLines() []map[string]interface{} {
+	lines := []map[string]interface{}{}
+	for _, line := range strings.Split(strings.TrimSpace(suite.output.String()), "\n") {
+		var record map[string]interface{}
+		suite.Require().NoError(json.Unmarshal([]byte(line), &record))
+		lines = append(lines, record)
+	}
+	return lines
+}
+
+func (suite *RequestIDTestSuite) TestEveryLineCarriesTheRequestID() {
+	recorder := suite.request("")
+	requestID := recorder.Header().Get("X-Request-ID")
+	suite.Len(requestID, 32)
+
+	lines := suite.logLines()
+	suite.Require().Len(lines, 2)
+	suite.Equal("listing tasks", lines[0]["msg"])
+	suite.Equal("request", lines[1]["msg"])
+	for _, line := range lines {
+		suite.Equal(requestID, line["request_id"])
+	}
+	suite.Equal("/tasks", lines[1]["path"])
+	suite.EqualValues(http.StatusOK, lines[1]["status"])
+	suite.NotContains(suite.output.String(), "tm_secret", "query strings can hold tokens")
+}
+
+func (suite *RequestIDTestSuite) TestIncomingRequestIDIsPropagated() {
+	recorder := suite.request("edge-7f3a.42")
+	suite.Equal("edge-7f3a.42", recorder.Header().Get("X-Request-ID"))
+	suite.Equal("edge-7f3a.42", suite.logLines()[0]["request_id"])
+}
+
+func (suite *RequestIDTestSuite) TestMalformedRequestIDIsReplaced() {
+	recorder := suite.request(`"injected" level=ERROR`)
+	requestID := recorder.Header().Get("X-Request-ID")
+	suite.Len(requestID, 32)
+	suite.NotContains(suite.output.String(), "injected")
+}
+
+func (suite *RequestIDTestSuite) TestLinesLoggedWithTheRequestContextCarryTheRequestID() {
+	req, _ := http.NewRequest(http.MethodPost, "/tasks", nil)
+	req.Header.Set("X-Request-ID", "edge-7f3a.42")
+	suite.router.ServeHTTP(httptest.NewRecorder(), req)
+
+	lines := suite.logLines()
+	suite.Require().Len(lines, 3)
+	for _, line := range lines {
+		suite.Equal("edge-7f3a.42", line["request_id"])
+	}
+	suite.Equal(1, strings.Count(strings.Split(suite.output.String(), "\n")[1], "request_id"), "the id is only written once")
+}
+
+func (suite *RequestIDTestSuite) TestLogMailerCarriesTheRequestID() {
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(infrastructure.NewLogger(suite.output, "json", slog.LevelInfo))
+
+	ctx := infrastructure.WithRequestID(context.Background(), "edge-7f3a.42")
+	suite.Require().NoError(infrastructure.LogMailer{}.Send(ctx, domain.Mail{To: "kidusm3l@gmail.com", Subject: "Verify your email address"}))
+	suite.Require().NoError(infrastructure.LogNotifier{}.Notify(ctx, domain.Notification{Type: "due_soon", Subject: "Task due soon"}))
+
+	lines := suite.logLines()
+	suite.Require().Len(lines, 2)
+	for _, line := range lines {
+		suite.Equal("edge-7f3a.42", line["request_id"])
+	}
+}
+
+func TestRequestIDTestSuite(t *testing.T) {
+	suite.Run(t, new(RequestIDTestSuite))
+}
@@ -0,0 +1,101 @@
+package infrastructure_test
+
+import (
+	"context"
+	"errors"
+	"golang-clean-architecture/domain"
+	"golang-clean-architecture/domain/mocks"
+	"golang-clean-architecture/infrastructure"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MetricsTestSuite struct {
+	suite.Suite
+	metrics *infrastructure.Metrics
+	router  *gin.Engine
+}
+
+func (suite *MetricsTestSuite) SetupTest() {
+	suite.metrics = infrastructure.NewMetrics()
+	suite.router = gin.New()
+	suite.router.Use(suite.metrics.MiddleWare())
+	suite.router.GET("/tasks/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+	suite.router.GET("/metrics", gin.WrapH(suite.metrics.Handler()))
+}
+
+func (suite *MetricsTestSuite) get(path string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodGet, path, nil)
+	recorder := httptest.NewRecorder()
+	suite.router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func (suite *MetricsTestSuite) scrape() string {
+	recorder := suite.get("/metrics")
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	return recorder.Body.String()
+}
+
+func (suite *MetricsTestSuite) TestRequestsAreLabelledByRouteTemplate() {
+	suite.get("/tasks/1")
+	suite.get("/tasks/2")
+	suite.get("/nowhere")
+
+	body := suite.scrape()
+	suite.Contains(body, `http_requests_total{method="GET",route="/tasks/:id",status="200"} 2`)
+	suite.Contains(body, `http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	suite.Contains(body, `http_request_duration_seconds_count{method="GET",route="/tasks/:id",status="200"} 2`)
+	suite.NotContains(body, `route="/tasks/1"`)
+	suite.Contains(body, "go_goroutines")
+}
+
+func (suite *MetricsTestSuite) TestRepositoryCallsAreTimed() {
+	tasks := new(mocks.TaskRepository)
+	tasks.On("GetTask", mock.Anything, "1").Return(domain.Task{}, nil)
+	tasks.On("GetTask", mock.Anything, "2").Return(domain.Task{}, errors.New("task not found"))
+	users := new(mocks.UserRepository)
+	users.On("GetUserByEmail", mock.Anything, "kidusm3l@gmail.com").Return(domain.User{Email: "kidusm3l@gmail.com"})
+
+	instrumentedTasks := infrastructure.InstrumentTaskRepository(tasks, suite.metrics)
+	_, err := instrumentedTasks.GetTask(context.Background(), "1")
+	suite.NoError(err)
+	_, err = instrumentedTasks.GetTask(context.Background(), "2")
+	suite.EqualError(err, "task not found", "errors are passed through")
+	user := infrastructure.InstrumentUserRepository(users, suite.metrics).GetUserByEmail(context.Background(), "kidusm3l@gmail.com")
+	suite.Equal("kidusm3l@gmail.com", user.Email)
+
+	body := suite.scrape()
+	suite.Contains(body, `repository_operation_duration_seconds_count{method="GetTask",outcome="success",repository="tasks"} 1`)
+	suite.Contains(body, `repository_operation_duration_seconds_count{method="GetTask",outcome="error",repository="tasks"} 1`)
+	suite.Contains(body, `repository_operation_duration_seconds_count{method="GetUserByEmail",outcome="success",repository="users"} 1`)
+}
+
+func (suite *MetricsTestSuite) TestLoginsAreCountedByResult() {
+	guard := new(mocks.LoginGuard)
+	guard.On("Check", mock.Anything, "locked@example.com", mock.Anything, mock.Anything).Return(&domain.RetryError{Message: "too many failed login attempts, try again later"})
+	guard.On("RecordFailure", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	guard.On("RecordSuccess", mock.Anything, mock.Anything).Return(nil)
+
+	instrumented := infrastructure.InstrumentLoginGuard(guard, suite.metrics)
+	now := time.Now()
+	suite.Error(instrumented.Check(context.Background(), "locked@example.com", "203.0.113.7", now))
+	suite.NoError(instrumented.RecordFailure(context.Background(), "kidusm3l@gmail.com", "203.0.113.7", now))
+	suite.NoError(instrumented.RecordFailure(context.Background(), "kidusm3l@gmail.com", "203.0.113.7", now))
+	suite.NoError(instrumented.RecordSuccess(context.Background(), "kidusm3l@gmail.com"))
+
+	body := suite.scrape()
+	suite.Contains(body, `logins_total{result="blocked"} 1`)
+	suite.Contains(body, `logins_total{result="failure"} 2`)
+	suite.Contains(body, `logins_total{result="success"} 1`)
+}
+
+func TestMetricsTestSuite(t *testing.T) {
+	suite.Run(t, new(MetricsTestSuite))
+}
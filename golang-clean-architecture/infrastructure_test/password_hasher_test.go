@@ -0,0 +1,65 @@
+package infrastructure_test
+
+import (
+	"golang-clean-architecture/domain"
+	"golang-clean-architecture/infrastructure"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type PasswordHasherTestSuite struct {
+	suite.Suite
+	params infrastructure.Argon2Params
+	hasher domain.PasswordHasher
+}
+
+func (suite *PasswordHasherTestSuite) SetupTest() {
+	suite.params = infrastructure.Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+	suite.hasher = infrastructure.NewPasswordHasher(suite.params)
+}
+
+func (suite *PasswordHasherTestSuite) TestHashIsSelfDescribing() {
+	hash, err := suite.hasher.Hash("correct horse battery")
+	suite.NoError(err)
+	suite.True(strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$"), hash)
+	suite.NoError(suite.hasher.Compare(hash, "correct horse battery"))
+	suite.EqualError(suite.hasher.Compare(hash, "correct horse battery!"), "passwords don't match")
+	suite.False(suite.hasher.NeedsRehash(hash))
+
+	again, err := suite.hasher.Hash("correct horse battery")
+	suite.NoError(err)
+	suite.NotEqual(hash, again, "every hash gets its own salt")
+}
+
+func (suite *PasswordHasherTestSuite) TestBcryptHashesStillVerify() {
+	hash, err := bcrypt.GenerateFromPassword([]byte("correct horse battery"), bcrypt.MinCost)
+	suite.NoError(err)
+	suite.NoError(suite.hasher.Compare(string(hash), "correct horse battery"))
+	suite.Error(suite.hasher.Compare(string(hash), "wrong-password"))
+	suite.True(suite.hasher.NeedsRehash(string(hash)))
+}
+
+func (suite *PasswordHasherTestSuite) TestChangedParamsNeedRehash() {
+	hash, err := suite.hasher.Hash("correct horse battery")
+	suite.NoError(err)
+
+	stronger := suite.params
+	stronger.Iterations = 2
+	hasher := infrastructure.NewPasswordHasher(stronger)
+	suite.NoError(hasher.Compare(hash, "correct horse battery"), "old hashes verify with the parameters they were made with")
+	suite.True(hasher.NeedsRehash(hash))
+}
+
+func (suite *PasswordHasherTestSuite) TestMalformedHashesDontMatch() {
+	for _, hash := range []string{"", "plaintext", "$argon2id$v=19$m=64,t=1,p=1$c2FsdA", "$argon2id$v=16$m=64,t=1,p=1$c2FsdHNhbHRzYWx0c2FsdA$a2V5"} {
+		suite.Error(suite.hasher.Compare(hash, "correct horse battery"), hash)
+		suite.True(suite.hasher.NeedsRehash(hash), hash)
+	}
+}
+
+func TestPasswordHasherTestSuite(t *testing.T) {
+	suite.Run(t, new(PasswordHasherTestSuite))
+}
@@ -0,0 +1,71 @@
+package infrastructure_test
+
+import (
+	"errors"
+	"golang-clean-architecture/domain"
+	"golang-clean-architecture/infrastructure"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type PasswordPolicyTestSuite struct {
+	suite.Suite
+}
+
+func (suite *PasswordPolicyTestSuite) violatedRules(err error) []string {
+	var policyErr *domain.PasswordPolicyError
+	if !errors.As(err, &policyErr) {
+		return nil
+	}
+	rules := []string{}
+	for _, violation := range policyErr.Violations {
+		rules = append(rules, violation.Rule)
+	}
+	return rules
+}
+
+func (suite *PasswordPolicyTestSuite) TestEveryBrokenRuleIsReported() {
+	rules := infrastructure.DefaultPasswordRules()
+	rules.MinCharacterClasses = 3
+	policy := infrastructure.NewPasswordPolicy(rules)
+
+	cases := []struct {
+		password string
+		email    string
+		expected []string
+	}{
+		{"Correct-horse-7", "kidusm3l@gmail.com", nil},
+		{"a", "", []string{"min_length", "character_classes"}},
+		{strings.Repeat("Ab1", 25), "", []string{"max_length"}},
+		{"correcthorse", "", []string{"character_classes"}},
+		{"Kidusm3l@gmail.com", "kidusm3l@gmail.com", []string{"email"}},
+		{"KIDUSM3L", "kidusm3l@gmail.com", []string{"character_classes", "email"}},
+		{"Kidusm3l@gmail.com", "", nil},
+		{"P@ssw0rd", "", []string{"common"}},
+	}
+	for _, c := range cases {
+		err := policy.Validate(c.password, c.email)
+		if c.expected == nil {
+			suite.NoError(err, c.password)
+			continue
+		}
+		suite.Equal(c.expected, suite.violatedRules(err), c.password)
+	}
+}
+
+func (suite *PasswordPolicyTestSuite) TestRulesCanBeTurnedOff() {
+	rules := infrastructure.DefaultPasswordRules()
+	rules.RejectCommon = false
+	rules.RejectEmail = false
+	policy := infrastructure.NewPasswordPolicy(rules)
+
+	suite.NoError(policy.Validate("password123", ""))
+	suite.NoError(policy.Validate("jane.doe@example.com", "jane.doe@example.com"))
+	suite.EqualError(policy.Validate("short", ""), "password must be at least 8 characters long")
+}
+
+func TestPasswordPolicyTestSuite(t *testing.T) {
+	suite.Run(t, new(PasswordPolicyTestSuite))
+}
@@ -0,0 +1,116 @@
+package infrastructure_test
+
+import (
+	"golang-clean-architecture/domain"
+	"golang-clean-architecture/infrastructure"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+)
+
+type RateLimiterTestSuite struct {
+	suite.Suite
+	store *infrastructure.MemoryRateLimitStore
+	now   time.Time
+}
+
+func (suite *RateLimiterTestSuite) SetupTest() {
+	suite.store = infrastructure.NewMemoryRateLimitStore()
+	suite.now = time.Date(2024, 8, 10, 9, 0, 0, 0, time.UTC)
+}
+
+func (suite *RateLimiterTestSuite) TestBucketAllowsBurstThenRefills() {
+	limit := domain.RateLimit{Requests: 3, Per: 3 * time.Second}
+	for remaining := 2; remaining >= 0; remaining-- {
+		result, err := suite.store.Take("client", limit, suite.now)
+		suite.NoError(err)
+		suite.True(result.Allowed)
+		suite.Equal(remaining, result.Remaining)
+	}
+
+	result, err := suite.store.Take("client", limit, suite.now)
+	suite.NoError(err)
+	suite.False(result.Allowed)
+	suite.Equal(time.Second, result.RetryAfter)
+	suite.Equal(3*time.Second, result.ResetAfter)
+
+	result, err = suite.store.Take("client", limit, suite.now.Add(time.Second))
+	suite.NoError(err)
+	suite.True(result.Allowed, "one token is back after a second")
+
+	result, err = suite.store.Take("other", limit, suite.now)
+	suite.NoError(err)
+	suite.True(result.Allowed, "buckets are per key")
+}
+
+func (suite *RateLimiterTestSuite) TestParseRateLimit() {
+	limit, err := infrastructure.ParseRateLimit("100/1m")
+	suite.NoError(err)
+	suite.Equal(domain.RateLimit{Requests: 100, Per: time.Minute}, limit)
+
+	for _, value := range []string{"100", "0/1m", "ten/1m", "5/soon", "5/-1s"} {
+		_, err := infrastructure.ParseRateLimit(value)
+		suite.Error(err, value)
+	}
+}
+
+func (suite *RateLimiterTestSuite) TestMiddlewareHeadersAndRejection() {
+	limiter := infrastructure.NewRateLimiter(suite.store)
+	router := gin.New()
+	router.POST("/login", limiter.Limit("login", domain.RateLimit{Requests: 2, Per: time.Hour}), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	request := func(ip string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodPost, "/login", nil)
+		req.RemoteAddr = ip + ":4321"
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	first := request("203.0.113.7")
+	suite.Equal(http.StatusOK, first.Code)
+	suite.Equal("2", first.Header().Get("RateLimit-Limit"))
+	suite.Equal("1", first.Header().Get("RateLimit-Remaining"))
+
+	suite.Equal(http.StatusOK, request("203.0.113.7").Code)
+	rejected := request("203.0.113.7")
+	suite.Equal(http.StatusTooManyRequests, rejected.Code)
+	suite.Equal("0", rejected.Header().Get("RateLimit-Remaining"))
+	suite.Equal("1800", rejected.Header().Get("Retry-After"))
+
+	suite.Equal(http.StatusOK, request("198.51.100.1").Code, "other clients have their own bucket")
+}
+
+func (suite *RateLimiterTestSuite) TestMiddlewareKeysAuthenticatedUsersByEmail() {
+	limiter := infrastructure.NewRateLimiter(suite.store)
+	tokens := newTestTokenService(suite.T())
+	router := gin.New()
+	router.GET("/tasks", infrastructure.AuthMiddleWare(tokens), limiter.Limit("default", domain.RateLimit{Requests: 1, Per: time.Hour}), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	request := func(email string, ip string) int {
+		token, err := tokens.IssueToken(&domain.User{Email: email, Role: "user"})
+		suite.Require().NoError(err)
+		req, _ := http.NewRequest(http.MethodGet, "/tasks", nil)
+		req.RemoteAddr = ip + ":4321"
+		req.Header.Set("Authorization", "Bearer "+token.Token)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+
+	suite.Equal(http.StatusOK, request("kidusm3l@gmail.com", "203.0.113.7"))
+	suite.Equal(http.StatusTooManyRequests, request("kidusm3l@gmail.com", "198.51.100.1"), "changing IP doesn't reset a user's bucket")
+	suite.Equal(http.StatusOK, request("other@example.com", "203.0.113.7"), "users behind the same IP don't share a bucket")
+}
+
+func TestRateLimiterTestSuite(t *testing.T) {
+	suite.Run(t, new(RateLimiterTestSuite))
+}
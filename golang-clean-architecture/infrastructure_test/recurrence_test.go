@@ -0,0 +1,98 @@
+package infrastructure_test
+
+import (
+	"golang-clean-architecture/infrastructure"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type RecurrenceTestSuite struct {
+	suite.Suite
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 9, 0, 0, 0, time.UTC)
+}
+
+func (suite *RecurrenceTestSuite) next(rule string, previous time.Time, occurrence int) (time.Time, bool) {
+	parsed, err := infrastructure.ParseRecurrenceRule(rule)
+	suite.Require().NoError(err)
+	return infrastructure.NextOccurrence(parsed, previous, occurrence)
+}
+
+func (suite *RecurrenceTestSuite) TestParseRejectsUnsupportedRules() {
+	for _, rule := range []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=YEARLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=MONTHLY;BYDAY=MO",
+		"FREQ=DAILY;COUNT=3;UNTIL=20240101",
+		"FREQ=DAILY;BYHOUR=9",
+	} {
+		_, err := infrastructure.ParseRecurrenceRule(rule)
+		suite.Error(err, rule)
+	}
+}
+
+func (suite *RecurrenceTestSuite) TestDailyWithInterval() {
+	next, ok := suite.next("RRULE:FREQ=DAILY;INTERVAL=3", date(2024, time.January, 30), 1)
+	suite.True(ok)
+	suite.Equal(date(2024, time.February, 2), next)
+}
+
+func (suite *RecurrenceTestSuite) TestDailyOnWeekdays() {
+	// Friday -> Monday
+	next, ok := suite.next("FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR", date(2024, time.March, 8), 1)
+	suite.True(ok)
+	suite.Equal(date(2024, time.March, 11), next)
+}
+
+func (suite *RecurrenceTestSuite) TestWeeklyByDayWithInterval() {
+	rule := "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH"
+	// Monday -> Thursday of the same week
+	next, ok := suite.next(rule, date(2024, time.March, 4), 1)
+	suite.True(ok)
+	suite.Equal(date(2024, time.March, 7), next)
+
+	// Thursday -> Monday two weeks after the previous Monday
+	next, ok = suite.next(rule, date(2024, time.March, 7), 2)
+	suite.True(ok)
+	suite.Equal(date(2024, time.March, 18), next)
+}
+
+func (suite *RecurrenceTestSuite) TestWeeklyWithoutByDayKeepsWeekday() {
+	next, ok := suite.next("FREQ=WEEKLY", date(2024, time.March, 6), 1)
+	suite.True(ok)
+	suite.Equal(date(2024, time.March, 13), next)
+}
+
+func (suite *RecurrenceTestSuite) TestMonthlySkipsShortMonths() {
+	next, ok := suite.next("FREQ=MONTHLY", date(2024, time.January, 31), 1)
+	suite.True(ok)
+	suite.Equal(date(2024, time.March, 31), next)
+}
+
+func (suite *RecurrenceTestSuite) TestCountEndsSeries() {
+	_, ok := suite.next("FREQ=DAILY;COUNT=3", date(2024, time.January, 3), 3)
+	suite.False(ok)
+
+	_, ok = suite.next("FREQ=DAILY;COUNT=3", date(2024, time.January, 2), 2)
+	suite.True(ok)
+}
+
+func (suite *RecurrenceTestSuite) TestUntilIncludesWholeDay() {
+	next, ok := suite.next("FREQ=DAILY;UNTIL=20240105", date(2024, time.January, 4), 4)
+	suite.True(ok)
+	suite.Equal(date(2024, time.January, 5), next)
+
+	_, ok = suite.next("FREQ=DAILY;UNTIL=20240105", next, 5)
+	suite.False(ok)
+}
+
+func TestRecurrenceTestSuite(t *testing.T) {
+	suite.Run(t, new(RecurrenceTestSuite))
+}
@@ -0,0 +1,100 @@
+package infrastructure_test
+
+import (
+	"golang-clean-architecture/domain"
+	"golang-clean-architecture/infrastructure"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+)
+
+// base32 of the ASCII secret "12345678901234567890" used by the RFC 6238
+// test vectors
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+type TOTPTestSuite struct {
+	suite.Suite
+}
+
+func (suite *TOTPTestSuite) TestRFC6238Vectors() {
+	// the RFC lists 8 digit codes; 6 digit codes are their last six digits
+	vectors := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+	for unix, expected := range vectors {
+		code, err := infrastructure.TOTPCode(rfcSecret, infrastructure.TOTPStep(time.Unix(unix, 0)))
+		suite.NoError(err)
+		suite.Equal(expected, code, unix)
+	}
+}
+
+func (suite *TOTPTestSuite) TestValidateAllowsOneStepOfDrift() {
+	now := time.Unix(1111111109, 0)
+	previous, err := infrastructure.TOTPCode(rfcSecret, infrastructure.TOTPStep(now)-1)
+	suite.NoError(err)
+	step, ok := infrastructure.ValidateTOTP(rfcSecret, previous, now)
+	suite.True(ok)
+	suite.Equal(infrastructure.TOTPStep(now)-1, step)
+
+	old, err := infrastructure.TOTPCode(rfcSecret, infrastructure.TOTPStep(now)-2)
+	suite.NoError(err)
+	_, ok = infrastructure.ValidateTOTP(rfcSecret, old, now)
+	suite.False(ok)
+
+	_, ok = infrastructure.ValidateTOTP(rfcSecret, "12345", now)
+	suite.False(ok)
+}
+
+func (suite *TOTPTestSuite) TestProvisioningURI() {
+	uri, err := url.Parse(infrastructure.TOTPProvisioningURI("Task Manager", "kidusm3l@gmail.com", rfcSecret))
+	suite.NoError(err)
+	suite.Equal("otpauth", uri.Scheme)
+	suite.Equal("totp", uri.Host)
+	suite.Equal("/Task Manager:kidusm3l@gmail.com", uri.Path)
+	suite.Equal(rfcSecret, uri.Query().Get("secret"))
+	suite.Equal("Task Manager", uri.Query().Get("issuer"))
+}
+
+func (suite *TOTPTestSuite) TestTwoFactorMiddleWareRequiresAdmins() {
+	tokens := newTestTokenService(suite.T())
+	router := gin.New()
+	handler := func(c *gin.Context) { c.Status(http.StatusOK) }
+	middlewares := []gin.HandlerFunc{infrastructure.AuthMiddleWare(tokens), infrastructure.TwoFactorMiddleWare(true, "/")}
+	router.PUT("/promote/:id", append(middlewares, handler)...)
+	router.POST("/me/2fa/enroll", append(middlewares, handler)...)
+	v1 := router.Group("/api/v1", infrastructure.AuthMiddleWare(tokens), infrastructure.TwoFactorMiddleWare(true, "/api/v1"))
+	v1.PUT("/promote/:id", handler)
+	v1.GET("/me", handler)
+
+	request := func(user *domain.User, method string, path string) int {
+		token, err := tokens.IssueToken(user)
+		suite.Require().NoError(err)
+		req, _ := http.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token.Token)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+
+	admin := &domain.User{Email: "kidusm3l@gmail.com", Role: "admin"}
+	suite.Equal(http.StatusForbidden, request(admin, http.MethodPut, "/promote/12345"))
+	suite.Equal(http.StatusOK, request(admin, http.MethodPost, "/me/2fa/enroll"), "admins can still enroll")
+	suite.Equal(http.StatusForbidden, request(admin, http.MethodPut, "/api/v1/promote/12345"))
+	suite.Equal(http.StatusOK, request(admin, http.MethodGet, "/api/v1/me"), "routes are matched below the base path")
+
+	admin.TwoFactorEnabled = true
+	suite.Equal(http.StatusOK, request(admin, http.MethodPut, "/promote/12345"))
+	suite.Equal(http.StatusOK, request(&domain.User{Email: "user@example.com", Role: "user"}, http.MethodPut, "/promote/12345"), "users are left to the handlers")
+}
+
+func TestTOTPTestSuite(t *testing.T) {
+	suite.Run(t, new(TOTPTestSuite))
+}
@@ -0,0 +1,142 @@
+package infrastructure_test
+
+import (
+	"context"
+	"errors"
+	"golang-clean-architecture/domain"
+	"golang-clean-architecture/domain/mocks"
+	"golang-clean-architecture/infrastructure"
+	"golang-clean-architecture/use_cases"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	mongoevent "go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type TracingTestSuite struct {
+	suite.Suite
+	recorder *tracetest.SpanRecorder
+	tracing  *infrastructure.Tracing
+}
+
+func (suite *TracingTestSuite) SetupTest() {
+	suite.recorder = tracetest.NewSpanRecorder()
+	suite.tracing = infrastructure.NewTracing(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(suite.recorder)))
+}
+
+// span returns the ended span called name.
+func (suite *TracingTestSuite) span(name string) sdktrace.ReadOnlySpan {
+	for _, span := range suite.recorder.Ended() {
+		if span.Name() == name {
+			return span
+		}
+	}
+	suite.FailNow("span not found", name)
+	return nil
+}
+
+func (suite *TracingTestSuite) attribute(span sdktrace.ReadOnlySpan, key string) attribute.Value {
+	for _, kv := range span.Attributes() {
+		if string(kv.Key) == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func (suite *TracingTestSuite) TestSpansNestFromRequestToRepository() {
+	tasks := new(mocks.TaskRepository)
+	tasks.On("GetTasks", mock.Anything).Return([]*domain.Task{}, nil)
+	searcher := new(mocks.TaskSearcher)
+	events := new(mocks.EventPublisher)
+	repository := infrastructure.TraceTaskRepository(tasks, suite.tracing)
+	useCase := infrastructure.TraceTaskUseCase(use_cases.NewTaskUseCase(repository, searcher, events), suite.tracing)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(suite.tracing.MiddleWare())
+	router.GET("/tasks", func(c *gin.Context) {
+		_, err := useCase.GetTasks(c.Request.Context())
+		suite.NoError(err)
+		c.Status(http.StatusOK)
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+	request.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), request)
+
+	server := suite.span("GET /tasks")
+	suite.Equal("4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext().TraceID().String(), "the caller's trace is continued")
+	suite.Equal("00f067aa0ba902b7", server.Parent().SpanID().String())
+	suite.Equal("/tasks", suite.attribute(server, "http.route").AsString())
+	suite.Equal(int64(http.StatusOK), suite.attribute(server, "http.response.status_code").AsInt64())
+
+	useCaseSpan := suite.span("TaskUseCase.GetTasks")
+	suite.Equal(server.SpanContext().SpanID(), useCaseSpan.Parent().SpanID())
+	repositorySpan := suite.span("TaskRepository.GetTasks")
+	suite.Equal(useCaseSpan.SpanContext().SpanID(), repositorySpan.Parent().SpanID())
+}
+
+func (suite *TracingTestSuite) TestUnmatchedRequestsShareASpanName() {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(suite.tracing.MiddleWare())
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/nowhere/1234", nil))
+
+	span := suite.span("GET unmatched")
+	suite.Equal("/nowhere/1234", suite.attribute(span, "url.path").AsString())
+}
+
+func (suite *TracingTestSuite) TestErrorsMarkSpansAsFailed() {
+	users := new(mocks.UserRepository)
+	users.On("GetUserByID", mock.Anything, "1").Return(domain.User{}, errors.New("no user with the specified id found"))
+	users.On("VerifyFirst", mock.Anything, mock.Anything).Return(errors.New("a user is found on db"))
+	traced := infrastructure.TraceUserRepository(users, suite.tracing)
+
+	_, err := traced.GetUserByID(context.Background(), "1")
+	suite.EqualError(err, "no user with the specified id found", "errors are passed through")
+	err = traced.VerifyFirst(context.Background(), &domain.User{})
+	suite.EqualError(err, "a user is found on db")
+
+	suite.Equal(codes.Error, suite.span("UserRepository.GetUserByID").Status().Code)
+	suite.NotEqual(codes.Error, suite.span("UserRepository.VerifyFirst").Status().Code, "an existing user isn't a failure")
+}
+
+func (suite *TracingTestSuite) TestMongoCommandsAreChildSpans() {
+	monitor := suite.tracing.CommandMonitor()
+	ctx, parent := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(suite.recorder)).Tracer("test").Start(context.Background(), "parent")
+
+	find, err := bson.Marshal(bson.D{{Key: "find", Value: "tasks"}, {Key: "filter", Value: bson.D{}}})
+	suite.Require().NoError(err)
+	monitor.Started(ctx, &mongoevent.CommandStartedEvent{Command: find, CommandName: "find", DatabaseName: "task_management", ConnectionID: "c1", RequestID: 1})
+	monitor.Succeeded(ctx, &mongoevent.CommandSucceededEvent{CommandFinishedEvent: mongoevent.CommandFinishedEvent{CommandName: "find", ConnectionID: "c1", RequestID: 1}})
+
+	insert, err := bson.Marshal(bson.D{{Key: "insert", Value: "users"}})
+	suite.Require().NoError(err)
+	monitor.Started(ctx, &mongoevent.CommandStartedEvent{Command: insert, CommandName: "insert", DatabaseName: "task_management", ConnectionID: "c1", RequestID: 2})
+	monitor.Failed(ctx, &mongoevent.CommandFailedEvent{CommandFinishedEvent: mongoevent.CommandFinishedEvent{CommandName: "insert", ConnectionID: "c1", RequestID: 2}, Failure: "duplicate key"})
+	parent.End()
+
+	span := suite.span("find tasks")
+	suite.Equal(parent.SpanContext().SpanID(), span.Parent().SpanID())
+	suite.Equal("mongodb", suite.attribute(span, "db.system").AsString())
+	suite.Equal("tasks", suite.attribute(span, "db.mongodb.collection").AsString())
+
+	failed := suite.span("insert users")
+	suite.Equal(codes.Error, failed.Status().Code)
+	suite.Equal("duplicate key", failed.Status().Description)
+}
+
+func TestTracingTestSuite(t *testing.T) {
+	suite.Run(t, new(TracingTestSuite))
+}
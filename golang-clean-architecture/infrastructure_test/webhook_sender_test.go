@@ -0,0 +1,47 @@
+package infrastructure_test
+
+import (
+	"golang-clean-architecture/domain"
+	"golang-clean-architecture/infrastructure"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type WebhookSenderTestSuite struct {
+	suite.Suite
+}
+
+func (suite *WebhookSenderTestSuite) TestSendSignsPayload() {
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	subscription := &domain.WebhookSubscription{URL: server.URL, Secret: "s3cret"}
+	delivery := &domain.WebhookDelivery{ID: primitive.NewObjectID(), EventType: "task.created", Payload: `{"type":"task.created"}`}
+
+	statusCode, err := infrastructure.NewHTTPWebhookSender().Send(subscription, delivery)
+	suite.NoError(err)
+	suite.Equal(http.StatusAccepted, statusCode)
+	suite.Equal(delivery.Payload, string(body))
+	suite.Equal("task.created", received.Header.Get("X-Webhook-Event"))
+
+	timestamp, err := strconv.ParseInt(received.Header.Get("X-Webhook-Timestamp"), 10, 64)
+	suite.NoError(err)
+	suite.Equal(infrastructure.SignWebhookPayload("s3cret", timestamp, body), received.Header.Get("X-Webhook-Signature"))
+	suite.NotEqual(infrastructure.SignWebhookPayload("other", timestamp, body), received.Header.Get("X-Webhook-Signature"))
+}
+
+func TestWebhookSenderTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookSenderTestSuite))
+}
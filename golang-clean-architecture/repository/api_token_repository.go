@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"golang-clean-architecture/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type APITokenRepository struct {
+	Database   *mongo.Database
+	Collection string
+}
+
+func NewAPITokenRepository(db *mongo.Database, collection string) domain.APITokenRepository {
+	return &APITokenRepository{
+		Database:   db,
+		Collection: collection,
+	}
+}
+
+// EnsureAPITokenIndexes makes token hashes unique, speeds up listing a
+// user's tokens and lets MongoDB drop tokens once they expire.
+func EnsureAPITokenIndexes(db *mongo.Database, collection string) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}},
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}
+	_, err := db.Collection(collection).Indexes().CreateMany(context.TODO(), indexes)
+	if err != nil {
+		return errors.New("error while creating api token indexes")
+	}
+	return nil
+}
+
+func (ar *APITokenRepository) CreateAPIToken(ctx context.Context, token *domain.APIToken) error {
+	collection := ar.Database.Collection(ar.Collection)
+	token.ID = primitive.NewObjectID()
+	_, err := collection.InsertOne(ctx, token)
+	if err != nil {
+		return errors.New("error while trying to insert data")
+	}
+	return nil
+}
+
+func (ar *APITokenRepository) GetAPITokens(ctx context.Context, userID string) ([]*domain.APIToken, error) {
+	collection := ar.Database.Collection(ar.Collection)
+	findOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cur, err := collection.Find(ctx, bson.D{{Key: "user_id", Value: userID}}, findOptions)
+	if err != nil {
+		return nil, errors.New("error while fetching api tokens")
+	}
+	defer cur.Close(ctx)
+
+	tokens := []*domain.APIToken{}
+	if err := cur.All(ctx, &tokens); err != nil {
+		return nil, errors.New("error while fetching api tokens")
+	}
+	return tokens, nil
+}
+
+func (ar *APITokenRepository) GetAPITokenByHash(ctx context.Context, tokenHash string) (domain.APIToken, error) {
+	var token domain.APIToken
+	collection := ar.Database.Collection(ar.Collection)
+	err := collection.FindOne(ctx, bson.D{{Key: "token_hash", Value: tokenHash}}).Decode(&token)
+	if err == mongo.ErrNoDocuments {
+		return domain.APIToken{}, errors.New("api token not found")
+	}
+	if err != nil {
+		return domain.APIToken{}, errors.New("internal server error")
+	}
+	return token, nil
+}
+
+// DeleteAPIToken only deletes the token if it belongs to userID, so users
+// can't revoke each other's tokens by guessing IDs.
+func (ar *APITokenRepository) DeleteAPIToken(ctx context.Context, userID string, tokenID string) error {
+	processedID, err := primitive.ObjectIDFromHex(tokenID)
+	if err != nil {
+		return errors.New("invalid api token id")
+	}
+
+	collection := ar.Database.Collection(ar.Collection)
+	filter := bson.D{{Key: "_id", Value: processedID}, {Key: "user_id", Value: userID}}
+	deleteResult, err := collection.DeleteOne(ctx, filter)
+	if err != nil {
+		return errors.New("internal server error")
+	}
+	if deleteResult.DeletedCount == 0 {
+		return errors.New("api token not found")
+	}
+	return nil
+}
+
+func (ar *APITokenRepository) TouchAPIToken(ctx context.Context, tokenID string, usedAt time.Time) error {
+	processedID, err := primitive.ObjectIDFromHex(tokenID)
+	if err != nil {
+		return errors.New("invalid api token id")
+	}
+
+	collection := ar.Database.Collection(ar.Collection)
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "last_used_at", Value: usedAt}}}}
+	_, err = collection.UpdateOne(ctx, bson.D{{Key: "_id", Value: processedID}}, update)
+	if err != nil {
+		return errors.New("internal server error")
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"golang-clean-architecture/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// loginAttemptRetention is how long counters outlive their last change before
+// MongoDB's TTL monitor removes them.
+const loginAttemptRetention = 24 * time.Hour
+
+// LoginAttemptRepository stores failed login counters in MongoDB so that every
+// instance of the API sees the same counts.
+type LoginAttemptRepository struct {
+	Database   *mongo.Database
+	Collection string
+}
+
+func NewLoginAttemptRepository(db *mongo.Database, collection string) domain.LoginAttemptStore {
+	return &LoginAttemptRepository{
+		Database:   db,
+		Collection: collection,
+	}
+}
+
+func EnsureLoginAttemptIndexes(db *mongo.Database, collection string) error {
+	index := mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	}
+	_, err := db.Collection(collection).Indexes().CreateOne(context.TODO(), index)
+	if err != nil {
+		return errors.New("error while creating login attempt indexes")
+	}
+	return nil
+}
+
+func (lr *LoginAttemptRepository) Get(ctx context.Context, key string) (domain.LoginAttempts, error) {
+	collection := lr.Database.Collection(lr.Collection)
+	var attempts domain.LoginAttempts
+	err := collection.FindOne(ctx, bson.D{{Key: "_id", Value: key}}).Decode(&attempts)
+	if err == mongo.ErrNoDocuments {
+		return domain.LoginAttempts{}, nil
+	}
+	if err != nil {
+		return domain.LoginAttempts{}, errors.New("internal server error")
+	}
+	return attempts, nil
+}
+
+// RecordFailure increments the counter in a single update so that concurrent
+// failures on different instances are all counted. The count restarts when
+// the previous failure is older than window.
+func (lr *LoginAttemptRepository) RecordFailure(ctx context.Context, key string, now time.Time, window time.Duration) (domain.LoginAttempts, error) {
+	collection := lr.Database.Collection(lr.Collection)
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.D{
+			{Key: "failures", Value: bson.D{{Key: "$cond", Value: bson.A{
+				bson.D{{Key: "$gte", Value: bson.A{"$last_failure", now.Add(-window)}}},
+				bson.D{{Key: "$add", Value: bson.A{"$failures", 1}}},
+				1,
+			}}}},
+			{Key: "last_failure", Value: now},
+			{Key: "expires_at", Value: bson.D{{Key: "$max", Value: bson.A{
+				"$locked_until",
+				now.Add(loginAttemptRetention),
+			}}}},
+		}}},
+	}
+	updateOptions := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var attempts domain.LoginAttempts
+	err := collection.FindOneAndUpdate(ctx, bson.D{{Key: "_id", Value: key}}, update, updateOptions).Decode(&attempts)
+	if err != nil {
+		return domain.LoginAttempts{}, errors.New("internal server error")
+	}
+	return attempts, nil
+}
+
+func (lr *LoginAttemptRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	collection := lr.Database.Collection(lr.Collection)
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "locked_until", Value: until},
+		{Key: "expires_at", Value: until.Add(loginAttemptRetention)},
+	}}}
+	_, err := collection.UpdateOne(ctx, bson.D{{Key: "_id", Value: key}}, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return errors.New("internal server error")
+	}
+	return nil
+}
+
+func (lr *LoginAttemptRepository) Reset(ctx context.Context, key string) error {
+	collection := lr.Database.Collection(lr.Collection)
+	_, err := collection.DeleteOne(ctx, bson.D{{Key: "_id", Value: key}})
+	if err != nil {
+		return errors.New("internal server error")
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"golang-clean-architecture/domain"
+	"strings"
+	"unicode"
+)
+
+// Field weights shared by the Mongo text index and the in-process inverted
+// index so both backends rank results the same way.
+const (
+	titleSearchWeight       = 10
+	descriptionSearchWeight = 5
+	commentSearchWeight     = 2
+	snippetRadius           = 60
+)
+
+// searchStopWords holds the most common entries of MongoDB's English stop word
+// list; neither backend indexes them.
+var searchStopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "for": true, "from": true, "in": true, "is": true,
+	"it": true, "of": true, "on": true, "or": true, "that": true, "the": true,
+	"this": true, "to": true, "was": true, "with": true,
+}
+
+type textSpan struct {
+	start int
+	end   int
+}
+
+// tokenize lower-cases text, splits it into words and reduces each word to a
+// crude stem so "deploying" and "deploy" match each other.
+func tokenize(text string) []string {
+	var terms []string
+	for _, span := range wordSpans(text) {
+		word := strings.ToLower(text[span.start:span.end])
+		if searchStopWords[word] {
+			continue
+		}
+		terms = append(terms, stem(word))
+	}
+	return terms
+}
+
+func stem(word string) string {
+	for _, suffix := range []string{"ing", "ed", "es", "s"} {
+		if len(word) > len(suffix)+2 && strings.HasSuffix(word, suffix) {
+			return strings.TrimSuffix(word, suffix)
+		}
+	}
+	return word
+}
+
+func wordSpans(text string) []textSpan {
+	var spans []textSpan
+	start := -1
+	for i, r := range text {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if isWord && start < 0 {
+			start = i
+		}
+		if !isWord && start >= 0 {
+			spans = append(spans, textSpan{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, textSpan{start, len(text)})
+	}
+	return spans
+}
+
+// highlight wraps the words of text matching one of the query terms in <mark>
+// tags and trims the result to a window around the first match. It returns an
+// empty string when nothing in text matches.
+func highlight(text string, terms []string) string {
+	wanted := make(map[string]bool, len(terms))
+	for _, term := range terms {
+		wanted[term] = true
+	}
+
+	var matches []textSpan
+	for _, span := range wordSpans(text) {
+		if wanted[stem(strings.ToLower(text[span.start:span.end]))] {
+			matches = append(matches, span)
+		}
+	}
+	if len(matches) == 0 {
+		return ""
+	}
+
+	from, to := 0, len(text)
+	if matches[0].start > snippetRadius {
+		from = matches[0].start
+		if space := strings.IndexByte(text[matches[0].start-snippetRadius:], ' '); space >= 0 {
+			from = matches[0].start - snippetRadius + space + 1
+		}
+	}
+	if matches[0].end+snippetRadius < len(text) {
+		if space := strings.IndexByte(text[matches[0].end+snippetRadius:], ' '); space >= 0 {
+			to = matches[0].end + snippetRadius + space
+		}
+	}
+
+	var builder strings.Builder
+	if from > 0 {
+		builder.WriteString("…")
+	}
+	cursor := from
+	for _, match := range matches {
+		if match.start < cursor || match.end > to {
+			continue
+		}
+		builder.WriteString(text[cursor:match.start])
+		builder.WriteString("<mark>")
+		builder.WriteString(text[match.start:match.end])
+		builder.WriteString("</mark>")
+		cursor = match.end
+	}
+	builder.WriteString(text[cursor:to])
+	if to < len(text) {
+		builder.WriteString("…")
+	}
+	return builder.String()
+}
+
+func highlightTask(task *domain.Task, terms []string) map[string]string {
+	highlights := map[string]string{}
+	if snippet := highlight(task.Title, terms); snippet != "" {
+		highlights["title"] = snippet
+	}
+	if snippet := highlight(task.Description, terms); snippet != "" {
+		highlights["description"] = snippet
+	}
+	// only the first matching comment is shown
+	for _, comment := range task.Comments {
+		if snippet := highlight(comment, terms); snippet != "" {
+			highlights["comments"] = snippet
+			break
+		}
+	}
+	return highlights
+}
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"golang-clean-architecture/domain"
+	"math"
+	"sort"
+	"sync"
+)
+
+// IndexedTaskRepository wraps any domain.TaskRepository with an in-process
+// inverted index so backends without native full-text search can serve
+// SearchTasks. The index is built from the wrapped repository on the first
+// search and kept current by the write methods afterwards.
+type IndexedTaskRepository struct {
+	domain.TaskRepository
+	mutex    sync.RWMutex
+	loaded   bool
+	tasks    map[string]*domain.Task
+	postings map[string]map[string]float64
+}
+
+func NewIndexedTaskRepository(tr domain.TaskRepository) *IndexedTaskRepository {
+	return &IndexedTaskRepository{
+		TaskRepository: tr,
+		tasks:          map[string]*domain.Task{},
+		postings:       map[string]map[string]float64{},
+	}
+}
+
+func (ir *IndexedTaskRepository) PostTask(ctx context.Context, task *domain.Task) error {
+	err := ir.TaskRepository.PostTask(ctx, task)
+	if err != nil {
+		return err
+	}
+
+	ir.mutex.Lock()
+	defer ir.mutex.Unlock()
+	if ir.loaded {
+		indexed := *task
+		ir.index(&indexed)
+	}
+	return nil
+}
+
+func (ir *IndexedTaskRepository) UpdateTask(ctx context.Context, taskID string, modified *domain.Task) error {
+	err := ir.TaskRepository.UpdateTask(ctx, taskID, modified)
+	if err != nil {
+		return err
+	}
+
+	ir.mutex.Lock()
+	defer ir.mutex.Unlock()
+	if !ir.loaded {
+		return nil
+	}
+	task, err := ir.TaskRepository.GetTask(ctx, taskID)
+	if err != nil {
+		// the update went through but we can't see the result; rebuild the
+		// whole index on the next search instead of serving stale entries
+		ir.loaded = false
+		return nil
+	}
+	ir.index(&task)
+	return nil
+}
+
+func (ir *IndexedTaskRepository) DeleteTask(ctx context.Context, taskID string) error {
+	err := ir.TaskRepository.DeleteTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	ir.mutex.Lock()
+	defer ir.mutex.Unlock()
+	ir.remove(taskID)
+	return nil
+}
+
+func (ir *IndexedTaskRepository) SearchTasks(ctx context.Context, query string, limit int) ([]*domain.TaskSearchResult, error) {
+	if err := ir.load(ctx); err != nil {
+		return nil, err
+	}
+
+	ir.mutex.RLock()
+	defer ir.mutex.RUnlock()
+
+	terms := tokenize(query)
+	seen := map[string]bool{}
+	scores := map[string]float64{}
+	for _, term := range terms {
+		if seen[term] {
+			continue
+		}
+		seen[term] = true
+		postings := ir.postings[term]
+		idf := math.Log(1 + float64(len(ir.tasks))/float64(len(postings)+1))
+		for taskID, weight := range postings {
+			scores[taskID] += weight * idf
+		}
+	}
+
+	results := []*domain.TaskSearchResult{}
+	for taskID, score := range scores {
+		task := *ir.tasks[taskID]
+		results = append(results, &domain.TaskSearchResult{
+			Task:       &task,
+			Score:      score,
+			Highlights: highlightTask(&task, terms),
+		})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Task.Title < results[j].Task.Title
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+func (ir *IndexedTaskRepository) load(ctx context.Context) error {
+	ir.mutex.Lock()
+	defer ir.mutex.Unlock()
+	if ir.loaded {
+		return nil
+	}
+
+	tasks, err := ir.TaskRepository.GetTasks(ctx)
+	if err != nil {
+		return errors.New("error while searching tasks")
+	}
+	ir.tasks = map[string]*domain.Task{}
+	ir.postings = map[string]map[string]float64{}
+	for _, task := range tasks {
+		indexed := *task
+		ir.index(&indexed)
+	}
+	ir.loaded = true
+	return nil
+}
+
+// index must be called with the write lock held.
+func (ir *IndexedTaskRepository) index(task *domain.Task) {
+	taskID := task.ID.Hex()
+	ir.remove(taskID)
+	ir.tasks[taskID] = task
+	ir.addPostings(taskID, task.Title, titleSearchWeight)
+	ir.addPostings(taskID, task.Description, descriptionSearchWeight)
+	for _, comment := range task.Comments {
+		ir.addPostings(taskID, comment, commentSearchWeight)
+	}
+}
+
+func (ir *IndexedTaskRepository) addPostings(taskID string, text string, weight float64) {
+	for _, term := range tokenize(text) {
+		if ir.postings[term] == nil {
+			ir.postings[term] = map[string]float64{}
+		}
+		ir.postings[term][taskID] += weight
+	}
+}
+
+// remove must be called with the write lock held.
+func (ir *IndexedTaskRepository) remove(taskID string) {
+	task, ok := ir.tasks[taskID]
+	if !ok {
+		return
+	}
+	terms := append(tokenize(task.Title), tokenize(task.Description)...)
+	for _, comment := range task.Comments {
+		terms = append(terms, tokenize(comment)...)
+	}
+	for _, term := range terms {
+		delete(ir.postings[term], taskID)
+		if len(ir.postings[term]) == 0 {
+			delete(ir.postings, term)
+		}
+	}
+	delete(ir.tasks, taskID)
+}
@@ -3,55 +3,56 @@ package repository
 import (
 	"context"
 	"errors"
-	"fmt"
 	"golang-clean-architecture/domain"
+	"log/slog"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"time"
 )
 
 type TaskRepository struct {
 	Database		 *mongo.Database
 	Collection 		 string
+	Logger			 *slog.Logger
 }
 
-func NewTaskRepository(db *mongo.Database, collection string)  domain.TaskRepository {
+func NewTaskRepository(db *mongo.Database, collection string, logger *slog.Logger)  domain.TaskRepository {
 	return &TaskRepository {
 		Database : db,
 		Collection: collection,
+		Logger : logger,
 	}
 }
 
-func (tr *TaskRepository) GetTasks() ([]*domain.Task, error) {
+func (tr *TaskRepository) GetTasks(ctx context.Context) ([]*domain.Task, error) {
 	var tasks []*domain.Task
 	collection := tr.Database.Collection(tr.Collection)
-	cur, err := collection.Find(context.TODO(), bson.D{{}})
+	cur, err := collection.Find(ctx, bson.D{{}})
 	if err != nil {
-		fmt.Println("first")
+		tr.Logger.ErrorContext(ctx, "error while querying tasks", "error", err)
 		return nil, errors.New("error while fetching tasks")
 	}
 	
-	for cur.Next(context.TODO()) {
+	for cur.Next(ctx) {
 		var task domain.Task
 		err := cur.Decode(&task)
 		if err != nil {
-			fmt.Println("second")
+			tr.Logger.ErrorContext(ctx, "error while decoding task", "error", err)
 			return nil, errors.New("error while fetching tasks")
 		}
 		tasks = append(tasks, &task)
 	}
 
-	fmt.Println(tasks)
-
 	if cur.Err() != nil {
-		fmt.Println("third")
+		tr.Logger.ErrorContext(ctx, "error while iterating tasks", "error", cur.Err())
 		return nil, errors.New("error while fetching tasks")
 	}
 
 	return tasks, nil
 }
 
-func (tr *TaskRepository) GetTask(taskID string) (domain.Task, error) {
+func (tr *TaskRepository) GetTask(ctx context.Context, taskID string) (domain.Task, error) {
 	
 	collection := tr.Database.Collection(tr.Collection)
 	processedID, err := primitive.ObjectIDFromHex(taskID)
@@ -60,7 +61,7 @@ func (tr *TaskRepository) GetTask(taskID string) (domain.Task, error) {
 	}
 	filter := bson.D{{Key : "_id", Value : processedID}}
 	var task domain.Task
-	err = collection.FindOne(context.TODO(), filter).Decode(&task)
+	err = collection.FindOne(ctx, filter).Decode(&task)
 	if err == mongo.ErrNoDocuments {
 		return domain.Task{}, errors.New("there is no task with the specified id")
 	}
@@ -71,17 +72,17 @@ func (tr *TaskRepository) GetTask(taskID string) (domain.Task, error) {
 	return task, nil
 }
 
-func (tr *TaskRepository) PostTask(task *domain.Task) error {
+func (tr *TaskRepository) PostTask(ctx context.Context, task *domain.Task) error {
 	collection := tr.Database.Collection(tr.Collection)
 	task.ID = primitive.NewObjectID()
-	_, err := collection.InsertOne(context.TODO(), task)
+	_, err := collection.InsertOne(ctx, task)
 	if err != nil {
 		return errors.New("error while trying to insert data")
 	}
 	return nil
 }
 
-func (tr *TaskRepository) DeleteTask(task_id string) error {
+func (tr *TaskRepository) DeleteTask(ctx context.Context, task_id string) error {
 	processedID, err := primitive.ObjectIDFromHex(task_id)
 	if err != nil {
 		return errors.New("invalid task id")
@@ -90,7 +91,7 @@ func (tr *TaskRepository) DeleteTask(task_id string) error {
 	filter := bson.D{{Key : "_id", Value : processedID}}
 
 	collection := tr.Database.Collection(tr.Collection)
-	deleteResult, err := collection.DeleteOne(context.TODO(), filter)
+	deleteResult, err := collection.DeleteOne(ctx, filter)
 	if deleteResult.DeletedCount == 0{
 		return errors.New("task with the specified id not found")
 	}
@@ -100,7 +101,7 @@ func (tr *TaskRepository) DeleteTask(task_id string) error {
 	return nil
 }
 
-func (tr *TaskRepository) UpdateTask(id string, modified *domain.Task) error {
+func (tr *TaskRepository) UpdateTask(ctx context.Context, id string, modified *domain.Task) error {
 
 	processedID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
@@ -133,7 +134,14 @@ func (tr *TaskRepository) UpdateTask(id string, modified *domain.Task) error {
 			}}})
 	}
 
-	updatedResult, err := collection.UpdateOne(context.TODO(), filter, update)
+	if modified.Comments != nil {
+		update = append(update, bson.E{
+			Key : "$set", Value : bson.D{{
+				Key : "comments", Value : modified.Comments,
+			}}})
+	}
+
+	updatedResult, err := collection.UpdateOne(ctx, filter, update)
 
 	if updatedResult.MatchedCount == 0 {
 		return errors.New("document with the specified id not found")
@@ -144,3 +152,120 @@ func (tr *TaskRepository) UpdateTask(id string, modified *domain.Task) error {
 
 	return nil
 }
+
+func (tr *TaskRepository) GetRecurringTasks(ctx context.Context) ([]*domain.Task, error) {
+	filter := bson.D{{Key : "recurrence", Value : bson.D{{Key : "$exists", Value : true}, {Key : "$ne", Value : ""}}}}
+	return tr.findTasks(ctx, filter)
+}
+
+func (tr *TaskRepository) GetSeriesTasks(ctx context.Context, seriesID string) ([]*domain.Task, error) {
+	filter, err := seriesFilter(seriesID)
+	if err != nil {
+		return nil, err
+	}
+	return tr.findTasks(ctx, filter)
+}
+
+func (tr *TaskRepository) UpdateSeriesRecurrence(ctx context.Context, seriesID string, rule string) error {
+	filter, err := seriesFilter(seriesID)
+	if err != nil {
+		return err
+	}
+
+	update := bson.D{{Key : "$set", Value : bson.D{{Key : "recurrence", Value : rule}}}}
+	if rule == "" {
+		update = bson.D{{Key : "$unset", Value : bson.D{{Key : "recurrence", Value : ""}}}}
+	}
+
+	collection := tr.Database.Collection(tr.Collection)
+	updateResult, err := collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return errors.New("internal server error")
+	}
+	if updateResult.MatchedCount == 0 {
+		return errors.New("task with the specified id not found")
+	}
+	return nil
+}
+
+// GetTasksDueBefore returns the unfinished tasks due by deadline that haven't
+// had the given reminder yet, so tasks that were dealt with don't come back
+// on every run.
+func (tr *TaskRepository) GetTasksDueBefore(ctx context.Context, deadline time.Time, reminder string) ([]*domain.Task, error) {
+	filter := bson.D{
+		{Key : "due_date", Value : bson.D{{Key : "$gt", Value : time.Time{}}, {Key : "$lte", Value : deadline}}},
+		{Key : "status", Value : bson.D{{Key : "$nin", Value : bson.A{"completed", "done"}}}},
+		{Key : "reminders_sent", Value : bson.D{{Key : "$ne", Value : reminder}}},
+	}
+	return tr.findTasks(ctx, filter)
+}
+
+// MarkReminderSent records that the reminder identified by key went out for a
+// task. It reports false when the reminder had already been recorded, which
+// keeps several instances of the scheduler from sending it twice.
+func (tr *TaskRepository) MarkReminderSent(ctx context.Context, taskID string, key string) (bool, error) {
+	processedID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return false, errors.New("invalid task id")
+	}
+
+	filter := bson.D{{Key : "_id", Value : processedID}, {Key : "reminders_sent", Value : bson.D{{Key : "$ne", Value : key}}}}
+	update := bson.D{{Key : "$addToSet", Value : bson.D{{Key : "reminders_sent", Value : key}}}}
+	collection := tr.Database.Collection(tr.Collection)
+	updateResult, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, errors.New("internal server error")
+	}
+	return updateResult.ModifiedCount == 1, nil
+}
+
+func (tr *TaskRepository) ClearReminderSent(ctx context.Context, taskID string, key string) error {
+	processedID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return errors.New("invalid task id")
+	}
+
+	filter := bson.D{{Key : "_id", Value : processedID}}
+	update := bson.D{{Key : "$pull", Value : bson.D{{Key : "reminders_sent", Value : key}}}}
+	collection := tr.Database.Collection(tr.Collection)
+	_, err = collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return errors.New("internal server error")
+	}
+	return nil
+}
+
+// seriesFilter matches every occurrence of a series. The first occurrence has
+// no series_id of its own; the series is identified by its id.
+func seriesFilter(seriesID string) (bson.D, error) {
+	processedID, err := primitive.ObjectIDFromHex(seriesID)
+	if err != nil {
+		return nil, errors.New("invalid task id")
+	}
+	return bson.D{{Key : "$or", Value : bson.A{
+		bson.D{{Key : "_id", Value : processedID}},
+		bson.D{{Key : "series_id", Value : seriesID}},
+	}}}, nil
+}
+
+func (tr *TaskRepository) findTasks(ctx context.Context, filter bson.D) ([]*domain.Task, error) {
+	collection := tr.Database.Collection(tr.Collection)
+	cur, err := collection.Find(ctx, filter)
+	if err != nil {
+		return nil, errors.New("error while fetching tasks")
+	}
+	defer cur.Close(ctx)
+
+	var tasks []*domain.Task
+	for cur.Next(ctx) {
+		var task domain.Task
+		if err := cur.Decode(&task); err != nil {
+			return nil, errors.New("error while fetching tasks")
+		}
+		tasks = append(tasks, &task)
+	}
+	if cur.Err() != nil {
+		return nil, errors.New("error while fetching tasks")
+	}
+	return tasks, nil
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"golang-clean-architecture/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type TaskSearchRepository struct {
+	Database   *mongo.Database
+	Collection string
+}
+
+func NewTaskSearchRepository(db *mongo.Database, collection string) domain.TaskSearcher {
+	return &TaskSearchRepository{
+		Database:   db,
+		Collection: collection,
+	}
+}
+
+// EnsureTaskTextIndex creates the weighted text index that SearchTasks relies
+// on. Creating an index that already exists is a no-op in MongoDB; a text
+// index created before comments were searchable is replaced, since a
+// collection can only have one.
+func EnsureTaskTextIndex(db *mongo.Database, collection string) error {
+	index := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "title", Value: "text"},
+			{Key: "description", Value: "text"},
+			{Key: "comments", Value: "text"},
+		},
+		Options: options.Index().SetName("task_text").SetWeights(bson.D{
+			{Key: "title", Value: titleSearchWeight},
+			{Key: "description", Value: descriptionSearchWeight},
+			{Key: "comments", Value: commentSearchWeight},
+		}),
+	}
+	indexes := db.Collection(collection).Indexes()
+	_, err := indexes.CreateOne(context.TODO(), index)
+	if isIndexConflict(err) {
+		_, err = indexes.DropOne(context.TODO(), "task_text")
+		if err == nil {
+			_, err = indexes.CreateOne(context.TODO(), index)
+		}
+	}
+	if err != nil {
+		return errors.New("error while creating task text index")
+	}
+	return nil
+}
+
+// isIndexConflict reports whether an index of the same name or on the same
+// keys already exists with other options.
+func isIndexConflict(err error) bool {
+	var commandError mongo.CommandError
+	if !errors.As(err, &commandError) {
+		return false
+	}
+	return commandError.Code == 85 || commandError.Code == 86
+}
+
+func (ts *TaskSearchRepository) SearchTasks(ctx context.Context, query string, limit int) ([]*domain.TaskSearchResult, error) {
+	collection := ts.Database.Collection(ts.Collection)
+	filter := bson.D{{Key: "$text", Value: bson.D{{Key: "$search", Value: query}}}}
+	score := bson.D{{Key: "score", Value: bson.D{{Key: "$meta", Value: "textScore"}}}}
+	findOptions := options.Find().SetProjection(score).SetSort(score).SetLimit(int64(limit))
+
+	cur, err := collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, errors.New("error while searching tasks")
+	}
+	defer cur.Close(ctx)
+
+	terms := tokenize(query)
+	results := []*domain.TaskSearchResult{}
+	for cur.Next(ctx) {
+		var scored struct {
+			domain.Task `bson:",inline"`
+			Score       float64 `bson:"score"`
+		}
+		if err := cur.Decode(&scored); err != nil {
+			return nil, errors.New("error while searching tasks")
+		}
+		task := scored.Task
+		results = append(results, &domain.TaskSearchResult{
+			Task:       &task,
+			Score:      scored.Score,
+			Highlights: highlightTask(&task, terms),
+		})
+	}
+	if cur.Err() != nil {
+		return nil, errors.New("error while searching tasks")
+	}
+
+	return results, nil
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"golang-clean-architecture/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// OneTimeTokenRepository stores hashed single-use tokens such as password
+// reset links. Only the hash is persisted, so a leaked collection can't be
+// used to take over accounts.
+type OneTimeTokenRepository struct {
+	Database   *mongo.Database
+	Collection string
+}
+
+func NewOneTimeTokenRepository(db *mongo.Database, collection string) domain.OneTimeTokenRepository {
+	return &OneTimeTokenRepository{
+		Database:   db,
+		Collection: collection,
+	}
+}
+
+// EnsureOneTimeTokenIndexes makes token hashes unique and lets MongoDB drop
+// tokens once they expire.
+func EnsureOneTimeTokenIndexes(db *mongo.Database, collection string) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}
+	_, err := db.Collection(collection).Indexes().CreateMany(context.TODO(), indexes)
+	if err != nil {
+		return errors.New("error while creating token indexes")
+	}
+	return nil
+}
+
+func (tr *OneTimeTokenRepository) CreateToken(ctx context.Context, token *domain.OneTimeToken) error {
+	collection := tr.Database.Collection(tr.Collection)
+	token.ID = primitive.NewObjectID()
+	_, err := collection.InsertOne(ctx, token)
+	if err != nil {
+		return errors.New("error while trying to insert data")
+	}
+	return nil
+}
+
+// GetToken returns an unused, unexpired token without spending it, for
+// checks that have to pass before the token is consumed.
+func (tr *OneTimeTokenRepository) GetToken(ctx context.Context, purpose string, tokenHash string, now time.Time) (domain.OneTimeToken, error) {
+	collection := tr.Database.Collection(tr.Collection)
+	var token domain.OneTimeToken
+	err := collection.FindOne(ctx, usableTokenFilter(purpose, tokenHash, now)).Decode(&token)
+	if err == mongo.ErrNoDocuments {
+		return domain.OneTimeToken{}, errors.New("invalid or expired token")
+	}
+	if err != nil {
+		return domain.OneTimeToken{}, errors.New("internal server error")
+	}
+	return token, nil
+}
+
+// ConsumeToken atomically marks an unused, unexpired token as used and
+// returns it, so the same token can never be redeemed twice.
+func (tr *OneTimeTokenRepository) ConsumeToken(ctx context.Context, purpose string, tokenHash string, now time.Time) (domain.OneTimeToken, error) {
+	collection := tr.Database.Collection(tr.Collection)
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "used_at", Value: now}}}}
+
+	var token domain.OneTimeToken
+	err := collection.FindOneAndUpdate(ctx, usableTokenFilter(purpose, tokenHash, now), update).Decode(&token)
+	if err == mongo.ErrNoDocuments {
+		return domain.OneTimeToken{}, errors.New("invalid or expired token")
+	}
+	if err != nil {
+		return domain.OneTimeToken{}, errors.New("internal server error")
+	}
+	return token, nil
+}
+
+func usableTokenFilter(purpose string, tokenHash string, now time.Time) bson.D {
+	return bson.D{
+		{Key: "purpose", Value: purpose},
+		{Key: "token_hash", Value: tokenHash},
+		{Key: "used_at", Value: bson.D{{Key: "$exists", Value: false}}},
+		{Key: "expires_at", Value: bson.D{{Key: "$gt", Value: now}}},
+	}
+}
+
+// RevokeTokens deletes every outstanding token of the given purpose for a user.
+func (tr *OneTimeTokenRepository) RevokeTokens(ctx context.Context, userID string, purpose string) error {
+	collection := tr.Database.Collection(tr.Collection)
+	filter := bson.D{
+		{Key: "user_id", Value: userID},
+		{Key: "purpose", Value: purpose},
+	}
+	_, err := collection.DeleteMany(ctx, filter)
+	if err != nil {
+		return errors.New("internal server error")
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"golang-clean-architecture/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// TwoFactorRepository keeps the TOTP secret and recovery codes on the user
+// document, outside of domain.User so that they never leave the use cases.
+type TwoFactorRepository struct {
+	Database   *mongo.Database
+	Collection string
+}
+
+func NewTwoFactorRepository(db *mongo.Database, collection string) domain.TwoFactorRepository {
+	return &TwoFactorRepository{
+		Database:   db,
+		Collection: collection,
+	}
+}
+
+func (tr *TwoFactorRepository) GetTwoFactor(ctx context.Context, userID string) (domain.TwoFactor, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return domain.TwoFactor{}, errors.New("invalid user ID")
+	}
+	var twoFactor domain.TwoFactor
+	collection := tr.Database.Collection(tr.Collection)
+	err = collection.FindOne(ctx, bson.D{{Key: "_id", Value: objectID}}).Decode(&twoFactor)
+	if err == mongo.ErrNoDocuments {
+		return domain.TwoFactor{}, errors.New("no user with the specified id found")
+	}
+	if err != nil {
+		return domain.TwoFactor{}, errors.New("internal server error")
+	}
+	return twoFactor, nil
+}
+
+// SetPendingSecret stores a secret that only takes effect once a code
+// generated from it is confirmed. Re-enrolling replaces it.
+func (tr *TwoFactorRepository) SetPendingSecret(ctx context.Context, userID string, secret string) error {
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "two_factor_pending_secret", Value: secret}}}}
+	return tr.update(ctx, userID, bson.D{}, update)
+}
+
+// EnableTwoFactor promotes the secret, replaces any earlier recovery codes
+// and turns 2FA on.
+func (tr *TwoFactorRepository) EnableTwoFactor(ctx context.Context, userID string, secret string, recoveryCodeHashes []string) error {
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "two_factor_enabled", Value: true},
+			{Key: "two_factor_secret", Value: secret},
+			{Key: "recovery_codes", Value: recoveryCodeHashes},
+		}},
+		{Key: "$unset", Value: bson.D{
+			{Key: "two_factor_pending_secret", Value: ""},
+			{Key: "two_factor_last_step", Value: ""},
+		}},
+	}
+	return tr.update(ctx, userID, bson.D{}, update)
+}
+
+// UseTimeStep records step as used and reports false if it or a later step
+// was used already, so that a TOTP code can't be replayed.
+func (tr *TwoFactorRepository) UseTimeStep(ctx context.Context, userID string, step int64) (bool, error) {
+	filter := bson.D{{Key: "$or", Value: bson.A{
+		bson.D{{Key: "two_factor_last_step", Value: bson.D{{Key: "$exists", Value: false}}}},
+		bson.D{{Key: "two_factor_last_step", Value: bson.D{{Key: "$lt", Value: step}}}},
+	}}}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "two_factor_last_step", Value: step}}}}
+	return tr.updateIfMatched(ctx, userID, filter, update)
+}
+
+// UseRecoveryCode removes the code with the given hash and reports whether
+// it was still there.
+func (tr *TwoFactorRepository) UseRecoveryCode(ctx context.Context, userID string, codeHash string) (bool, error) {
+	filter := bson.D{{Key: "recovery_codes", Value: codeHash}}
+	update := bson.D{{Key: "$pull", Value: bson.D{{Key: "recovery_codes", Value: codeHash}}}}
+	return tr.updateIfMatched(ctx, userID, filter, update)
+}
+
+func (tr *TwoFactorRepository) update(ctx context.Context, userID string, filter bson.D, update bson.D) error {
+	matched, err := tr.updateIfMatched(ctx, userID, filter, update)
+	if err != nil {
+		return err
+	}
+	if !matched {
+		return errors.New("no user with the specified id found")
+	}
+	return nil
+}
+
+func (tr *TwoFactorRepository) updateIfMatched(ctx context.Context, userID string, filter bson.D, update bson.D) (bool, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return false, errors.New("invalid user ID")
+	}
+	filter = append(bson.D{{Key: "_id", Value: objectID}}, filter...)
+	collection := tr.Database.Collection(tr.Collection)
+	updateResult, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, errors.New("internal server error")
+	}
+	return updateResult.MatchedCount == 1, nil
+}
@@ -4,11 +4,19 @@ import (
 	"context"
 	"errors"
 	"golang-clean-architecture/domain"
+	"log/slog"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
 )
 
+// emailCollation compares emails case-insensitively. Emails are lower-cased
+// before they are stored, but accounts created before that keep whatever
+// case they were registered with.
+var emailCollation = &options.Collation{Locale: "en", Strength: 2}
+
 type UserRepository struct  {
 	Database 		*mongo.Database
 	Collection 		string
@@ -21,27 +29,76 @@ func NewUserRepository(db *mongo.Database, collection string) domain.UserReposit
 	}
 }
 
-func (ur *UserRepository) Register(newUser *domain.User) error {
+// EnsureUserIndexes makes emails unique regardless of case. Lookups by
+// email use the same collation, so that they can use the index.
+//
+// Accounts registered before emails were lower-cased can share an email that
+// differs only by case. The index can't be created until they are merged, so
+// they are logged and the server starts without it in the meantime.
+func EnsureUserIndexes(db *mongo.Database, collection string, logger *slog.Logger) error {
+	index := mongo.IndexModel{
+		Keys:    bson.D{{Key: "email", Value: 1}},
+		Options: options.Index().SetUnique(true).SetCollation(emailCollation),
+	}
+	_, err := db.Collection(collection).Indexes().CreateOne(context.TODO(), index)
+	if mongo.IsDuplicateKeyError(err) {
+		return reportDuplicateEmails(db.Collection(collection), logger)
+	}
+	if err != nil {
+		return errors.New("error while creating user indexes")
+	}
+	return nil
+}
+
+func reportDuplicateEmails(collection *mongo.Collection, logger *slog.Logger) error {
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.D{{Key: "$toLower", Value: "$email"}}},
+			{Key: "user_ids", Value: bson.D{{Key: "$push", Value: "$_id"}}},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+		{{Key: "$match", Value: bson.D{{Key: "count", Value: bson.D{{Key: "$gt", Value: 1}}}}}},
+	}
+	cur, err := collection.Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		return errors.New("error while looking for duplicate emails")
+	}
+	var duplicates []struct {
+		Email   string               `bson:"_id"`
+		UserIDs []primitive.ObjectID `bson:"user_ids"`
+	}
+	if err := cur.All(context.TODO(), &duplicates); err != nil {
+		return errors.New("error while looking for duplicate emails")
+	}
+
+	for _, duplicate := range duplicates {
+		logger.Warn("accounts share an email that differs only by case", "email", duplicate.Email, "user_ids", duplicate.UserIDs)
+	}
+	logger.Warn("emails aren't unique regardless of case until the accounts above are merged or renamed; the index is created on the next start after that", "duplicates", len(duplicates))
+	return nil
+}
+
+func (ur *UserRepository) Register(ctx context.Context, newUser *domain.User) error {
 	collection := ur.Database.Collection(ur.Collection)
 	newUser.ID = primitive.NewObjectID()
-	_, err := collection.InsertOne(context.TODO(), newUser)
+	_, err := collection.InsertOne(ctx, newUser)
 	return err
 }
 
-func (ur *UserRepository) VerifyFirst(newUser *domain.User) error {
+func (ur *UserRepository) VerifyFirst(ctx context.Context, newUser *domain.User) error {
 	collection := ur.Database.Collection(ur.Collection)
-	cur, err := collection.Find(context.TODO(), bson.D{{}})
-	if cur.Next(context.TODO()) {return errors.New("a user is found on db")}
+	cur, err := collection.Find(ctx, bson.D{{}})
+	if cur.Next(ctx) {return errors.New("a user is found on db")}
 	if err != nil {
 		return errors.New("internal server error")
 	}
 	return nil
 }
 
-func (ur *UserRepository) UserExists(newUser *domain.User) error {
+func (ur *UserRepository) UserExists(ctx context.Context, newUser *domain.User) error {
 	collection := ur.Database.Collection(ur.Collection)
 	var existingUser domain.User
-	err := collection.FindOne(context.TODO(), bson.D{{Key : "email", Value : newUser.Email}}).Decode(&existingUser)
+	err := collection.FindOne(ctx, bson.D{{Key : "email", Value : newUser.Email}}, options.FindOne().SetCollation(emailCollation)).Decode(&existingUser)
 	if err == mongo.ErrNoDocuments {
 		return nil
 	}
@@ -51,19 +108,37 @@ func (ur *UserRepository) UserExists(newUser *domain.User) error {
 	return nil
 }
 
-func (ur *UserRepository) GetUserByEmail(email string) domain.User {
+func (ur *UserRepository) GetUserByEmail(ctx context.Context, email string) domain.User {
 	collection := ur.Database.Collection(ur.Collection)
 	filter := bson.D{{Key : "email", Value : email}}
 
 	var existingUser domain.User
-	err := collection.FindOne(context.TODO(), filter).Decode(&existingUser)
+	err := collection.FindOne(ctx, filter, options.FindOne().SetCollation(emailCollation)).Decode(&existingUser)
 	if err != nil {
 		return domain.User{}
 	}
 	return existingUser
 }
 
-func (ur *UserRepository) PromoteUser(userID string) error {
+func (ur *UserRepository) GetUserByID(ctx context.Context, userID string) (domain.User, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return domain.User{}, errors.New("invalid user ID")
+	}
+	collection := ur.Database.Collection(ur.Collection)
+
+	var existingUser domain.User
+	err = collection.FindOne(ctx, bson.D{{Key : "_id", Value : objectID}}).Decode(&existingUser)
+	if err == mongo.ErrNoDocuments {
+		return domain.User{}, errors.New("no user with the specified id found")
+	}
+	if err != nil {
+		return domain.User{}, errors.New("internal server error")
+	}
+	return existingUser, nil
+}
+
+func (ur *UserRepository) PromoteUser(ctx context.Context, userID string) error {
 	objectID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
 		return errors.New("invalid user ID")
@@ -71,7 +146,7 @@ func (ur *UserRepository) PromoteUser(userID string) error {
 	filter := bson.D{{Key : "_id", Value : objectID}}
 	update := bson.D{{Key : "$set", Value : bson.D{{Key : "role", Value : "admin"}}}}
 	collection := ur.Database.Collection(ur.Collection)
-	updateResult, err := collection.UpdateOne(context.TODO(), filter, update)
+	updateResult, err := collection.UpdateOne(ctx, filter, update)
 	
 	if updateResult.MatchedCount == 0 {
 		return errors.New("no user with the specified id found")
@@ -84,4 +159,93 @@ func (ur *UserRepository) PromoteUser(userID string) error {
 		return errors.New("internal server error")
 	}
 	return nil
+}
+
+// UpdatePassword stores a new password hash. Tokens issued before
+// sessionsRevokedAt stop being accepted.
+func (ur *UserRepository) UpdatePassword(ctx context.Context, userID string, hashedPassword string, sessionsRevokedAt time.Time) error {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return errors.New("invalid user ID")
+	}
+	filter := bson.D{{Key : "_id", Value : objectID}}
+	update := bson.D{{Key : "$set", Value : bson.D{
+		{Key : "password", Value : hashedPassword},
+		{Key : "sessions_revoked_at", Value : sessionsRevokedAt},
+	}}}
+	collection := ur.Database.Collection(ur.Collection)
+	updateResult, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return errors.New("internal server error")
+	}
+	if updateResult.MatchedCount == 0 {
+		return errors.New("no user with the specified id found")
+	}
+	return nil
+}
+
+// UpdatePasswordHash replaces the stored hash of an unchanged password, such
+// as when it is upgraded to newer hashing parameters. Sessions are kept.
+func (ur *UserRepository) UpdatePasswordHash(ctx context.Context, userID string, hashedPassword string) error {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return errors.New("invalid user ID")
+	}
+	filter := bson.D{{Key : "_id", Value : objectID}}
+	update := bson.D{{Key : "$set", Value : bson.D{{Key : "password", Value : hashedPassword}}}}
+	collection := ur.Database.Collection(ur.Collection)
+	updateResult, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return errors.New("internal server error")
+	}
+	if updateResult.MatchedCount == 0 {
+		return errors.New("no user with the specified id found")
+	}
+	return nil
+}
+
+func (ur *UserRepository) MarkVerified(ctx context.Context, userID string) error {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return errors.New("invalid user ID")
+	}
+	filter := bson.D{{Key : "_id", Value : objectID}}
+	update := bson.D{{Key : "$set", Value : bson.D{{Key : "verified", Value : true}}}}
+	collection := ur.Database.Collection(ur.Collection)
+	updateResult, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return errors.New("internal server error")
+	}
+	if updateResult.MatchedCount == 0 {
+		return errors.New("no user with the specified id found")
+	}
+	return nil
+}
+
+// UpdateProfile sets the fields of update that are not nil.
+func (ur *UserRepository) UpdateProfile(ctx context.Context, userID string, update domain.ProfileUpdate) error {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return errors.New("invalid user ID")
+	}
+	fields := bson.D{}
+	if update.DisplayName != nil {
+		fields = append(fields, bson.E{Key : "display_name", Value : *update.DisplayName})
+	}
+	if update.Timezone != nil {
+		fields = append(fields, bson.E{Key : "timezone", Value : *update.Timezone})
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	collection := ur.Database.Collection(ur.Collection)
+	updateResult, err := collection.UpdateOne(ctx, bson.D{{Key : "_id", Value : objectID}}, bson.D{{Key : "$set", Value : fields}})
+	if err != nil {
+		return errors.New("internal server error")
+	}
+	if updateResult.MatchedCount == 0 {
+		return errors.New("no user with the specified id found")
+	}
+	return nil
 }
\ No newline at end of file
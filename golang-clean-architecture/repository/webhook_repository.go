@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"golang-clean-architecture/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type WebhookRepository struct {
+	Database                *mongo.Database
+	SubscriptionsCollection string
+	DeliveriesCollection    string
+}
+
+func NewWebhookRepository(db *mongo.Database, subscriptions string, deliveries string) domain.WebhookRepository {
+	return &WebhookRepository{
+		Database:                db,
+		SubscriptionsCollection: subscriptions,
+		DeliveriesCollection:    deliveries,
+	}
+}
+
+func (wr *WebhookRepository) CreateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	collection := wr.Database.Collection(wr.SubscriptionsCollection)
+	subscription.ID = primitive.NewObjectID()
+	_, err := collection.InsertOne(ctx, subscription)
+	if err != nil {
+		return errors.New("error while trying to insert data")
+	}
+	return nil
+}
+
+func (wr *WebhookRepository) GetSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	return wr.findSubscriptions(ctx, bson.D{})
+}
+
+func (wr *WebhookRepository) GetSubscriptionsForEvent(ctx context.Context, eventType string) ([]*domain.WebhookSubscription, error) {
+	return wr.findSubscriptions(ctx, bson.D{{Key: "event_types", Value: eventType}})
+}
+
+func (wr *WebhookRepository) GetSubscription(ctx context.Context, subscriptionID string) (domain.WebhookSubscription, error) {
+	processedID, err := primitive.ObjectIDFromHex(subscriptionID)
+	if err != nil {
+		return domain.WebhookSubscription{}, errors.New("invalid webhook id")
+	}
+
+	var subscription domain.WebhookSubscription
+	collection := wr.Database.Collection(wr.SubscriptionsCollection)
+	err = collection.FindOne(ctx, bson.D{{Key: "_id", Value: processedID}}).Decode(&subscription)
+	if err == mongo.ErrNoDocuments {
+		return domain.WebhookSubscription{}, errors.New("webhook with the specified id not found")
+	}
+	if err != nil {
+		return domain.WebhookSubscription{}, errors.New("internal server error")
+	}
+	return subscription, nil
+}
+
+func (wr *WebhookRepository) DeleteSubscription(ctx context.Context, subscriptionID string) error {
+	processedID, err := primitive.ObjectIDFromHex(subscriptionID)
+	if err != nil {
+		return errors.New("invalid webhook id")
+	}
+
+	collection := wr.Database.Collection(wr.SubscriptionsCollection)
+	deleteResult, err := collection.DeleteOne(ctx, bson.D{{Key: "_id", Value: processedID}})
+	if err != nil {
+		return errors.New("internal server error")
+	}
+	if deleteResult.DeletedCount == 0 {
+		return errors.New("webhook with the specified id not found")
+	}
+	return nil
+}
+
+func (wr *WebhookRepository) CreateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	collection := wr.Database.Collection(wr.DeliveriesCollection)
+	delivery.ID = primitive.NewObjectID()
+	_, err := collection.InsertOne(ctx, delivery)
+	if err != nil {
+		return errors.New("error while trying to insert data")
+	}
+	return nil
+}
+
+// ClaimPendingDelivery atomically picks one delivery that is due and pushes
+// its next attempt out by the lease, so concurrent workers never pick the
+// same delivery. It returns nil when nothing is due.
+func (wr *WebhookRepository) ClaimPendingDelivery(ctx context.Context, now time.Time, lease time.Duration) (*domain.WebhookDelivery, error) {
+	filter := bson.D{
+		{Key: "status", Value: "pending"},
+		{Key: "next_attempt_at", Value: bson.D{{Key: "$lte", Value: now}}},
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "next_attempt_at", Value: now.Add(lease)}}}}
+	findOptions := options.FindOneAndUpdate().SetSort(bson.D{{Key: "next_attempt_at", Value: 1}})
+
+	var delivery domain.WebhookDelivery
+	collection := wr.Database.Collection(wr.DeliveriesCollection)
+	err := collection.FindOneAndUpdate(ctx, filter, update, findOptions).Decode(&delivery)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.New("internal server error")
+	}
+	return &delivery, nil
+}
+
+func (wr *WebhookRepository) RecordAttempt(ctx context.Context, deliveryID string, attempt domain.WebhookAttempt, status string, nextAttemptAt time.Time) error {
+	processedID, err := primitive.ObjectIDFromHex(deliveryID)
+	if err != nil {
+		return errors.New("invalid delivery id")
+	}
+
+	update := bson.D{
+		{Key: "$push", Value: bson.D{{Key: "attempts", Value: attempt}}},
+		{Key: "$set", Value: bson.D{{Key: "status", Value: status}, {Key: "next_attempt_at", Value: nextAttemptAt}}},
+	}
+	collection := wr.Database.Collection(wr.DeliveriesCollection)
+	_, err = collection.UpdateOne(ctx, bson.D{{Key: "_id", Value: processedID}}, update)
+	if err != nil {
+		return errors.New("internal server error")
+	}
+	return nil
+}
+
+func (wr *WebhookRepository) GetDeliveries(ctx context.Context, subscriptionID string) ([]*domain.WebhookDelivery, error) {
+	collection := wr.Database.Collection(wr.DeliveriesCollection)
+	findOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(100)
+	cur, err := collection.Find(ctx, bson.D{{Key: "subscription_id", Value: subscriptionID}}, findOptions)
+	if err != nil {
+		return nil, errors.New("error while fetching deliveries")
+	}
+	defer cur.Close(ctx)
+
+	deliveries := []*domain.WebhookDelivery{}
+	if err := cur.All(ctx, &deliveries); err != nil {
+		return nil, errors.New("error while fetching deliveries")
+	}
+	return deliveries, nil
+}
+
+func (wr *WebhookRepository) findSubscriptions(ctx context.Context, filter bson.D) ([]*domain.WebhookSubscription, error) {
+	collection := wr.Database.Collection(wr.SubscriptionsCollection)
+	cur, err := collection.Find(ctx, filter)
+	if err != nil {
+		return nil, errors.New("error while fetching webhooks")
+	}
+	defer cur.Close(ctx)
+
+	subscriptions := []*domain.WebhookSubscription{}
+	if err := cur.All(ctx, &subscriptions); err != nil {
+		return nil, errors.New("error while fetching webhooks")
+	}
+	return subscriptions, nil
+}
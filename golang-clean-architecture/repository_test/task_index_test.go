@@ -0,0 +1,93 @@
+package repository_test
+
+import (
+	"context"
+	"golang-clean-architecture/domain"
+	"golang-clean-architecture/domain/mocks"
+	"golang-clean-architecture/repository"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TaskIndexTestSuite struct {
+	suite.Suite
+	mockRepo *mocks.TaskRepository
+	repo     *repository.IndexedTaskRepository
+	tasks    []*domain.Task
+}
+
+func (suite *TaskIndexTestSuite) SetupTest() {
+	suite.mockRepo = new(mocks.TaskRepository)
+	suite.repo = repository.NewIndexedTaskRepository(suite.mockRepo)
+	suite.tasks = []*domain.Task{
+		{ID: primitive.NewObjectID(), Title: "Deploy the release", Description: "roll out version two to production", Status: "pending"},
+		{ID: primitive.NewObjectID(), Title: "Write release notes", Description: "summarise what changed since we deployed last", Status: "pending"},
+		{ID: primitive.NewObjectID(), Title: "Order lunch", Description: "pizza for the team", Status: "done", Comments: []string{"ask about allergies", "Sam wants the vegetarian one"}},
+	}
+	suite.mockRepo.On("GetTasks", mock.Anything).Return(suite.tasks, nil)
+}
+
+func (suite *TaskIndexTestSuite) TestSearchRanksTitleMatchesFirst() {
+	results, err := suite.repo.SearchTasks(context.Background(), "deploying", 10)
+	suite.NoError(err)
+	suite.Len(results, 2)
+	suite.Equal("Deploy the release", results[0].Task.Title)
+	suite.Equal("<mark>Deploy</mark> the release", results[0].Highlights["title"])
+	suite.Equal("summarise what changed since we <mark>deployed</mark> last", results[1].Highlights["description"])
+	suite.Greater(results[0].Score, results[1].Score)
+}
+
+func (suite *TaskIndexTestSuite) TestSearchCoversComments() {
+	results, err := suite.repo.SearchTasks(context.Background(), "vegetarian", 10)
+	suite.NoError(err)
+	suite.Len(results, 1)
+	suite.Equal("Order lunch", results[0].Task.Title)
+	suite.Equal(map[string]string{"comments": "Sam wants the <mark>vegetarian</mark> one"}, results[0].Highlights)
+}
+
+func (suite *TaskIndexTestSuite) TestSearchHonoursLimit() {
+	results, err := suite.repo.SearchTasks(context.Background(), "release", 1)
+	suite.NoError(err)
+	suite.Len(results, 1)
+}
+
+func (suite *TaskIndexTestSuite) TestSearchNoMatches() {
+	results, err := suite.repo.SearchTasks(context.Background(), "kubernetes", 10)
+	suite.NoError(err)
+	suite.Empty(results)
+}
+
+func (suite *TaskIndexTestSuite) TestIndexFollowsWrites() {
+	_, err := suite.repo.SearchTasks(context.Background(), "lunch", 10)
+	suite.NoError(err)
+
+	newTask := &domain.Task{ID: primitive.NewObjectID(), Title: "Book lunch venue", Description: "team offsite", Status: "pending"}
+	suite.mockRepo.On("PostTask", mock.Anything, newTask).Return(nil)
+	suite.NoError(suite.repo.PostTask(context.Background(), newTask))
+
+	lunchID := suite.tasks[2].ID.Hex()
+	suite.mockRepo.On("DeleteTask", mock.Anything, lunchID).Return(nil)
+	suite.NoError(suite.repo.DeleteTask(context.Background(), lunchID))
+
+	results, err := suite.repo.SearchTasks(context.Background(), "lunch", 10)
+	suite.NoError(err)
+	suite.Len(results, 1)
+	suite.Equal("Book lunch venue", results[0].Task.Title)
+
+	updated := domain.Task{ID: newTask.ID, Title: "Book dinner venue", Description: "team offsite", Status: "pending"}
+	suite.mockRepo.On("UpdateTask", mock.Anything, newTask.ID.Hex(), mock.Anything).Return(nil)
+	suite.mockRepo.On("GetTask", mock.Anything, newTask.ID.Hex()).Return(updated, nil)
+	suite.NoError(suite.repo.UpdateTask(context.Background(), newTask.ID.Hex(), &domain.Task{Title: "Book dinner venue"}))
+
+	results, err = suite.repo.SearchTasks(context.Background(), "lunch", 10)
+	suite.NoError(err)
+	suite.Empty(results)
+	suite.mockRepo.AssertNumberOfCalls(suite.T(), "GetTasks", 1)
+}
+
+func TestTaskIndexTestSuite(t *testing.T) {
+	suite.Run(t, new(TaskIndexTestSuite))
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 	"golang-clean-architecture/domain"
 	"golang-clean-architecture/repository"
+	"log/slog"
 	"testing"
 	"time"
 
@@ -34,7 +35,7 @@ func (suite	*TaskTestSuite)	SetupSuite() {
 
 	db := client.Database("test")
 	collection := db.Collection("tasks")
-	repo := repository.NewTaskRepository(db, "tasks")
+	repo := repository.NewTaskRepository(db, "tasks", slog.Default())
 	suite.client = client
 	suite.db = db
 	suite.collection = collection
@@ -62,7 +63,7 @@ func (suite *TaskTestSuite) TestPostTask() {
         Status:      "pending",
     }
 
-    err := suite.repo.PostTask(task)
+    err := suite.repo.PostTask(context.Background(), task)
     suite.NoError(err, "no error while inserting a task")
 
     var insertedTask domain.Task
@@ -82,9 +83,9 @@ func (suite *TaskTestSuite) TestDeleteTask_Positive() {
         Status:      "pending",
     }
 
-	err := suite.repo.PostTask(task)
+	err := suite.repo.PostTask(context.Background(), task)
     suite.NoError(err, "no error while inserting a task")
-	err = suite.repo.DeleteTask(task.ID.Hex())
+	err = suite.repo.DeleteTask(context.Background(), task.ID.Hex())
 	suite.NoError(err, "no error while deleting a task")
 }
 
@@ -97,9 +98,9 @@ func (suite *TaskTestSuite) TestDeleteTask_Negative() {
         Status:      "pending",
     }
 
-	err := suite.repo.PostTask(task)
+	err := suite.repo.PostTask(context.Background(), task)
     suite.NoError(err, "no error while inserting a task")
-	err = suite.repo.DeleteTask(primitive.NewObjectID().Hex())
+	err = suite.repo.DeleteTask(context.Background(), primitive.NewObjectID().Hex())
 	suite.Error(err, "error while deleting a task")
 }
 
@@ -112,7 +113,7 @@ func (suite *TaskTestSuite) TestUpdateTask_Positive() {
         Status:      "pending",
     }
 
-	err := suite.repo.PostTask(task)
+	err := suite.repo.PostTask(context.Background(), task)
     suite.NoError(err, "no error while inserting a task")
 
 	modifiedTask := &domain.Task{
@@ -122,7 +123,7 @@ func (suite *TaskTestSuite) TestUpdateTask_Positive() {
         Status:      "pending",
     }
 
-	err = suite.repo.UpdateTask(task.ID.Hex(), modifiedTask)
+	err = suite.repo.UpdateTask(context.Background(), task.ID.Hex(), modifiedTask)
 	suite.NoError(err, "no error while updating a task")
 }
 
@@ -135,7 +136,7 @@ func (suite *TaskTestSuite) TestUpdateTask_Negative() {
         Status:      "pending",
     }
 
-	err := suite.repo.PostTask(task)
+	err := suite.repo.PostTask(context.Background(), task)
     suite.NoError(err, "no error while inserting a task")
 
 	modifiedTask := &domain.Task{
@@ -145,7 +146,7 @@ func (suite *TaskTestSuite) TestUpdateTask_Negative() {
         Status:      "pending",
     }
 
-	err = suite.repo.UpdateTask(primitive.NewObjectID().Hex(), modifiedTask)
+	err = suite.repo.UpdateTask(context.Background(), primitive.NewObjectID().Hex(), modifiedTask)
 	suite.Error(err, "error while updating a task")
 }
 
@@ -158,9 +159,9 @@ func (suite *TaskTestSuite) TestGetTask_Positive() {
         Status:      "pending",
     }
 
-    err := suite.repo.PostTask(task)
+    err := suite.repo.PostTask(context.Background(), task)
     suite.NoError(err, "no error while inserting a task")
-	_, err = suite.repo.GetTask(task.ID.Hex())
+	_, err = suite.repo.GetTask(context.Background(), task.ID.Hex())
     suite.NoError(err, "no error retrieving a task")
 }
 
@@ -173,9 +174,9 @@ func (suite *TaskTestSuite) TestGetTask_Negative() {
         Status:      "pending",
     }
 
-    err := suite.repo.PostTask(task)
+    err := suite.repo.PostTask(context.Background(), task)
     suite.NoError(err, "no error while inserting a task")
-	_, err = suite.repo.GetTask(primitive.NewObjectID().Hex())
+	_, err = suite.repo.GetTask(context.Background(), primitive.NewObjectID().Hex())
     suite.Error(err, "error retrieving a task")
 }
 
@@ -188,12 +189,35 @@ func (suite *TaskTestSuite) TestGetTasks() {
         Status:      "pending",
     }
 
-    err := suite.repo.PostTask(task)
+    err := suite.repo.PostTask(context.Background(), task)
     suite.NoError(err, "no error while inserting a task")
-	_, err = suite.repo.GetTasks()
+	_, err = suite.repo.GetTasks(context.Background())
     suite.NoError(err, "no error retrieving a task")
 }
 
+func (suite *TaskTestSuite) TestGetTasksDueBefore_SkipsTasksAlreadyReminded() {
+	now := time.Now()
+	reported := &domain.Task{Title: "reported overdue", DueDate: now.Add(-time.Hour), Status: "overdue"}
+	unreported := &domain.Task{Title: "not reported yet", DueDate: now.Add(-time.Hour), Status: "pending"}
+	later := &domain.Task{Title: "due next week", DueDate: now.Add(7 * 24 * time.Hour), Status: "pending"}
+	for _, task := range []*domain.Task{reported, unreported, later} {
+		suite.NoError(suite.repo.PostTask(context.Background(), task))
+	}
+	claimed, err := suite.repo.MarkReminderSent(context.Background(), reported.ID.Hex(), "overdue")
+	suite.NoError(err)
+	suite.True(claimed)
+
+	tasks, err := suite.repo.GetTasksDueBefore(context.Background(), now, "overdue")
+	suite.NoError(err)
+	var titles []string
+	for _, task := range tasks {
+		titles = append(titles, task.Title)
+	}
+	suite.Contains(titles, unreported.Title)
+	suite.NotContains(titles, reported.Title)
+	suite.NotContains(titles, later.Title)
+}
+
 func TestTaskTestSuite(t *testing.T) {
     suite.Run(t, new(TaskTestSuite))
 }
@@ -4,6 +4,7 @@ package repository_test
 
 import (
 	"context"
+	"log/slog"
 	"testing"
 	"golang-clean-architecture/domain"
 	"github.com/stretchr/testify/suite"
@@ -40,6 +41,10 @@ func (suite *APITestSuite) SetupSuite() {
 	suite.collection = collection
 	suite.db = db
 	suite.repo = repository.NewUserRepository(db, "users")
+	err = repository.EnsureUserIndexes(db, "users", slog.Default())
+	if err != nil {
+		suite.T().Fatal(err)
+	}
 }
 
 func (suite *APITestSuite) TearDownSuite() {
@@ -64,7 +69,7 @@ func (suite *APITestSuite) TestRegisterSuccess() {
 		Role : "admin",
 	}
 
-	err := suite.repo.Register(user)
+	err := suite.repo.Register(context.Background(), user)
 	suite.NoError(err, "no error when registering user")
 	var insertedUser domain.User
 	err = suite.collection.FindOne(context.TODO(), bson.M{"_id" : user.ID}).Decode(&insertedUser)
@@ -83,12 +88,42 @@ func (suite *APITestSuite) TestSignUp_DupEmail() {
 		Role : "user",
 	}
 
-	err := suite.repo.Register(user)
+	err := suite.repo.Register(context.Background(), user)
 	suite.NoError(err, "no error when registering user")
-	err = suite.repo.UserExists(user)
+	err = suite.repo.UserExists(context.Background(), user)
 	suite.Error(err, "error user email already exists")
 }
 
+func (suite *APITestSuite) TestEmailsMatchRegardlessOfCase() {
+	// stored before emails were lower-cased on the way in
+	_, err := suite.collection.InsertOne(context.TODO(), domain.User{ID: primitive.NewObjectID(), Email: "Alice@X.com", Password: "123456789", Role: "user"})
+	suite.NoError(err)
+
+	found := suite.repo.GetUserByEmail(context.Background(), "alice@x.com")
+	suite.Equal("Alice@X.com", found.Email, "the account can still log in and reset its password")
+
+	err = suite.repo.UserExists(context.Background(), &domain.User{Email: "alice@x.com"})
+	suite.EqualError(err, "user email already in use")
+	err = suite.repo.Register(context.Background(), &domain.User{Email: "alice@x.com", Password: "123456789", Role: "user"})
+	suite.Error(err, "the index refuses a duplicate that differs only in case")
+}
+
+func (suite *APITestSuite) TestDuplicateEmailsDoNotStopTheIndexFromBeingEnsured() {
+	collection := suite.db.Collection("users_with_duplicates")
+	_, err := collection.InsertMany(context.TODO(), []interface{}{
+		domain.User{ID: primitive.NewObjectID(), Email: "Bob@X.com", Password: "123456789", Role: "user"},
+		domain.User{ID: primitive.NewObjectID(), Email: "bob@x.com", Password: "123456789", Role: "user"},
+	})
+	suite.NoError(err)
+
+	err = repository.EnsureUserIndexes(suite.db, "users_with_duplicates", slog.Default())
+	suite.NoError(err, "the duplicates are reported instead of keeping the server from starting")
+
+	repo := repository.NewUserRepository(suite.db, "users_with_duplicates")
+	found := repo.GetUserByEmail(context.Background(), "BOB@x.com")
+	suite.Contains([]string{"Bob@X.com", "bob@x.com"}, found.Email)
+}
+
 func (suite *APITestSuite) TestPromoteUser_Positive() {
 	user := &domain.User{
 		ID: primitive.NewObjectID(),
@@ -96,9 +131,9 @@ func (suite *APITestSuite) TestPromoteUser_Positive() {
 		Password: "123456789",
 		Role : "user",
 	}
-	err := suite.repo.Register(user)
+	err := suite.repo.Register(context.Background(), user)
 	suite.NoError(err, "no error when registering user")
-	err = suite.repo.PromoteUser(user.ID.Hex())
+	err = suite.repo.PromoteUser(context.Background(), user.ID.Hex())
 	suite.NoError(err, "no error while promoting user")
 }
 
@@ -111,9 +146,9 @@ func (suite *APITestSuite) TestPromoteUser_Negative() {
 		Role : "admin",
 	}
 
-	err := suite.repo.Register(user)
+	err := suite.repo.Register(context.Background(), user)
 	suite.NoError(err, "no error when registering user")
-	err = suite.repo.PromoteUser(user.ID.Hex())
+	err = suite.repo.PromoteUser(context.Background(), user.ID.Hex())
 	suite.Error(err, "error user already an admin")
 }
 
@@ -0,0 +1,155 @@
+package router_test
+
+import (
+	"errors"
+	"golang-clean-architecture/delivery/app"
+	"golang-clean-architecture/domain"
+	"golang-clean-architecture/domain/mocks"
+	"golang-clean-architecture/infrastructure"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// newTestApp builds the whole application over mock repositories and
+// in-memory services.
+func newTestApp(t *testing.T, repositories app.Repositories) *app.App {
+	keys, err := infrastructure.NewEphemeralKeyRing()
+	if err != nil {
+		t.Fatal(err)
+	}
+	services := app.Services{
+		Mailer:        infrastructure.LogMailer{},
+		Notifier:      infrastructure.LogNotifier{},
+		WebhookSender: new(mocks.WebhookSender),
+		Tokens:        infrastructure.NewJWTService(infrastructure.JWTConfig{Keys: keys, Issuer: "task-manager", Audience: "task-manager-api", TTL: time.Hour}),
+		Keys:          keys,
+		Hasher:        infrastructure.NewPasswordHasher(infrastructure.DefaultArgon2Params()),
+		Policy:        infrastructure.NewPasswordPolicy(infrastructure.DefaultPasswordRules()),
+		RateLimits:    infrastructure.NewMemoryRateLimitStore(),
+		TaskCache:     infrastructure.NewMemoryCache(100),
+		Logger:        slog.Default(),
+		Metrics:       infrastructure.NewMetrics(),
+		Tracing:       infrastructure.NewTracing(noop.NewTracerProvider()),
+	}
+	if repositories.LoginAttempts == nil {
+		repositories.LoginAttempts = infrastructure.NewMemoryLoginAttemptStore(time.Hour)
+	}
+	return app.New(app.LoadConfig(), repositories, services)
+}
+
+type AppTestSuite struct {
+	suite.Suite
+	users  *mocks.UserRepository
+	tasks  *mocks.TaskRepository
+	tokens *mocks.OneTimeTokenRepository
+	app    *app.App
+	router *gin.Engine
+}
+
+func (suite *AppTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	suite.users = new(mocks.UserRepository)
+	suite.tasks = new(mocks.TaskRepository)
+	suite.tokens = new(mocks.OneTimeTokenRepository)
+	suite.app = newTestApp(suite.T(), app.Repositories{
+		Users:         suite.users,
+		Tasks:         suite.tasks,
+		TaskSearch:    new(mocks.TaskSearcher),
+		TwoFactor:     new(mocks.TwoFactorRepository),
+		OneTimeTokens: suite.tokens,
+		APITokens:     new(mocks.APITokenRepository),
+		Webhooks:      new(mocks.WebhookRepository),
+	})
+	suite.router = gin.New()
+	suite.app.Setup(suite.router)
+}
+
+func (suite *AppTestSuite) getTasks(user domain.User) *httptest.ResponseRecorder {
+	token, err := suite.app.Dependencies.Tokens.IssueToken(&user)
+	suite.Require().NoError(err)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks", nil)
+	req.Header.Set("Authorization", "Bearer "+token.Token)
+	recorder := httptest.NewRecorder()
+	suite.router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func (suite *AppTestSuite) TestRequestsReachTheRepositories() {
+	user := domain.User{Email: "admin@example.com", Role: "admin"}
+	suite.users.On("GetUserByEmail", mock.Anything, user.Email).Return(user)
+	task := domain.Task{ID: primitive.NewObjectID(), Title: "Write report", Status: "pending"}
+	suite.tasks.On("GetTasks", mock.Anything).Return([]*domain.Task{&task}, nil).Once()
+
+	for i := 0; i < 2; i++ {
+		recorder := suite.getTasks(user)
+		suite.Equal(http.StatusOK, recorder.Code)
+		suite.Contains(recorder.Body.String(), "Write report")
+	}
+	// the second request is answered by the task cache
+	suite.tasks.AssertExpectations(suite.T())
+}
+
+func (suite *AppTestSuite) TestRevokedSessionsAreRejected() {
+	user := domain.User{Email: "admin@example.com", Role: "admin"}
+	revoked := user
+	revoked.SessionsRevokedAt = time.Now().Add(time.Hour)
+	suite.users.On("GetUserByEmail", mock.Anything, user.Email).Return(revoked)
+
+	recorder := suite.getTasks(user)
+	suite.Equal(http.StatusUnauthorized, recorder.Code)
+	suite.tasks.AssertNotCalled(suite.T(), "GetTasks", mock.Anything)
+}
+
+func (suite *AppTestSuite) TestTasksAreIndexedWhenTheBackendCannotSearch() {
+	searchless := newTestApp(suite.T(), app.Repositories{Users: suite.users, Tasks: suite.tasks})
+	engine := gin.New()
+	searchless.Setup(engine)
+	user := domain.User{Email: "admin@example.com", Role: "admin"}
+	suite.users.On("GetUserByEmail", mock.Anything, user.Email).Return(user)
+	task := domain.Task{ID: primitive.NewObjectID(), Title: "Order lunch", Status: "pending", Comments: []string{"Sam wants the vegetarian one"}}
+	suite.tasks.On("GetTasks", mock.Anything).Return([]*domain.Task{&task}, nil).Once()
+
+	token, err := searchless.Dependencies.Tokens.IssueToken(&user)
+	suite.Require().NoError(err)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks/search?q=vegetarian", nil)
+	req.Header.Set("Authorization", "Bearer "+token.Token)
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, req)
+
+	suite.Equal(http.StatusOK, recorder.Code)
+	suite.Contains(recorder.Body.String(), "Order lunch")
+	suite.Contains(recorder.Body.String(), `\u003cmark\u003evegetarian\u003c/mark\u003e`)
+	suite.tasks.AssertExpectations(suite.T())
+}
+
+func (suite *AppTestSuite) TestOnlySigningUpCountsAgainstTheRegisterLimit() {
+	suite.tokens.On("ConsumeToken", mock.Anything, "email_verification", mock.Anything, mock.Anything).Return(domain.OneTimeToken{}, errors.New("invalid or expired token"))
+	suite.app.Config.Router.RegisterLimit = domain.RateLimit{Requests: 1, Per: time.Hour}
+	engine := gin.New()
+	suite.app.Setup(engine)
+	serve := func(method string, target string) int {
+		recorder := httptest.NewRecorder()
+		engine.ServeHTTP(recorder, httptest.NewRequest(method, target, nil))
+		return recorder.Code
+	}
+
+	for i := 0; i < 3; i++ {
+		suite.Equal(http.StatusBadRequest, serve(http.MethodGet, "/api/v1/verify?token=expired"))
+	}
+	suite.Equal(http.StatusBadRequest, serve(http.MethodPost, "/api/v1/register"), "the first signup gets through")
+	suite.Equal(http.StatusTooManyRequests, serve(http.MethodPost, "/api/v1/register"))
+	suite.Equal(http.StatusBadRequest, serve(http.MethodGet, "/api/v1/verify?token=expired"))
+}
+
+func TestAppTestSuite(t *testing.T) {
+	suite.Run(t, new(AppTestSuite))
+}
@@ -0,0 +1,126 @@
+package router_test
+
+import (
+	"encoding/json"
+	"golang-clean-architecture/delivery/app"
+	"golang-clean-architecture/delivery/docs"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+)
+
+const v1 = "/api/v1"
+
+type OpenAPITestSuite struct {
+	suite.Suite
+	router *gin.Engine
+	// operations holds "METHOD path" for every operation of the document,
+	// with the path relative to the server it is served from.
+	operations map[string]bool
+}
+
+func (suite *OpenAPITestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	suite.router = gin.New()
+	// nothing reaches the repositories until a handler runs
+	newTestApp(suite.T(), app.Repositories{}).Setup(suite.router)
+
+	var document struct {
+		Servers []struct {
+			URL string `json:"url"`
+		} `json:"servers"`
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	suite.Require().NoError(json.Unmarshal(docs.Spec(), &document))
+	suite.Require().Len(document.Servers, 1)
+	suite.Require().Equal(v1, document.Servers[0].URL)
+
+	suite.operations = map[string]bool{}
+	for path, item := range document.Paths {
+		server := v1
+		if raw, ok := item["servers"]; ok {
+			var servers []struct {
+				URL string `json:"url"`
+			}
+			suite.Require().NoError(json.Unmarshal(raw, &servers))
+			server = strings.TrimSuffix(servers[0].URL, "/")
+		}
+		for method := range item {
+			if method != "servers" {
+				suite.operations[strings.ToUpper(method)+" "+server+path] = true
+			}
+		}
+	}
+}
+
+var pathParameter = regexp.MustCompile(`:(\w+)`)
+
+// registered returns "METHOD path" for every route, with OpenAPI's
+// {parameter} syntax.
+func (suite *OpenAPITestSuite) registered() map[string]bool {
+	routes := map[string]bool{}
+	for _, route := range suite.router.Routes() {
+		routes[route.Method+" "+pathParameter.ReplaceAllString(route.Path, "{$1}")] = true
+	}
+	return routes
+}
+
+func (suite *OpenAPITestSuite) TestEveryRouteIsDocumented() {
+	routes := suite.registered()
+	suite.Require().NotEmpty(routes)
+	for route := range routes {
+		method, path, _ := strings.Cut(route, " ")
+		if !strings.HasPrefix(path, v1+"/") && routes[method+" "+v1+path] {
+			// a deprecated alias, documented with the route it stands for
+			continue
+		}
+		suite.True(suite.operations[route], "%s isn't in openapi.json", route)
+	}
+}
+
+func (suite *OpenAPITestSuite) TestEveryDocumentedRouteExists() {
+	routes := suite.registered()
+	for operation := range suite.operations {
+		suite.True(routes[operation], "%s is documented but not registered", operation)
+	}
+}
+
+func (suite *OpenAPITestSuite) TestDocumentAndUIAreServed() {
+	recorder := httptest.NewRecorder()
+	suite.router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, v1+"/openapi.json", nil))
+	suite.Equal(http.StatusOK, recorder.Code)
+	suite.Equal("application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
+	suite.JSONEq(string(docs.Spec()), recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	suite.router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, v1+"/docs", nil))
+	suite.Equal(http.StatusOK, recorder.Code)
+	suite.Contains(recorder.Body.String(), `url: "openapi.json"`)
+}
+
+func (suite *OpenAPITestSuite) TestUnversionedRoutesAreDeprecated() {
+	recorder := httptest.NewRecorder()
+	suite.router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	suite.Equal(http.StatusOK, recorder.Code, "the alias still answers")
+	suite.Equal("@1792368000", recorder.Header().Get("Deprecation"))
+	suite.Equal("Mon, 19 Apr 2027 00:00:00 GMT", recorder.Header().Get("Sunset"))
+	suite.Equal(`</api/v1/openapi.json>; rel="successor-version"`, recorder.Header().Get("Link"))
+
+	recorder = httptest.NewRecorder()
+	suite.router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/tasks", nil))
+	suite.Equal(http.StatusBadRequest, recorder.Code, "aliases are authenticated like the routes they stand for")
+	suite.NotEmpty(recorder.Header().Get("Deprecation"))
+
+	recorder = httptest.NewRecorder()
+	suite.router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, v1+"/openapi.json", nil))
+	suite.Empty(recorder.Header().Get("Deprecation"))
+}
+
+func TestOpenAPITestSuite(t *testing.T) {
+	suite.Run(t, new(OpenAPITestSuite))
+}
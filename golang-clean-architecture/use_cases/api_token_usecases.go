@@ -0,0 +1,156 @@
+package use_cases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang-clean-architecture/domain"
+	"golang-clean-architecture/infrastructure"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// APITokenScopes lists the scopes an API token can be granted. Each guards
+// reading or writing one group of routes; managing API tokens themselves
+// always needs a password login.
+var APITokenScopes = map[string]bool{
+	"tasks:read":     true,
+	"tasks:write":    true,
+	"webhooks:read":  true,
+	"webhooks:write": true,
+	"profile:read":   true,
+	"profile:write":  true,
+	"users:write":    true,
+}
+
+const (
+	maxAPITokenNameLength = 64
+	// last_used_at is only written when it is older than this, so that a
+	// busy script doesn't cost a database write per request
+	lastUsedResolution = time.Minute
+)
+
+type APITokenUseCase struct {
+	Users  domain.UserRepository
+	Tokens domain.APITokenRepository
+	Now    func() time.Time
+	Logger *slog.Logger
+}
+
+func NewAPITokenUseCase(ur domain.UserRepository, tokens domain.APITokenRepository, logger *slog.Logger) domain.APITokenUseCase {
+	return &APITokenUseCase{
+		Users:  ur,
+		Tokens: tokens,
+		Now:    time.Now,
+		Logger: logger,
+	}
+}
+
+// CreateToken validates and stores token for the user and returns its
+// secret. Only a hash is kept, so the secret can't be shown again.
+func (au *APITokenUseCase) CreateToken(ctx context.Context, email string, token *domain.APIToken) (string, error) {
+	token.Name = strings.TrimSpace(token.Name)
+	if token.Name == "" {
+		return "", errors.New("token name is required")
+	}
+	if len([]rune(token.Name)) > maxAPITokenNameLength {
+		return "", fmt.Errorf("token name must be at most %d characters long", maxAPITokenNameLength)
+	}
+	if len(token.Scopes) == 0 {
+		return "", errors.New("at least one scope is required")
+	}
+	seen := map[string]bool{}
+	scopes := []string{}
+	for _, scope := range token.Scopes {
+		if !APITokenScopes[scope] {
+			return "", errors.New("unsupported scope " + scope)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	now := au.Now()
+	if token.ExpiresAt != nil && !token.ExpiresAt.After(now) {
+		return "", errors.New("expiry must be in the future")
+	}
+
+	user := au.Users.GetUserByEmail(ctx, email)
+	if user == (domain.User{}) {
+		return "", errors.New("user not found")
+	}
+
+	random, err := infrastructure.GenerateRandomToken(32)
+	if err != nil {
+		return "", errors.New("internal server error")
+	}
+	secret := infrastructure.APITokenPrefix + random
+
+	token.UserID = user.ID.Hex()
+	token.Scopes = scopes
+	token.Prefix = secret[:len(infrastructure.APITokenPrefix)+6]
+	token.TokenHash = infrastructure.HashToken(secret)
+	token.LastUsedAt = nil
+	token.CreatedAt = now
+	if err := au.Tokens.CreateAPIToken(ctx, token); err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+func (au *APITokenUseCase) GetTokens(ctx context.Context, email string) ([]*domain.APIToken, error) {
+	user := au.Users.GetUserByEmail(ctx, email)
+	if user == (domain.User{}) {
+		return nil, errors.New("user not found")
+	}
+	return au.Tokens.GetAPITokens(ctx, user.ID.Hex())
+}
+
+func (au *APITokenUseCase) RevokeToken(ctx context.Context, email string, tokenID string) error {
+	user := au.Users.GetUserByEmail(ctx, email)
+	if user == (domain.User{}) {
+		return errors.New("user not found")
+	}
+	return au.Tokens.DeleteAPIToken(ctx, user.ID.Hex(), tokenID)
+}
+
+// Authenticate resolves an API token secret to the user it acts for. The
+// user's current role applies, so demoting a user also limits their tokens.
+func (au *APITokenUseCase) Authenticate(ctx context.Context, secret string) (domain.AuthenticatedUser, error) {
+	token, err := au.Tokens.GetAPITokenByHash(ctx, infrastructure.HashToken(secret))
+	if err != nil {
+		if err.Error() == "internal server error" {
+			return domain.AuthenticatedUser{}, err
+		}
+		return domain.AuthenticatedUser{}, errors.New("invalid api token")
+	}
+	now := au.Now()
+	// the TTL index removes expired tokens, but only once a minute or so
+	if token.ExpiresAt != nil && !now.Before(*token.ExpiresAt) {
+		return domain.AuthenticatedUser{}, errors.New("invalid api token")
+	}
+
+	user, err := au.Users.GetUserByID(ctx, token.UserID)
+	if err != nil {
+		if err.Error() == "internal server error" {
+			return domain.AuthenticatedUser{}, err
+		}
+		return domain.AuthenticatedUser{}, errors.New("invalid api token")
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= lastUsedResolution {
+		if err := au.Tokens.TouchAPIToken(ctx, token.ID.Hex(), now); err != nil {
+			au.Logger.ErrorContext(ctx, "error while recording api token use", "api_token_id", token.ID.Hex(), "error", err)
+		}
+	}
+
+	return domain.AuthenticatedUser{
+		Role:       user.Role,
+		Email:      user.Email,
+		IssuedAt:   token.CreatedAt,
+		TwoFactor:  user.TwoFactorEnabled,
+		APITokenID: token.ID.Hex(),
+		Scopes:     token.Scopes,
+	}, nil
+}
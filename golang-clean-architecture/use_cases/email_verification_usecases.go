@@ -0,0 +1,104 @@
+package use_cases
+
+import (
+	"context"
+	"errors"
+	"golang-clean-architecture/domain"
+	"golang-clean-architecture/infrastructure"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+const emailVerificationPurpose = "email_verification"
+
+type EmailVerificationUseCase struct {
+	Users    domain.UserRepository
+	Tokens   domain.OneTimeTokenRepository
+	Mailer   domain.Mailer
+	TokenTTL time.Duration
+	// VerifyURL is where the emailed link points; the token is added to it
+	// as the "token" query parameter.
+	VerifyURL string
+	Now       func() time.Time
+	Logger    *slog.Logger
+}
+
+func NewEmailVerificationUseCase(ur domain.UserRepository, tokens domain.OneTimeTokenRepository, mailer domain.Mailer, ttl time.Duration, verifyURL string, logger *slog.Logger) domain.EmailVerificationUseCase {
+	return &EmailVerificationUseCase{
+		Users:     ur,
+		Tokens:    tokens,
+		Mailer:    mailer,
+		TokenTTL:  ttl,
+		VerifyURL: verifyURL,
+		Now:       time.Now,
+		Logger:    logger,
+	}
+}
+
+// SendVerification emails a verification link to a newly registered user.
+func (ev *EmailVerificationUseCase) SendVerification(ctx context.Context, user *domain.User) error {
+	token, err := issueToken(ctx, ev.Tokens, emailVerificationPurpose, user.ID.Hex(), ev.TokenTTL, ev.Now())
+	if err != nil {
+		return err
+	}
+
+	err = ev.Mailer.Send(ctx, domain.Mail{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: "Welcome! Open the link below to confirm that this is your email address:\n\n" +
+			tokenLink(ev.VerifyURL, token) + "\n\n" +
+			"The link expires in " + ev.TokenTTL.String() + ". If you didn't create an account you can ignore this email.",
+	})
+	if err != nil {
+		return errors.New("error while sending verification email")
+	}
+	return nil
+}
+
+// ResendVerification replaces any outstanding verification link with a new
+// one. Like ForgotPassword it doesn't reveal whether the email is registered.
+func (ev *EmailVerificationUseCase) ResendVerification(ctx context.Context, email string) error {
+	email = normalizeEmail(email)
+	if email == "" {
+		return errors.New("required field missing")
+	}
+
+	user := ev.Users.GetUserByEmail(ctx, email)
+	if user == (domain.User{}) || user.Verified {
+		return nil
+	}
+
+	err := ev.Tokens.RevokeTokens(ctx, user.ID.Hex(), emailVerificationPurpose)
+	if err != nil {
+		return err
+	}
+	err = ev.SendVerification(ctx, &user)
+	if err != nil {
+		ev.Logger.ErrorContext(ctx, "error while resending verification email", "user_id", user.ID.Hex(), "error", err)
+	}
+	return nil
+}
+
+func (ev *EmailVerificationUseCase) VerifyEmail(ctx context.Context, token string) error {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return errors.New("required field missing")
+	}
+
+	verificationToken, err := ev.Tokens.ConsumeToken(ctx, emailVerificationPurpose, infrastructure.HashToken(token), ev.Now())
+	if err != nil {
+		return err
+	}
+
+	err = ev.Users.MarkVerified(ctx, verificationToken.UserID)
+	if err != nil {
+		return err
+	}
+
+	err = ev.Tokens.RevokeTokens(ctx, verificationToken.UserID, emailVerificationPurpose)
+	if err != nil {
+		ev.Logger.ErrorContext(ctx, "error while revoking verification tokens", "user_id", verificationToken.UserID, "error", err)
+	}
+	return nil
+}
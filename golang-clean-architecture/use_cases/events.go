@@ -0,0 +1,17 @@
+package use_cases
+
+import (
+	"golang-clean-architecture/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newEvent(eventType string, data interface{}) domain.Event {
+	return domain.Event{
+		ID:         primitive.NewObjectID().Hex(),
+		Type:       eventType,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	}
+}
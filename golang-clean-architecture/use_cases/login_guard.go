@@ -0,0 +1,162 @@
+package use_cases
+
+import (
+	"context"
+	"errors"
+	"golang-clean-architecture/domain"
+	"log/slog"
+	"time"
+)
+
+const tooManyAttemptsMessage = "too many failed login attempts, try again later"
+
+type LoginGuardPolicy struct {
+	// MaxAccountFailures failures within Window lock the account.
+	MaxAccountFailures int
+	// MaxIPFailures failures within Window block the client IP. It is higher
+	// than MaxAccountFailures because many users can share an IP.
+	MaxIPFailures int
+	Window        time.Duration
+	Lockout       time.Duration
+	// After a failed attempt the account must wait BaseDelay, doubling with
+	// every further failure up to MaxDelay, before the next attempt.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+func DefaultLoginGuardPolicy() LoginGuardPolicy {
+	return LoginGuardPolicy{
+		MaxAccountFailures: 5,
+		MaxIPFailures:      50,
+		Window:             15 * time.Minute,
+		Lockout:            15 * time.Minute,
+		BaseDelay:          time.Second,
+		MaxDelay:           30 * time.Second,
+	}
+}
+
+// LoginGuard slows down and then locks out repeated failed logins, both per
+// account and per client IP.
+type LoginGuard struct {
+	Store  domain.LoginAttemptStore
+	Events domain.EventPublisher
+	Policy LoginGuardPolicy
+	Logger *slog.Logger
+}
+
+func NewLoginGuard(store domain.LoginAttemptStore, events domain.EventPublisher, policy LoginGuardPolicy, logger *slog.Logger) domain.LoginGuard {
+	return &LoginGuard{
+		Store:  store,
+		Events: events,
+		Policy: policy,
+		Logger: logger,
+	}
+}
+
+func accountKey(email string) string {
+	return "account:" + email
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+// Check refuses an attempt while the account or IP is locked out or the
+// account's progressive delay hasn't passed yet. The same error is used in
+// every case so that it doesn't reveal which limit was hit.
+func (lg *LoginGuard) Check(ctx context.Context, email string, ip string, now time.Time) error {
+	ipAttempts, err := lg.Store.Get(ctx, ipKey(ip))
+	if err != nil {
+		return errors.New("internal server error")
+	}
+	if now.Before(ipAttempts.LockedUntil) {
+		return tooManyAttempts(ipAttempts.LockedUntil.Sub(now))
+	}
+
+	accountAttempts, err := lg.Store.Get(ctx, accountKey(email))
+	if err != nil {
+		return errors.New("internal server error")
+	}
+	if now.Before(accountAttempts.LockedUntil) {
+		return tooManyAttempts(accountAttempts.LockedUntil.Sub(now))
+	}
+	if accountAttempts.Failures > 0 && now.Sub(accountAttempts.LastFailure) <= lg.Policy.Window {
+		retryAt := accountAttempts.LastFailure.Add(lg.delay(accountAttempts.Failures))
+		if now.Before(retryAt) {
+			return tooManyAttempts(retryAt.Sub(now))
+		}
+	}
+	return nil
+}
+
+// RecordFailure counts a failed attempt and locks the account or IP once
+// its limit is reached. Unknown emails are counted too, so lockouts don't
+// reveal which accounts exist.
+func (lg *LoginGuard) RecordFailure(ctx context.Context, email string, ip string, now time.Time) error {
+	accountAttempts, err := lg.Store.RecordFailure(ctx, accountKey(email), now, lg.Policy.Window)
+	if err != nil {
+		return errors.New("internal server error")
+	}
+	if accountAttempts.Failures >= lg.Policy.MaxAccountFailures && !now.Before(accountAttempts.LockedUntil) {
+		lockedUntil := now.Add(lg.Policy.Lockout)
+		if err := lg.Store.Lock(ctx, accountKey(email), lockedUntil); err != nil {
+			return errors.New("internal server error")
+		}
+		lg.Logger.WarnContext(ctx, "locked out account after failed logins", "email", email, "failures", accountAttempts.Failures, "locked_until", lockedUntil)
+		lg.Events.Publish(ctx, newEvent("user.locked_out", map[string]interface{}{
+			"email":        email,
+			"ip":           ip,
+			"failures":     accountAttempts.Failures,
+			"locked_until": lockedUntil,
+		}))
+	}
+
+	ipAttempts, err := lg.Store.RecordFailure(ctx, ipKey(ip), now, lg.Policy.Window)
+	if err != nil {
+		return errors.New("internal server error")
+	}
+	if ipAttempts.Failures >= lg.Policy.MaxIPFailures && !now.Before(ipAttempts.LockedUntil) {
+		lockedUntil := now.Add(lg.Policy.Lockout)
+		if err := lg.Store.Lock(ctx, ipKey(ip), lockedUntil); err != nil {
+			return errors.New("internal server error")
+		}
+		lg.Logger.WarnContext(ctx, "blocked client ip after failed logins", "ip", ip, "failures", ipAttempts.Failures, "locked_until", lockedUntil)
+		lg.Events.Publish(ctx, newEvent("login.ip_blocked", map[string]interface{}{
+			"ip":           ip,
+			"failures":     ipAttempts.Failures,
+			"locked_until": lockedUntil,
+		}))
+	}
+	return nil
+}
+
+// RecordSuccess clears the account's failures. The IP counter is left alone,
+// otherwise an attacker could reset it by logging into their own account.
+func (lg *LoginGuard) RecordSuccess(ctx context.Context, email string) error {
+	if err := lg.Store.Reset(ctx, accountKey(email)); err != nil {
+		return errors.New("internal server error")
+	}
+	return nil
+}
+
+func (lg *LoginGuard) Unlock(ctx context.Context, email string) error {
+	if err := lg.Store.Reset(ctx, accountKey(email)); err != nil {
+		return errors.New("internal server error")
+	}
+	return nil
+}
+
+func (lg *LoginGuard) delay(failures int) time.Duration {
+	delay := lg.Policy.BaseDelay
+	for i := 1; i < failures && delay < lg.Policy.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > lg.Policy.MaxDelay {
+		return lg.Policy.MaxDelay
+	}
+	return delay
+}
+
+func tooManyAttempts(retryAfter time.Duration) error {
+	return &domain.RetryError{Message: tooManyAttemptsMessage, RetryAfter: retryAfter}
+}
@@ -0,0 +1,123 @@
+package use_cases
+
+import (
+	"context"
+	"errors"
+	"golang-clean-architecture/domain"
+	"golang-clean-architecture/infrastructure"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+const passwordResetPurpose = "password_reset"
+
+type PasswordResetUseCase struct {
+	Users    domain.UserRepository
+	Tokens   domain.OneTimeTokenRepository
+	Mailer   domain.Mailer
+	Hasher   domain.PasswordHasher
+	Policy   domain.PasswordPolicy
+	TokenTTL time.Duration
+	// ResetURL is the page the emailed link points to; the token is added
+	// to it as the "token" query parameter.
+	ResetURL string
+	Now      func() time.Time
+	Logger   *slog.Logger
+}
+
+func NewPasswordResetUseCase(ur domain.UserRepository, tokens domain.OneTimeTokenRepository, mailer domain.Mailer, hasher domain.PasswordHasher, policy domain.PasswordPolicy, ttl time.Duration, resetURL string, logger *slog.Logger) domain.PasswordResetUseCase {
+	return &PasswordResetUseCase{
+		Users:    ur,
+		Tokens:   tokens,
+		Mailer:   mailer,
+		Hasher:   hasher,
+		Policy:   policy,
+		TokenTTL: ttl,
+		ResetURL: resetURL,
+		Now:      time.Now,
+		Logger:   logger,
+	}
+}
+
+// ForgotPassword emails a reset link to the account with the given email.
+// It succeeds whether or not the account exists so that the endpoint can't be
+// used to find out which emails are registered.
+func (pr *PasswordResetUseCase) ForgotPassword(ctx context.Context, email string) error {
+	email = normalizeEmail(email)
+	if email == "" {
+		return errors.New("required field missing")
+	}
+
+	user := pr.Users.GetUserByEmail(ctx, email)
+	if user == (domain.User{}) {
+		return nil
+	}
+
+	token, err := issueToken(ctx, pr.Tokens, passwordResetPurpose, user.ID.Hex(), pr.TokenTTL, pr.Now())
+	if err != nil {
+		return err
+	}
+
+	err = pr.Mailer.Send(ctx, domain.Mail{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: "Someone asked to reset the password for your account. If it was you, open the link below to choose a new password:\n\n" +
+			tokenLink(pr.ResetURL, token) + "\n\n" +
+			"The link expires in " + pr.TokenTTL.String() + " and can only be used once. If you didn't ask for a reset you can ignore this email.",
+	})
+	if err != nil {
+		// reporting the failure would reveal that the account exists
+		pr.Logger.ErrorContext(ctx, "error while sending password reset email", "user_id", user.ID.Hex(), "error", err)
+	}
+	return nil
+}
+
+// ResetPassword sets a new password for the owner of a valid reset token and
+// signs them out everywhere else.
+func (pr *PasswordResetUseCase) ResetPassword(ctx context.Context, token string, password string) error {
+	token = strings.TrimSpace(token)
+	if token == "" || password == "" {
+		return errors.New("required field missing")
+	}
+
+	// the token is only spent once the password is accepted, so that a
+	// refused password doesn't cost the user their link
+	now := pr.Now()
+	tokenHash := infrastructure.HashToken(token)
+	resetToken, err := pr.Tokens.GetToken(ctx, passwordResetPurpose, tokenHash, now)
+	if err != nil {
+		return err
+	}
+	user, err := pr.Users.GetUserByID(ctx, resetToken.UserID)
+	if err != nil {
+		return err
+	}
+	if err := pr.Policy.Validate(password, user.Email); err != nil {
+		return err
+	}
+	hashedPassword, err := pr.Hasher.Hash(password)
+	if err != nil {
+		return errors.New("internal server error")
+	}
+
+	resetToken, err = pr.Tokens.ConsumeToken(ctx, passwordResetPurpose, tokenHash, now)
+	if err != nil {
+		return err
+	}
+
+	// JWTs carry whole-second issue times, so the revocation time is
+	// truncated to let a login right after the reset through
+	err = pr.Users.UpdatePassword(ctx, resetToken.UserID, hashedPassword, now.Truncate(time.Second))
+	if err != nil {
+		return err
+	}
+
+	// any other links sent before this reset must not work any more
+	err = pr.Tokens.RevokeTokens(ctx, resetToken.UserID, passwordResetPurpose)
+	if err != nil {
+		pr.Logger.ErrorContext(ctx, "error while revoking password reset tokens", "user_id", resetToken.UserID, "error", err)
+	}
+	return nil
+}
+
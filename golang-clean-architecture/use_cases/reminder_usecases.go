@@ -0,0 +1,107 @@
+package use_cases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang-clean-architecture/domain"
+	"sort"
+	"time"
+)
+
+type ReminderUseCase struct {
+	Repository domain.TaskRepository
+	Notifier   domain.Notifier
+	LeadTimes  []time.Duration
+}
+
+func NewReminderUseCase(tr domain.TaskRepository, notifier domain.Notifier, leadTimes []time.Duration) domain.ReminderUseCase {
+	sorted := append([]time.Duration{}, leadTimes...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return &ReminderUseCase{
+		Repository: tr,
+		Notifier:   notifier,
+		LeadTimes:  sorted,
+	}
+}
+
+// overdueReminder is the last reminder a task gets.
+const overdueReminder = "overdue"
+
+// CheckDueTasks marks tasks whose due date has passed as overdue and sends a
+// reminder for every task entering one of the configured lead-time windows.
+// Each reminder is recorded on the task before it is sent, so it goes out
+// once even when several instances run the check. Tasks that were already
+// reported overdue aren't fetched again.
+func (ru *ReminderUseCase) CheckDueTasks(ctx context.Context, now time.Time) error {
+	horizon := now
+	if len(ru.LeadTimes) > 0 {
+		horizon = now.Add(ru.LeadTimes[len(ru.LeadTimes)-1])
+	}
+
+	tasks, err := ru.Repository.GetTasksDueBefore(ctx, horizon, overdueReminder)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, task := range tasks {
+		if err := ru.checkTask(ctx, task, now); err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("error while sending reminders for %d task(s)", failed)
+	}
+	return nil
+}
+
+func (ru *ReminderUseCase) checkTask(ctx context.Context, task *domain.Task, now time.Time) error {
+	taskID := task.ID.Hex()
+
+	if !task.DueDate.After(now) {
+		if task.Status != "overdue" {
+			err := ru.Repository.UpdateTask(ctx, taskID, &domain.Task{Status: "overdue"})
+			if err != nil {
+				return err
+			}
+			task.Status = "overdue"
+		}
+		return ru.remind(ctx, task, overdueReminder, domain.Notification{
+			Type:    "task.overdue",
+			Subject: "Task overdue: " + task.Title,
+			Message: fmt.Sprintf("%q was due at %s.", task.Title, task.DueDate.Format(time.RFC1123)),
+			Task:    task,
+		})
+	}
+
+	// only the tightest window that applies is reminded about; wider ones
+	// that were missed, e.g. while the service was down, are skipped
+	remaining := task.DueDate.Sub(now)
+	for _, leadTime := range ru.LeadTimes {
+		if remaining <= leadTime {
+			return ru.remind(ctx, task, "due:"+leadTime.String(), domain.Notification{
+				Type:    "task.due_soon",
+				Subject: "Task due soon: " + task.Title,
+				Message: fmt.Sprintf("%q is due at %s.", task.Title, task.DueDate.Format(time.RFC1123)),
+				Task:    task,
+			})
+		}
+	}
+	return nil
+}
+
+func (ru *ReminderUseCase) remind(ctx context.Context, task *domain.Task, key string, notification domain.Notification) error {
+	taskID := task.ID.Hex()
+	claimed, err := ru.Repository.MarkReminderSent(ctx, taskID, key)
+	if err != nil || !claimed {
+		return err
+	}
+
+	if err := ru.Notifier.Notify(ctx, notification); err != nil {
+		// release the reminder so the next run retries it
+		ru.Repository.ClearReminderSent(ctx, taskID, key)
+		return errors.New("error while sending reminder")
+	}
+	return nil
+}
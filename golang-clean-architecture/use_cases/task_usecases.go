@@ -1,32 +1,47 @@
 package use_cases
 
 import (
+	"context"
 	"golang-clean-architecture/domain"
+	"golang-clean-architecture/infrastructure"
 	"strings"
 	"errors"
+	"time"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit = 100
+	// bounds how many missed occurrences of one series a scheduler run
+	// catches up on, so a daily series left alone for years can't flood the db
+	maxOccurrencesPerRun = 31
 )
 
 type TaskUseCase struct {
 	Repository 		domain.TaskRepository
+	Searcher		domain.TaskSearcher
+	Events			domain.EventPublisher
 }
 
-func NewTaskUseCase(tr domain.TaskRepository) domain.TaskUseCase {
+func NewTaskUseCase(tr domain.TaskRepository, ts domain.TaskSearcher, events domain.EventPublisher) domain.TaskUseCase {
 	return &TaskUseCase {
 		Repository: tr,
+		Searcher: ts,
+		Events: events,
 	}
 }
 
-func (tu *TaskUseCase) GetTasks() ([]*domain.Task, error) {
-	tasks, err := tu.Repository.GetTasks()
+func (tu *TaskUseCase) GetTasks(ctx context.Context) ([]*domain.Task, error) {
+	tasks, err := tu.Repository.GetTasks(ctx)
 	return tasks, err
 }
 
-func (tu *TaskUseCase) GetTask(taskId string) (domain.Task, error) {
-	task, err := tu.Repository.GetTask(taskId)
+func (tu *TaskUseCase) GetTask(ctx context.Context, taskId string) (domain.Task, error) {
+	task, err := tu.Repository.GetTask(ctx, taskId)
 	return task, err
 }
 
-func (tu *TaskUseCase) PostTask(task domain.Task) error {
+func (tu *TaskUseCase) PostTask(ctx context.Context, task *domain.Task) error {
 
 	task.Description = strings.TrimSpace(task.Description)
 	task.Title = strings.TrimSpace(task.Title)
@@ -35,16 +50,209 @@ func (tu *TaskUseCase) PostTask(task domain.Task) error {
 	if task.Description == "" || task.Status == "" || task.Title == "" {
 		return errors.New("required fields are missing")
 	}
-	err := tu.Repository.PostTask(&task)
-	return err
+
+	task.Recurrence = strings.TrimSpace(task.Recurrence)
+	task.SeriesID = ""
+	task.Occurrence = 0
+	if task.Recurrence != "" {
+		if _, err := infrastructure.ParseRecurrenceRule(task.Recurrence); err != nil {
+			return err
+		}
+		if task.DueDate.IsZero() {
+			return errors.New("recurring tasks require a due date")
+		}
+		task.Occurrence = 1
+	}
+
+	err := tu.Repository.PostTask(ctx, task)
+	if err != nil {
+		return err
+	}
+	tu.Events.Publish(ctx, newEvent("task.created", task))
+	return nil
 }
 
-func (tu *TaskUseCase) DeleteTask(taskID string) error {
-	err := tu.Repository.DeleteTask(taskID)
-	return err
+func (tu *TaskUseCase) DeleteTask(ctx context.Context, taskID string) error {
+	err := tu.Repository.DeleteTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	tu.Events.Publish(ctx, newEvent("task.deleted", map[string]string{"id" : taskID}))
+	return nil
 }
 
-func (tu *TaskUseCase) UpdateTask(taskID string, modifiedTask *domain.Task) error {
-	err := tu.Repository.UpdateTask(taskID, modifiedTask)
+func (tu *TaskUseCase) UpdateTask(ctx context.Context, taskID string, modifiedTask *domain.Task) error {
+	err := tu.Repository.UpdateTask(ctx, taskID, modifiedTask)
+	if err != nil {
+		return err
+	}
+	tu.Events.Publish(ctx, newEvent("task.updated", map[string]interface{}{"id" : taskID, "changes" : modifiedTask}))
+	if !isCompleted(modifiedTask.Status) {
+		return nil
+	}
+
+	// completing an occurrence of a recurring task brings the next one in
+	// straight away instead of waiting for the scheduler
+	task, err := tu.Repository.GetTask(ctx, taskID)
+	if err != nil || task.Recurrence == "" {
+		return nil
+	}
+	_, err = tu.materializeNext(ctx, &task)
 	return err
-}
\ No newline at end of file
+}
+
+func (tu *TaskUseCase) SearchTasks(ctx context.Context, query string, limit int) ([]*domain.TaskSearchResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, errors.New("search query is required")
+	}
+
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	results, err := tu.Searcher.SearchTasks(ctx, query, limit)
+	return results, err
+}
+
+func (tu *TaskUseCase) UpdateRecurrence(ctx context.Context, taskID string, rule string) error {
+	rule = strings.TrimSpace(rule)
+	if _, err := infrastructure.ParseRecurrenceRule(rule); err != nil {
+		return err
+	}
+
+	task, err := tu.Repository.GetTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	if task.DueDate.IsZero() {
+		return errors.New("recurring tasks require a due date")
+	}
+
+	err = tu.Repository.UpdateSeriesRecurrence(ctx, seriesIDOf(&task), rule)
+	if err != nil {
+		return err
+	}
+	tu.Events.Publish(ctx, newEvent("task.updated", map[string]interface{}{"id" : taskID, "changes" : map[string]string{"recurrence" : rule}}))
+	return nil
+}
+
+func (tu *TaskUseCase) StopRecurrence(ctx context.Context, taskID string) error {
+	task, err := tu.Repository.GetTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	if task.Recurrence == "" {
+		return errors.New("task is not part of a recurring series")
+	}
+
+	err = tu.Repository.UpdateSeriesRecurrence(ctx, seriesIDOf(&task), "")
+	if err != nil {
+		return err
+	}
+	tu.Events.Publish(ctx, newEvent("task.updated", map[string]interface{}{"id" : taskID, "changes" : map[string]string{"recurrence" : ""}}))
+	return nil
+}
+
+// MaterializeRecurringTasks creates every pending occurrence of every active
+// series that falls due before the given time.
+func (tu *TaskUseCase) MaterializeRecurringTasks(ctx context.Context, until time.Time) error {
+	tasks, err := tu.Repository.GetRecurringTasks(ctx)
+	if err != nil {
+		return err
+	}
+
+	latest := map[string]*domain.Task{}
+	for _, task := range tasks {
+		seriesID := seriesIDOf(task)
+		if current, ok := latest[seriesID]; !ok || occurrenceOf(task) > occurrenceOf(current) {
+			latest[seriesID] = task
+		}
+	}
+
+	for _, task := range latest {
+		for created := 0; created < maxOccurrencesPerRun; created++ {
+			rule, err := infrastructure.ParseRecurrenceRule(task.Recurrence)
+			if err != nil {
+				break
+			}
+			next, ok := infrastructure.NextOccurrence(rule, task.DueDate, occurrenceOf(task))
+			if !ok || next.After(until) {
+				break
+			}
+			task, err = tu.materializeNext(ctx, task)
+			if err != nil {
+				return err
+			}
+			if task == nil {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// materializeNext creates the occurrence following current unless the series
+// is exhausted or that occurrence already exists. It returns the created task,
+// or nil when nothing was created.
+func (tu *TaskUseCase) materializeNext(ctx context.Context, current *domain.Task) (*domain.Task, error) {
+	rule, err := infrastructure.ParseRecurrenceRule(current.Recurrence)
+	if err != nil {
+		return nil, err
+	}
+	next, ok := infrastructure.NextOccurrence(rule, current.DueDate, occurrenceOf(current))
+	if !ok {
+		return nil, nil
+	}
+
+	seriesID := seriesIDOf(current)
+	series, err := tu.Repository.GetSeriesTasks(ctx, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	for _, task := range series {
+		if occurrenceOf(task) > occurrenceOf(current) {
+			return nil, nil
+		}
+	}
+
+	task := &domain.Task{
+		Title: current.Title,
+		Description: current.Description,
+		DueDate: next,
+		Status: "pending",
+		Recurrence: current.Recurrence,
+		SeriesID: seriesID,
+		Occurrence: occurrenceOf(current) + 1,
+	}
+	err = tu.Repository.PostTask(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+	tu.Events.Publish(ctx, newEvent("task.created", task))
+	return task, nil
+}
+
+func seriesIDOf(task *domain.Task) string {
+	if task.SeriesID != "" {
+		return task.SeriesID
+	}
+	return task.ID.Hex()
+}
+
+// occurrenceOf treats tasks made recurring after creation, which were never
+// numbered, as the first occurrence of their series.
+func occurrenceOf(task *domain.Task) int {
+	if task.Occurrence < 1 {
+		return 1
+	}
+	return task.Occurrence
+}
+
+func isCompleted(status string) bool {
+	status = strings.ToLower(strings.TrimSpace(status))
+	return status == "completed" || status == "done"
+}
@@ -0,0 +1,44 @@
+package use_cases
+
+import (
+	"context"
+	"errors"
+	"golang-clean-architecture/domain"
+	"golang-clean-architecture/infrastructure"
+	"net/url"
+	"time"
+)
+
+// issueToken creates a single-use token for the user and returns it in the
+// clear; only its hash is stored.
+func issueToken(ctx context.Context, tokens domain.OneTimeTokenRepository, purpose string, userID string, ttl time.Duration, now time.Time) (string, error) {
+	token, err := infrastructure.GenerateRandomToken(32)
+	if err != nil {
+		return "", errors.New("internal server error")
+	}
+
+	err = tokens.CreateToken(ctx, &domain.OneTimeToken{
+		Purpose:   purpose,
+		UserID:    userID,
+		TokenHash: infrastructure.HashToken(token),
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return "", errors.New("internal server error")
+	}
+	return token, nil
+}
+
+// tokenLink adds the token to base as the "token" query parameter, keeping
+// any parameters base already has.
+func tokenLink(base string, token string) string {
+	link, err := url.Parse(base)
+	if err != nil {
+		return base + "?token=" + url.QueryEscape(token)
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+	return link.String()
+}
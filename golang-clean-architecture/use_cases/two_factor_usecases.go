@@ -0,0 +1,168 @@
+package use_cases
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"golang-clean-architecture/domain"
+	"golang-clean-architecture/infrastructure"
+	"strings"
+	"time"
+)
+
+const (
+	loginChallengePurpose = "login_challenge"
+	recoveryCodeCount     = 10
+	invalidCodeMessage    = "invalid two-factor code"
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TwoFactorUseCase enrolls users in TOTP based 2FA and checks the second
+// step of their logins.
+type TwoFactorUseCase struct {
+	Users        domain.UserRepository
+	TwoFactor    domain.TwoFactorRepository
+	Tokens       domain.OneTimeTokenRepository
+	Issuer       string
+	ChallengeTTL time.Duration
+	Now          func() time.Time
+}
+
+func NewTwoFactorUseCase(ur domain.UserRepository, twoFactor domain.TwoFactorRepository, tokens domain.OneTimeTokenRepository, issuer string, challengeTTL time.Duration) domain.TwoFactorUseCase {
+	return &TwoFactorUseCase{
+		Users:        ur,
+		TwoFactor:    twoFactor,
+		Tokens:       tokens,
+		Issuer:       issuer,
+		ChallengeTTL: challengeTTL,
+		Now:          time.Now,
+	}
+}
+
+// BeginEnrollment generates a secret for the user to add to their
+// authenticator app. 2FA stays off until ConfirmEnrollment sees a code made
+// from it.
+func (tf *TwoFactorUseCase) BeginEnrollment(ctx context.Context, email string) (domain.TwoFactorEnrollment, error) {
+	user := tf.Users.GetUserByEmail(ctx, email)
+	if user == (domain.User{}) {
+		return domain.TwoFactorEnrollment{}, errors.New("user not found")
+	}
+	if user.TwoFactorEnabled {
+		return domain.TwoFactorEnrollment{}, errors.New("two-factor authentication is already enabled")
+	}
+
+	secret, err := infrastructure.GenerateTOTPSecret()
+	if err != nil {
+		return domain.TwoFactorEnrollment{}, errors.New("internal server error")
+	}
+	if err := tf.TwoFactor.SetPendingSecret(ctx, user.ID.Hex(), secret); err != nil {
+		return domain.TwoFactorEnrollment{}, err
+	}
+	return domain.TwoFactorEnrollment{
+		Secret:          secret,
+		ProvisioningURI: infrastructure.TOTPProvisioningURI(tf.Issuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmEnrollment turns 2FA on once the user proves their app produces
+// valid codes, and returns recovery codes. They are only stored hashed, so
+// this is the one time they can be shown.
+func (tf *TwoFactorUseCase) ConfirmEnrollment(ctx context.Context, email string, code string) ([]string, error) {
+	user := tf.Users.GetUserByEmail(ctx, email)
+	if user == (domain.User{}) {
+		return nil, errors.New("user not found")
+	}
+	state, err := tf.TwoFactor.GetTwoFactor(ctx, user.ID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	if state.PendingSecret == "" {
+		return nil, errors.New("no two-factor enrollment in progress")
+	}
+	if _, ok := infrastructure.ValidateTOTP(state.PendingSecret, normalizeCode(code), tf.Now()); !ok {
+		return nil, errors.New(invalidCodeMessage)
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		codes[i], err = generateRecoveryCode()
+		if err != nil {
+			return nil, errors.New("internal server error")
+		}
+		hashes[i] = infrastructure.HashToken(normalizeCode(codes[i]))
+	}
+	if err := tf.TwoFactor.EnableTwoFactor(ctx, user.ID.Hex(), state.PendingSecret, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Challenge issues the short-lived token a user with 2FA gets instead of a
+// JWT after entering the right password.
+func (tf *TwoFactorUseCase) Challenge(ctx context.Context, user *domain.User) (string, error) {
+	return issueToken(ctx, tf.Tokens, loginChallengePurpose, user.ID.Hex(), tf.ChallengeTTL, tf.Now())
+}
+
+// Verify redeems a challenge with a TOTP code or an unused recovery code
+// and returns the user who passed it. A challenge can only be tried once;
+// after a wrong code the user has to enter their password again. The user
+// is returned with that error too, so that the caller can count the failure
+// against the account.
+func (tf *TwoFactorUseCase) Verify(ctx context.Context, challenge string, code string) (domain.User, error) {
+	if challenge == "" || code == "" {
+		return domain.User{}, errors.New("required field missing")
+	}
+	now := tf.Now()
+	token, err := tf.Tokens.ConsumeToken(ctx, loginChallengePurpose, infrastructure.HashToken(challenge), now)
+	if err != nil {
+		if err.Error() == "internal server error" {
+			return domain.User{}, err
+		}
+		return domain.User{}, errors.New("invalid or expired challenge")
+	}
+
+	user, err := tf.Users.GetUserByID(ctx, token.UserID)
+	if err != nil {
+		return domain.User{}, err
+	}
+	state, err := tf.TwoFactor.GetTwoFactor(ctx, token.UserID)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	code = normalizeCode(code)
+	var ok bool
+	if step, valid := infrastructure.ValidateTOTP(state.Secret, code, now); valid {
+		ok, err = tf.TwoFactor.UseTimeStep(ctx, token.UserID, step)
+	} else if len(code) != infrastructure.TOTPDigits {
+		ok, err = tf.TwoFactor.UseRecoveryCode(ctx, token.UserID, infrastructure.HashToken(code))
+	}
+	if err != nil {
+		return domain.User{}, err
+	}
+	if !ok {
+		return user, errors.New(invalidCodeMessage)
+	}
+	return user, nil
+}
+
+// generateRecoveryCode returns 80 random bits as four dash separated groups
+// of base32, e.g. "7mqz-k2dp-vx4a-hn3e".
+func generateRecoveryCode() (string, error) {
+	buffer := make([]byte, 10)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+	encoded := strings.ToLower(recoveryCodeEncoding.EncodeToString(buffer))
+	return encoded[0:4] + "-" + encoded[4:8] + "-" + encoded[8:12] + "-" + encoded[12:16], nil
+}
+
+// normalizeCode drops the spaces and dashes people type or paste along with
+// codes, and case-folds recovery codes.
+func normalizeCode(code string) string {
+	code = strings.NewReplacer(" ", "", "-", "").Replace(code)
+	return strings.ToLower(code)
+}
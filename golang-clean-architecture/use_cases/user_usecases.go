@@ -1,31 +1,62 @@
 package use_cases
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"golang-clean-architecture/domain"
-	"golang-clean-architecture/infrastructure"
+	"log/slog"
+	"net/mail"
 	"strings"
+	"time"
+	// validating timezones shouldn't depend on the host's zoneinfo
+	_ "time/tzdata"
 	//"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type UserUseCase struct {
 	Repository	domain.UserRepository
+	Verification	domain.EmailVerificationUseCase
+	Guard		domain.LoginGuard
+	TwoFactor	domain.TwoFactorUseCase
+	Tokens		domain.TokenService
+	Hasher		domain.PasswordHasher
+	Policy		domain.PasswordPolicy
+	Events		domain.EventPublisher
+	RequireVerification	bool
+	Logger		*slog.Logger
 }
 
-func NewUserUseCase(ur domain.UserRepository) domain.UserUseCase {
+func NewUserUseCase(ur domain.UserRepository, verification domain.EmailVerificationUseCase, guard domain.LoginGuard, twoFactor domain.TwoFactorUseCase, tokens domain.TokenService, hasher domain.PasswordHasher, policy domain.PasswordPolicy, events domain.EventPublisher, requireVerification bool, logger *slog.Logger) domain.UserUseCase {
 	return &UserUseCase{
 		Repository : ur,
+		Verification : verification,
+		Guard : guard,
+		TwoFactor : twoFactor,
+		Tokens : tokens,
+		Hasher : hasher,
+		Policy : policy,
+		Events : events,
+		RequireVerification : requireVerification,
+		Logger : logger,
 	}
 }
 
-func (user  *UserUseCase) Register(newUser *domain.User) error {
+func (user  *UserUseCase) Register(ctx context.Context, newUser *domain.User) error {
 	
+	newUser.Email = normalizeEmail(newUser.Email)
 	if newUser.Email == "" || newUser.Password == "" {
 		return errors.New("required field missing")
 	}
+	if !validEmail(newUser.Email) {
+		return errors.New("invalid email address")
+	}
+	if err := user.Policy.Validate(newUser.Password, newUser.Email); err != nil {
+		return err
+	}
+	newUser.Verified = false
 
-	err := user.Repository.VerifyFirst(newUser)
+	err := user.Repository.VerifyFirst(ctx, newUser)
 	if err != nil {
 		if err.Error() == "a user is found on db" {
 			newUser.Role = "user"
@@ -37,53 +68,238 @@ func (user  *UserUseCase) Register(newUser *domain.User) error {
 		newUser.Role = "admin"
 	}
 
-	err = user.Repository.UserExists(newUser)
+	err = user.Repository.UserExists(ctx, newUser)
 	if err != nil {
 		return err
 	}
 
-	hashedPassword, err := infrastructure.HashPassword(newUser.Password)
+	hashedPassword, err := user.Hasher.Hash(newUser.Password)
 	if err != nil {
 		return errors.New("internal server error")
 	}
 
-	newUser.Password = string(hashedPassword)
-	err = user.Repository.Register(newUser)
+	newUser.Password = hashedPassword
+	err = user.Repository.Register(ctx, newUser)
 	if err != nil {
 		return err
 	}
 
+	// the account exists either way; a failed mail can be resent later
+	err = user.Verification.SendVerification(ctx, newUser)
+	if err != nil {
+		user.Logger.ErrorContext(ctx, "error while sending verification email", "user_id", newUser.ID.Hex(), "error", err)
+	}
 	return nil
 }
 
 
-func (user *UserUseCase) Login(userInfo *domain.User) (string, error){
+// Login checks the credentials of a user signing in from clientIP and
+// returns a token for them. Users with 2FA get a challenge instead, to be
+// completed with CompleteTwoFactorLogin.
+func (user *UserUseCase) Login(ctx context.Context, userInfo *domain.User, clientIP string) (domain.LoginResult, error){
 	userInfo.Password = strings.TrimSpace(userInfo.Password)
-	userInfo.Email = strings.TrimSpace(userInfo.Email)
+	userInfo.Email = normalizeEmail(userInfo.Email)
 	if userInfo.Password == "" || userInfo.Email == "" {
-		return "", errors.New("required fields are missing")
+		return domain.LoginResult{}, errors.New("required fields are missing")
+	}
+
+	now := time.Now()
+	if err := user.Guard.Check(ctx, userInfo.Email, clientIP, now); err != nil {
+		return domain.LoginResult{}, err
+	}
+
+	foundUser := user.Repository.GetUserByEmail(ctx, userInfo.Email)
+	if foundUser == (domain.User{}) || user.Hasher.Compare(foundUser.Password, userInfo.Password) != nil {
+		if err := user.Guard.RecordFailure(ctx, userInfo.Email, clientIP, now); err != nil {
+			return domain.LoginResult{}, err
+		}
+		return domain.LoginResult{}, errors.New("invalid credentials")
+	}
+	if user.Hasher.NeedsRehash(foundUser.Password) {
+		user.rehashPassword(ctx, &foundUser, userInfo.Password)
+	}
+
+	if user.RequireVerification && !foundUser.Verified {
+		return domain.LoginResult{}, errors.New("email address not verified")
+	}
+
+	// the account's failures are only cleared once the user gets a token,
+	// so that a known password doesn't reset the count of wrong 2FA codes
+	if foundUser.TwoFactorEnabled {
+		challenge, err := user.TwoFactor.Challenge(ctx, &foundUser)
+		if err != nil {
+			return domain.LoginResult{}, err
+		}
+		return domain.LoginResult{TwoFactorRequired : true, ChallengeToken : challenge}, nil
+	}
+
+	token, err := user.Tokens.IssueToken(&foundUser)
+	if err != nil {
+		return domain.LoginResult{}, errors.New("internal server error")
+	}
+	if err := user.Guard.RecordSuccess(ctx, foundUser.Email); err != nil {
+		return domain.LoginResult{}, err
+	}
+
+	return domain.LoginResult{Token : token}, nil
+}
+
+// rehashPassword upgrades the stored hash of a password that was just
+// verified. A failure only postpones the upgrade to the next login.
+func (user *UserUseCase) rehashPassword(ctx context.Context, foundUser *domain.User, password string) {
+	hashedPassword, err := user.Hasher.Hash(password)
+	if err != nil {
+		user.Logger.ErrorContext(ctx, "error while rehashing password", "user_id", foundUser.ID.Hex(), "error", err)
+		return
+	}
+	err = user.Repository.UpdatePasswordHash(ctx, foundUser.ID.Hex(), hashedPassword)
+	if err != nil {
+		user.Logger.ErrorContext(ctx, "error while rehashing password", "user_id", foundUser.ID.Hex(), "error", err)
+		return
+	}
+	foundUser.Password = hashedPassword
+}
+
+// CompleteTwoFactorLogin exchanges a login challenge and a TOTP or recovery
+// code, sent from clientIP, for a token. Wrong codes count as failed logins.
+func (user *UserUseCase) CompleteTwoFactorLogin(ctx context.Context, challenge string, code string, clientIP string) (domain.AccessToken, error) {
+	foundUser, err := user.TwoFactor.Verify(ctx, challenge, code)
+	if err != nil && err.Error() == invalidCodeMessage && foundUser.Email != "" {
+		if err := user.Guard.RecordFailure(ctx, foundUser.Email, clientIP, time.Now()); err != nil {
+			return domain.AccessToken{}, err
+		}
+	}
+	if err != nil {
+		return domain.AccessToken{}, err
+	}
+	token, err := user.Tokens.IssueToken(&foundUser)
+	if err != nil {
+		return domain.AccessToken{}, errors.New("internal server error")
+	}
+	if err := user.Guard.RecordSuccess(ctx, foundUser.Email); err != nil {
+		return domain.AccessToken{}, err
+	}
+	return token, nil
+}
+
+func (user *UserUseCase) PromoteUser(ctx context.Context, userID string) error {
+	err := user.Repository.PromoteUser(ctx, userID)
+	if err != nil {
+		return err
 	}
-	foundUser := user.Repository.GetUserByEmail(userInfo.Email)
+	user.Events.Publish(ctx, newEvent("user.promoted", map[string]string{"id" : userID}))
+	return nil
+}
+
+// UnlockAccount lifts a login lockout before it expires.
+func (user *UserUseCase) UnlockAccount(ctx context.Context, userID string) error {
+	foundUser, err := user.Repository.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	err = user.Guard.Unlock(ctx, foundUser.Email)
+	if err != nil {
+		return err
+	}
+	user.Events.Publish(ctx, newEvent("user.unlocked", map[string]string{"id" : userID, "email" : foundUser.Email}))
+	return nil
+}
+
+const maxDisplayNameLength = 64
+
+func (user *UserUseCase) GetCurrentUser(ctx context.Context, email string) (domain.User, error) {
+	foundUser := user.Repository.GetUserByEmail(ctx, email)
 	if foundUser == (domain.User{}) {
-		return "", errors.New("invalid credentials")
+		return domain.User{}, errors.New("user not found")
 	}
-	
-	validateUser := infrastructure.ComparePasswords(&foundUser, userInfo)
-	if validateUser != nil {
-		fmt.Println("here")
-		return "", errors.New("invalid credentials")
+	return foundUser, nil
+}
+
+func (user *UserUseCase) UpdateProfile(ctx context.Context, email string, update domain.ProfileUpdate) (domain.User, error) {
+	if update.DisplayName != nil {
+		displayName := strings.TrimSpace(*update.DisplayName)
+		if len([]rune(displayName)) > maxDisplayNameLength {
+			return domain.User{}, fmt.Errorf("display name must be at most %d characters long", maxDisplayNameLength)
+		}
+		update.DisplayName = &displayName
+	}
+	if update.Timezone != nil {
+		timezone := strings.TrimSpace(*update.Timezone)
+		// LoadLocation treats "" as UTC; here it clears the timezone instead
+		if timezone != "" {
+			if _, err := time.LoadLocation(timezone); err != nil || timezone == "Local" {
+				return domain.User{}, errors.New("invalid timezone")
+			}
+		}
+		update.Timezone = &timezone
+	}
+
+	foundUser, err := user.GetCurrentUser(ctx, email)
+	if err != nil {
+		return domain.User{}, err
+	}
+	err = user.Repository.UpdateProfile(ctx, foundUser.ID.Hex(), update)
+	if err != nil {
+		return domain.User{}, err
 	}
 
-	token, err := infrastructure.GenerateToken(&foundUser)
+	if update.DisplayName != nil {
+		foundUser.DisplayName = *update.DisplayName
+	}
+	if update.Timezone != nil {
+		foundUser.Timezone = *update.Timezone
+	}
+	return foundUser, nil
+}
+
+// ChangePassword replaces the password of a signed in user. Every other
+// session is revoked, so a fresh token for the caller is returned.
+func (user *UserUseCase) ChangePassword(ctx context.Context, email string, currentPassword string, newPassword string) (domain.AccessToken, error) {
+	if currentPassword == "" || newPassword == "" {
+		return domain.AccessToken{}, errors.New("required field missing")
+	}
+
+	foundUser, err := user.GetCurrentUser(ctx, email)
 	if err != nil {
-		fmt.Println("here")
-		return "", errors.New("internal server error")
+		return domain.AccessToken{}, err
+	}
+	if user.Hasher.Compare(foundUser.Password, currentPassword) != nil {
+		return domain.AccessToken{}, errors.New("current password is incorrect")
+	}
+	if err := user.Policy.Validate(newPassword, foundUser.Email); err != nil {
+		return domain.AccessToken{}, err
 	}
 
+	hashedPassword, err := user.Hasher.Hash(newPassword)
+	if err != nil {
+		return domain.AccessToken{}, errors.New("internal server error")
+	}
+	// see ResetPassword for why the revocation time is truncated
+	err = user.Repository.UpdatePassword(ctx, foundUser.ID.Hex(), hashedPassword, time.Now().Truncate(time.Second))
+	if err != nil {
+		return domain.AccessToken{}, err
+	}
+
+	token, err := user.Tokens.IssueToken(&foundUser)
+	if err != nil {
+		return domain.AccessToken{}, errors.New("internal server error")
+	}
 	return token, nil
 }
 
-func (user *UserUseCase) PromoteUser(userID string) error {
-	err := user.Repository.PromoteUser(userID)
-	return err
+// normalizeEmail trims and case-folds an email so that the same address
+// always maps to the same account.
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// validEmail accepts a bare address such as "jane@example.com". Display
+// names, anything else net/mail would normalise away and domains without a
+// dot are rejected.
+func validEmail(email string) bool {
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email {
+		return false
+	}
+	return strings.Contains(email[strings.LastIndex(email, "@"):], ".")
 }
\ No newline at end of file
@@ -0,0 +1,207 @@
+package use_cases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"golang-clean-architecture/domain"
+	"golang-clean-architecture/infrastructure"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// WebhookEventTypes lists the events a webhook can subscribe to.
+var WebhookEventTypes = map[string]bool{
+	"task.created":  true,
+	"task.updated":  true,
+	"task.deleted":  true,
+	"user.promoted": true,
+	"user.locked_out": true,
+	"user.unlocked": true,
+	"login.ip_blocked": true,
+}
+
+const (
+	maxDeliveriesPerRun = 100
+	deliveryLease       = time.Minute
+)
+
+type WebhookUseCase struct {
+	Repository  domain.WebhookRepository
+	Sender      domain.WebhookSender
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	Logger      *slog.Logger
+}
+
+func NewWebhookUseCase(wr domain.WebhookRepository, sender domain.WebhookSender, logger *slog.Logger) domain.WebhookUseCase {
+	return &WebhookUseCase{
+		Repository:  wr,
+		Sender:      sender,
+		MaxAttempts: 8,
+		BaseBackoff: 30 * time.Second,
+		MaxBackoff:  time.Hour,
+		Logger:      logger,
+	}
+}
+
+func (wu *WebhookUseCase) CreateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	subscription.URL = strings.TrimSpace(subscription.URL)
+	parsedURL, err := url.Parse(subscription.URL)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+		return errors.New("webhook url must be an absolute http(s) url")
+	}
+
+	if len(subscription.EventTypes) == 0 {
+		return errors.New("at least one event type is required")
+	}
+	for _, eventType := range subscription.EventTypes {
+		if !WebhookEventTypes[eventType] {
+			return errors.New("unsupported event type " + eventType)
+		}
+	}
+
+	if subscription.Secret == "" {
+		secret, err := infrastructure.GenerateRandomToken(32)
+		if err != nil {
+			return errors.New("internal server error")
+		}
+		subscription.Secret = secret
+	}
+	subscription.CreatedAt = time.Now().UTC()
+
+	err = wu.Repository.CreateSubscription(ctx, subscription)
+	return err
+}
+
+func (wu *WebhookUseCase) GetSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	subscriptions, err := wu.Repository.GetSubscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// the secret is only ever shown when the subscription is created
+	for _, subscription := range subscriptions {
+		subscription.Secret = ""
+	}
+	return subscriptions, nil
+}
+
+func (wu *WebhookUseCase) DeleteSubscription(ctx context.Context, subscriptionID string) error {
+	err := wu.Repository.DeleteSubscription(ctx, subscriptionID)
+	return err
+}
+
+func (wu *WebhookUseCase) GetDeliveries(ctx context.Context, subscriptionID string) ([]*domain.WebhookDelivery, error) {
+	if _, err := wu.Repository.GetSubscription(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+	deliveries, err := wu.Repository.GetDeliveries(ctx, subscriptionID)
+	return deliveries, err
+}
+
+// Publish queues a delivery of the event for every subscription interested in
+// it. Failures are logged rather than returned so that a webhook problem never
+// fails the request that caused the event.
+func (wu *WebhookUseCase) Publish(ctx context.Context, event domain.Event) {
+	if !WebhookEventTypes[event.Type] {
+		return
+	}
+
+	subscriptions, err := wu.Repository.GetSubscriptionsForEvent(ctx, event.Type)
+	if err != nil {
+		wu.Logger.ErrorContext(ctx, "error while fetching webhooks", "event_type", event.Type, "error", err)
+		return
+	}
+	if len(subscriptions) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		wu.Logger.ErrorContext(ctx, "error while encoding webhook payload", "event_type", event.Type, "error", err)
+		return
+	}
+
+	now := time.Now().UTC()
+	for _, subscription := range subscriptions {
+		err := wu.Repository.CreateDelivery(ctx, &domain.WebhookDelivery{
+			SubscriptionID: subscription.ID.Hex(),
+			EventID:        event.ID,
+			EventType:      event.Type,
+			Payload:        string(payload),
+			Status:         "pending",
+			Attempts:       []domain.WebhookAttempt{},
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+		})
+		if err != nil {
+			wu.Logger.ErrorContext(ctx, "error while queueing webhook delivery", "event_type", event.Type, "subscription_id", subscription.ID.Hex(), "error", err)
+		}
+	}
+}
+
+// DeliverPending sends every delivery that is due. Failed deliveries are
+// retried with exponential backoff until MaxAttempts is reached.
+func (wu *WebhookUseCase) DeliverPending(ctx context.Context, now time.Time) error {
+	for i := 0; i < maxDeliveriesPerRun; i++ {
+		delivery, err := wu.Repository.ClaimPendingDelivery(ctx, now, deliveryLease)
+		if err != nil {
+			return err
+		}
+		if delivery == nil {
+			return nil
+		}
+		if err := wu.deliver(ctx, delivery); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (wu *WebhookUseCase) deliver(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	attempt := domain.WebhookAttempt{AttemptedAt: time.Now().UTC()}
+
+	subscription, err := wu.Repository.GetSubscription(ctx, delivery.SubscriptionID)
+	if err != nil && err.Error() == "webhook with the specified id not found" {
+		attempt.Error = "webhook subscription no longer exists"
+		return wu.Repository.RecordAttempt(ctx, delivery.ID.Hex(), attempt, "failed", attempt.AttemptedAt)
+	}
+	if err != nil {
+		// the lease runs out and the delivery is picked up again later
+		return err
+	}
+
+	statusCode, err := wu.Sender.Send(&subscription, delivery)
+	attempt.StatusCode = statusCode
+	attempt.DurationMS = time.Since(attempt.AttemptedAt).Milliseconds()
+	if err != nil {
+		attempt.Error = err.Error()
+	} else if statusCode < 200 || statusCode > 299 {
+		attempt.Error = "unexpected response status"
+	}
+
+	if attempt.Error == "" {
+		return wu.Repository.RecordAttempt(ctx, delivery.ID.Hex(), attempt, "succeeded", attempt.AttemptedAt)
+	}
+
+	attempts := len(delivery.Attempts) + 1
+	if attempts >= wu.MaxAttempts {
+		return wu.Repository.RecordAttempt(ctx, delivery.ID.Hex(), attempt, "failed", attempt.AttemptedAt)
+	}
+	return wu.Repository.RecordAttempt(ctx, delivery.ID.Hex(), attempt, "pending", attempt.AttemptedAt.Add(wu.backoff(attempts)))
+}
+
+// backoff doubles the wait after every failed attempt, capped at MaxBackoff.
+func (wu *WebhookUseCase) backoff(attempts int) time.Duration {
+	wait := wu.BaseBackoff
+	for i := 1; i < attempts && wait < wu.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > wu.MaxBackoff {
+		wait = wu.MaxBackoff
+	}
+	return wait
+}
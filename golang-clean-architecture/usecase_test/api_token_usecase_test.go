@@ -0,0 +1,105 @@
+package usecase_test
+
+import (
+	"context"
+	"golang-clean-architecture/domain"
+	"golang-clean-architecture/domain/mocks"
+	"golang-clean-architecture/infrastructure"
+	"golang-clean-architecture/use_cases"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type APITokenTestSuite struct {
+	suite.Suite
+	mockUsers  *mocks.UserRepository
+	mockTokens *mocks.APITokenRepository
+	useCase    *use_cases.APITokenUseCase
+	now        time.Time
+	user       domain.User
+}
+
+func (suite *APITokenTestSuite) SetupTest() {
+	suite.mockUsers = new(mocks.UserRepository)
+	suite.mockTokens = new(mocks.APITokenRepository)
+	suite.now = time.Date(2024, 8, 10, 9, 30, 0, 0, time.UTC)
+	suite.useCase = use_cases.NewAPITokenUseCase(suite.mockUsers, suite.mockTokens, discardLogger).(*use_cases.APITokenUseCase)
+	suite.useCase.Now = func() time.Time { return suite.now }
+	suite.user = domain.User{ID: primitive.NewObjectID(), Email: "kidusm3l@gmail.com", Role: "user"}
+}
+
+func (suite *APITokenTestSuite) TestCreateToken_StoresOnlyTheHash() {
+	suite.mockUsers.On("GetUserByEmail", mock.Anything, "kidusm3l@gmail.com").Return(suite.user)
+	suite.mockTokens.On("CreateAPIToken", mock.Anything, mock.Anything).Return(nil)
+
+	token := &domain.APIToken{Name: " ci ", Scopes: []string{"tasks:read", "tasks:read", "tasks:write"}}
+	secret, err := suite.useCase.CreateToken(context.Background(), "kidusm3l@gmail.com", token)
+	suite.NoError(err)
+	suite.True(strings.HasPrefix(secret, "tm_"))
+	suite.Equal("ci", token.Name)
+	suite.Equal([]string{"tasks:read", "tasks:write"}, token.Scopes)
+	suite.Equal(suite.user.ID.Hex(), token.UserID)
+	suite.Equal(infrastructure.HashToken(secret), token.TokenHash)
+	suite.True(strings.HasPrefix(secret, token.Prefix))
+	suite.Less(len(token.Prefix), len(secret))
+}
+
+func (suite *APITokenTestSuite) TestCreateToken_Validation() {
+	past := suite.now.Add(-time.Hour)
+	cases := map[string]*domain.APIToken{
+		"token name is required":         {Scopes: []string{"tasks:read"}},
+		"at least one scope is required": {Name: "ci"},
+		"unsupported scope tokens:write": {Name: "ci", Scopes: []string{"tokens:write"}},
+		"expiry must be in the future":   {Name: "ci", Scopes: []string{"tasks:read"}, ExpiresAt: &past},
+	}
+	for expected, token := range cases {
+		_, err := suite.useCase.CreateToken(context.Background(), "kidusm3l@gmail.com", token)
+		suite.EqualError(err, expected)
+	}
+	suite.mockTokens.AssertNotCalled(suite.T(), "CreateAPIToken", mock.Anything, mock.Anything)
+}
+
+func (suite *APITokenTestSuite) TestAuthenticate() {
+	token := domain.APIToken{ID: primitive.NewObjectID(), UserID: suite.user.ID.Hex(), Scopes: []string{"tasks:read"}}
+	suite.mockTokens.On("GetAPITokenByHash", mock.Anything, infrastructure.HashToken("tm_secret")).Return(token, nil)
+	suite.mockUsers.On("GetUserByID", mock.Anything, suite.user.ID.Hex()).Return(suite.user, nil)
+	suite.mockTokens.On("TouchAPIToken", mock.Anything, token.ID.Hex(), suite.now).Return(nil)
+
+	authUser, err := suite.useCase.Authenticate(context.Background(), "tm_secret")
+	suite.NoError(err)
+	suite.Equal("kidusm3l@gmail.com", authUser.Email)
+	suite.Equal("user", authUser.Role)
+	suite.Equal(token.ID.Hex(), authUser.APITokenID)
+	suite.Equal([]string{"tasks:read"}, authUser.Scopes)
+	suite.mockTokens.AssertCalled(suite.T(), "TouchAPIToken", mock.Anything, token.ID.Hex(), suite.now)
+}
+
+func (suite *APITokenTestSuite) TestAuthenticate_RecentUseIsNotRewritten() {
+	lastUsed := suite.now.Add(-10 * time.Second)
+	token := domain.APIToken{ID: primitive.NewObjectID(), UserID: suite.user.ID.Hex(), LastUsedAt: &lastUsed}
+	suite.mockTokens.On("GetAPITokenByHash", mock.Anything, mock.Anything).Return(token, nil)
+	suite.mockUsers.On("GetUserByID", mock.Anything, suite.user.ID.Hex()).Return(suite.user, nil)
+
+	_, err := suite.useCase.Authenticate(context.Background(), "tm_secret")
+	suite.NoError(err)
+	suite.mockTokens.AssertNotCalled(suite.T(), "TouchAPIToken", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *APITokenTestSuite) TestAuthenticate_ExpiredToken() {
+	expiresAt := suite.now
+	token := domain.APIToken{ID: primitive.NewObjectID(), UserID: suite.user.ID.Hex(), ExpiresAt: &expiresAt}
+	suite.mockTokens.On("GetAPITokenByHash", mock.Anything, mock.Anything).Return(token, nil)
+
+	_, err := suite.useCase.Authenticate(context.Background(), "tm_secret")
+	suite.EqualError(err, "invalid api token")
+	suite.mockUsers.AssertNotCalled(suite.T(), "GetUserByID", mock.Anything, mock.Anything)
+}
+
+func TestAPITokenTestSuite(t *testing.T) {
+	suite.Run(t, new(APITokenTestSuite))
+}
@@ -0,0 +1,114 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"golang-clean-architecture/domain"
+	"golang-clean-architecture/domain/mocks"
+	"golang-clean-architecture/infrastructure"
+	"golang-clean-architecture/use_cases"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type EmailVerificationTestSuite struct {
+	suite.Suite
+	mockUsers  *mocks.UserRepository
+	mockTokens *mocks.OneTimeTokenRepository
+	mailer     *infrastructure.FakeMailer
+	useCase    *use_cases.EmailVerificationUseCase
+	now        time.Time
+	user       domain.User
+}
+
+func (suite *EmailVerificationTestSuite) SetupTest() {
+	suite.mockUsers = new(mocks.UserRepository)
+	suite.mockTokens = new(mocks.OneTimeTokenRepository)
+	suite.mailer = &infrastructure.FakeMailer{}
+	suite.now = time.Date(2024, 8, 10, 9, 30, 0, 0, time.UTC)
+	suite.useCase = use_cases.NewEmailVerificationUseCase(suite.mockUsers, suite.mockTokens, suite.mailer, 24*time.Hour, "http://localhost:8080/verify", discardLogger).(*use_cases.EmailVerificationUseCase)
+	suite.useCase.Now = func() time.Time { return suite.now }
+	suite.user = domain.User{ID: primitive.NewObjectID(), Email: "kidusm3l@gmail.com", Role: "user"}
+}
+
+func (suite *EmailVerificationTestSuite) TestSendVerification_MailsLink() {
+	suite.mockTokens.On("CreateToken", mock.Anything, mock.Anything).Return(nil)
+
+	err := suite.useCase.SendVerification(context.Background(), &suite.user)
+	suite.NoError(err)
+
+	mails := suite.mailer.Sent()
+	suite.Require().Len(mails, 1)
+	suite.Equal("kidusm3l@gmail.com", mails[0].To)
+	suite.Contains(mails[0].Body, "http://localhost:8080/verify?token=")
+	suite.mockTokens.AssertCalled(suite.T(), "CreateToken", mock.Anything, mock.MatchedBy(func(stored *domain.OneTimeToken) bool {
+		return stored.Purpose == "email_verification" &&
+			stored.UserID == suite.user.ID.Hex() &&
+			!strings.Contains(mails[0].Body, stored.TokenHash) &&
+			stored.ExpiresAt.Equal(suite.now.Add(24*time.Hour))
+	}))
+}
+
+func (suite *EmailVerificationTestSuite) TestSendVerification_MailerFailure() {
+	suite.mailer.Err = errors.New("error while sending mail")
+	suite.mockTokens.On("CreateToken", mock.Anything, mock.Anything).Return(nil)
+
+	err := suite.useCase.SendVerification(context.Background(), &suite.user)
+	suite.EqualError(err, "error while sending verification email")
+}
+
+func (suite *EmailVerificationTestSuite) TestVerifyEmail_MarksUserVerified() {
+	suite.mockTokens.On("ConsumeToken", mock.Anything, "email_verification", infrastructure.HashToken("4f1c0a"), suite.now).
+		Return(domain.OneTimeToken{UserID: suite.user.ID.Hex()}, nil)
+	suite.mockUsers.On("MarkVerified", mock.Anything, suite.user.ID.Hex()).Return(nil)
+	suite.mockTokens.On("RevokeTokens", mock.Anything, suite.user.ID.Hex(), "email_verification").Return(nil)
+
+	err := suite.useCase.VerifyEmail(context.Background(), "4f1c0a")
+	suite.NoError(err)
+	suite.mockUsers.AssertExpectations(suite.T())
+	suite.mockTokens.AssertExpectations(suite.T())
+}
+
+func (suite *EmailVerificationTestSuite) TestVerifyEmail_InvalidToken() {
+	suite.mockTokens.On("ConsumeToken", mock.Anything, "email_verification", mock.Anything, suite.now).
+		Return(domain.OneTimeToken{}, errors.New("invalid or expired token"))
+
+	err := suite.useCase.VerifyEmail(context.Background(), "expired")
+	suite.EqualError(err, "invalid or expired token")
+	suite.mockUsers.AssertNotCalled(suite.T(), "MarkVerified", mock.Anything, mock.Anything)
+
+	err = suite.useCase.VerifyEmail(context.Background(), "")
+	suite.EqualError(err, "required field missing")
+}
+
+func (suite *EmailVerificationTestSuite) TestResendVerification_ReplacesOutstandingLinks() {
+	suite.mockUsers.On("GetUserByEmail", mock.Anything, "kidusm3l@gmail.com").Return(suite.user)
+	suite.mockTokens.On("RevokeTokens", mock.Anything, suite.user.ID.Hex(), "email_verification").Return(nil)
+	suite.mockTokens.On("CreateToken", mock.Anything, mock.Anything).Return(nil)
+
+	err := suite.useCase.ResendVerification(context.Background(), " KidusM3l@gmail.com")
+	suite.NoError(err)
+	suite.Len(suite.mailer.Sent(), 1)
+	suite.mockTokens.AssertExpectations(suite.T())
+}
+
+func (suite *EmailVerificationTestSuite) TestResendVerification_SilentForUnknownOrVerified() {
+	verified := suite.user
+	verified.Verified = true
+	suite.mockUsers.On("GetUserByEmail", mock.Anything, "kidusm3l@gmail.com").Return(verified)
+	suite.mockUsers.On("GetUserByEmail", mock.Anything, "nobody@example.com").Return(domain.User{})
+
+	suite.NoError(suite.useCase.ResendVerification(context.Background(), "kidusm3l@gmail.com"))
+	suite.NoError(suite.useCase.ResendVerification(context.Background(), "nobody@example.com"))
+	suite.Empty(suite.mailer.Sent())
+	suite.mockTokens.AssertNotCalled(suite.T(), "CreateToken", mock.Anything, mock.Anything)
+}
+
+func TestEmailVerificationTestSuite(t *testing.T) {
+	suite.Run(t, new(EmailVerificationTestSuite))
+}
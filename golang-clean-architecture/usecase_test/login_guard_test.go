@@ -0,0 +1,108 @@
+package usecase_test
+
+import (
+	"context"
+	"golang-clean-architecture/domain"
+	"golang-clean-architecture/domain/mocks"
+	"golang-clean-architecture/infrastructure"
+	"golang-clean-architecture/use_cases"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type LoginGuardTestSuite struct {
+	suite.Suite
+	mockEvents *mocks.EventPublisher
+	guard      domain.LoginGuard
+	now        time.Time
+}
+
+func (suite *LoginGuardTestSuite) SetupTest() {
+	suite.mockEvents = new(mocks.EventPublisher)
+	suite.mockEvents.On("Publish", mock.Anything, mock.Anything).Return()
+	policy := use_cases.LoginGuardPolicy{
+		MaxAccountFailures: 3,
+		MaxIPFailures:      5,
+		Window:             15 * time.Minute,
+		Lockout:            10 * time.Minute,
+		BaseDelay:          time.Second,
+		MaxDelay:           3 * time.Second,
+	}
+	suite.guard = use_cases.NewLoginGuard(infrastructure.NewMemoryLoginAttemptStore(time.Hour), suite.mockEvents, policy, discardLogger)
+	suite.now = time.Date(2024, 8, 10, 9, 0, 0, 0, time.UTC)
+}
+
+func retryAfter(err error) time.Duration {
+	if retryErr, ok := err.(*domain.RetryError); ok {
+		return retryErr.RetryAfter
+	}
+	return 0
+}
+
+func (suite *LoginGuardTestSuite) TestProgressiveDelay() {
+	suite.NoError(suite.guard.RecordFailure(context.Background(), "kidusm3l@gmail.com", "203.0.113.7", suite.now))
+	suite.Equal(time.Second, retryAfter(suite.guard.Check(context.Background(), "kidusm3l@gmail.com", "203.0.113.7", suite.now)))
+	suite.NoError(suite.guard.Check(context.Background(), "kidusm3l@gmail.com", "203.0.113.7", suite.now.Add(time.Second)))
+
+	suite.now = suite.now.Add(time.Second)
+	suite.NoError(suite.guard.RecordFailure(context.Background(), "kidusm3l@gmail.com", "203.0.113.7", suite.now))
+	suite.Equal(2*time.Second, retryAfter(suite.guard.Check(context.Background(), "kidusm3l@gmail.com", "203.0.113.7", suite.now)))
+
+	// other accounts aren't slowed down
+	suite.NoError(suite.guard.Check(context.Background(), "other@example.com", "203.0.113.7", suite.now))
+}
+
+func (suite *LoginGuardTestSuite) TestAccountLockoutAndUnlock() {
+	for i := 0; i < 3; i++ {
+		suite.NoError(suite.guard.RecordFailure(context.Background(), "kidusm3l@gmail.com", "203.0.113.7", suite.now))
+		suite.now = suite.now.Add(5 * time.Second)
+	}
+
+	err := suite.guard.Check(context.Background(), "kidusm3l@gmail.com", "198.51.100.1", suite.now)
+	suite.EqualError(err, "too many failed login attempts, try again later")
+	suite.Equal(10*time.Minute-5*time.Second, retryAfter(err))
+	suite.mockEvents.AssertCalled(suite.T(), "Publish", mock.Anything, mock.MatchedBy(func(event domain.Event) bool {
+		return event.Type == "user.locked_out"
+	}))
+
+	suite.NoError(suite.guard.Unlock(context.Background(), "kidusm3l@gmail.com"))
+	suite.NoError(suite.guard.Check(context.Background(), "kidusm3l@gmail.com", "198.51.100.1", suite.now))
+}
+
+func (suite *LoginGuardTestSuite) TestLockoutExpires() {
+	for i := 0; i < 3; i++ {
+		suite.NoError(suite.guard.RecordFailure(context.Background(), "kidusm3l@gmail.com", "203.0.113.7", suite.now))
+	}
+	suite.Error(suite.guard.Check(context.Background(), "kidusm3l@gmail.com", "203.0.113.7", suite.now.Add(9*time.Minute)))
+	suite.NoError(suite.guard.Check(context.Background(), "kidusm3l@gmail.com", "203.0.113.7", suite.now.Add(10*time.Minute)))
+}
+
+func (suite *LoginGuardTestSuite) TestIPBlockedAcrossAccounts() {
+	for _, email := range []string{"a@example.com", "b@example.com", "c@example.com", "d@example.com", "e@example.com"} {
+		suite.NoError(suite.guard.RecordFailure(context.Background(), email, "203.0.113.7", suite.now))
+	}
+
+	suite.Error(suite.guard.Check(context.Background(), "f@example.com", "203.0.113.7", suite.now))
+	suite.NoError(suite.guard.Check(context.Background(), "f@example.com", "198.51.100.1", suite.now))
+	suite.mockEvents.AssertCalled(suite.T(), "Publish", mock.Anything, mock.MatchedBy(func(event domain.Event) bool {
+		return event.Type == "login.ip_blocked"
+	}))
+}
+
+func (suite *LoginGuardTestSuite) TestSuccessResetsAccountFailures() {
+	suite.NoError(suite.guard.RecordFailure(context.Background(), "kidusm3l@gmail.com", "203.0.113.7", suite.now))
+	suite.NoError(suite.guard.RecordFailure(context.Background(), "kidusm3l@gmail.com", "203.0.113.7", suite.now))
+	suite.NoError(suite.guard.RecordSuccess(context.Background(), "kidusm3l@gmail.com"))
+
+	// two more failures would have locked the account without the reset
+	suite.NoError(suite.guard.RecordFailure(context.Background(), "kidusm3l@gmail.com", "203.0.113.7", suite.now))
+	suite.NoError(suite.guard.RecordFailure(context.Background(), "kidusm3l@gmail.com", "203.0.113.7", suite.now))
+	suite.NoError(suite.guard.Check(context.Background(), "kidusm3l@gmail.com", "203.0.113.7", suite.now.Add(time.Minute)))
+}
+
+func TestLoginGuardTestSuite(t *testing.T) {
+	suite.Run(t, new(LoginGuardTestSuite))
+}
@@ -0,0 +1,152 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"golang-clean-architecture/domain"
+	"golang-clean-architecture/domain/mocks"
+	"golang-clean-architecture/infrastructure"
+	"golang-clean-architecture/use_cases"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type PasswordResetTestSuite struct {
+	suite.Suite
+	mockUsers  *mocks.UserRepository
+	mockTokens *mocks.OneTimeTokenRepository
+	mailer     *infrastructure.FakeMailer
+	hasher     domain.PasswordHasher
+	useCase    *use_cases.PasswordResetUseCase
+	now        time.Time
+	user       domain.User
+}
+
+func (suite *PasswordResetTestSuite) SetupTest() {
+	suite.mockUsers = new(mocks.UserRepository)
+	suite.mockTokens = new(mocks.OneTimeTokenRepository)
+	suite.mailer = &infrastructure.FakeMailer{}
+	suite.hasher = newTestHasher()
+	suite.now = time.Date(2024, 8, 10, 9, 30, 15, 500, time.UTC)
+	suite.useCase = use_cases.NewPasswordResetUseCase(suite.mockUsers, suite.mockTokens, suite.mailer, suite.hasher, infrastructure.NewPasswordPolicy(infrastructure.DefaultPasswordRules()), time.Hour, "https://app.example.com/reset?lang=en", discardLogger).(*use_cases.PasswordResetUseCase)
+	suite.useCase.Now = func() time.Time { return suite.now }
+	suite.user = domain.User{ID: primitive.NewObjectID(), Email: "kidusm3l@gmail.com", Role: "user"}
+}
+
+func (suite *PasswordResetTestSuite) TestForgotPassword_MailsSingleUseToken() {
+	suite.mockUsers.On("GetUserByEmail", mock.Anything, "kidusm3l@gmail.com").Return(suite.user)
+	suite.mockTokens.On("CreateToken", mock.Anything, mock.Anything).Return(nil)
+
+	err := suite.useCase.ForgotPassword(context.Background(), "  kidusm3l@gmail.com ")
+	suite.NoError(err)
+
+	mails := suite.mailer.Sent()
+	suite.Require().Len(mails, 1)
+	suite.Equal("kidusm3l@gmail.com", mails[0].To)
+
+	// the link carries the raw token; only its hash is stored
+	var link *url.URL
+	for _, field := range strings.Fields(mails[0].Body) {
+		if strings.HasPrefix(field, "https://app.example.com/reset") {
+			link, err = url.Parse(field)
+			suite.Require().NoError(err)
+		}
+	}
+	suite.Require().NotNil(link)
+	suite.Equal("en", link.Query().Get("lang"))
+	token := link.Query().Get("token")
+	suite.Len(token, 64)
+
+	suite.mockTokens.AssertCalled(suite.T(), "CreateToken", mock.Anything, mock.MatchedBy(func(stored *domain.OneTimeToken) bool {
+		return stored.Purpose == "password_reset" &&
+			stored.UserID == suite.user.ID.Hex() &&
+			stored.TokenHash == infrastructure.HashToken(token) &&
+			stored.TokenHash != token &&
+			stored.ExpiresAt.Equal(suite.now.Add(time.Hour))
+	}))
+}
+
+func (suite *PasswordResetTestSuite) TestForgotPassword_UnknownEmailIsNotRevealed() {
+	suite.mockUsers.On("GetUserByEmail", mock.Anything, "nobody@example.com").Return(domain.User{})
+
+	err := suite.useCase.ForgotPassword(context.Background(), "nobody@example.com")
+	suite.NoError(err)
+	suite.Empty(suite.mailer.Sent())
+	suite.mockTokens.AssertNotCalled(suite.T(), "CreateToken", mock.Anything, mock.Anything)
+}
+
+func (suite *PasswordResetTestSuite) TestForgotPassword_MailerFailureIsNotRevealed() {
+	suite.mailer.Err = errors.New("error while sending mail")
+	suite.mockUsers.On("GetUserByEmail", mock.Anything, "kidusm3l@gmail.com").Return(suite.user)
+	suite.mockTokens.On("CreateToken", mock.Anything, mock.Anything).Return(nil)
+
+	err := suite.useCase.ForgotPassword(context.Background(), "kidusm3l@gmail.com")
+	suite.NoError(err)
+}
+
+func (suite *PasswordResetTestSuite) TestForgotPassword_MissingEmail() {
+	err := suite.useCase.ForgotPassword(context.Background(), "   ")
+	suite.EqualError(err, "required field missing")
+}
+
+func (suite *PasswordResetTestSuite) TestResetPassword_RehashesAndRevokesSessions() {
+	token := "4f1c0a"
+	suite.mockTokens.On("GetToken", mock.Anything, "password_reset", infrastructure.HashToken(token), suite.now).
+		Return(domain.OneTimeToken{UserID: suite.user.ID.Hex()}, nil)
+	suite.mockTokens.On("ConsumeToken", mock.Anything, "password_reset", infrastructure.HashToken(token), suite.now).
+		Return(domain.OneTimeToken{UserID: suite.user.ID.Hex()}, nil)
+	suite.mockUsers.On("GetUserByID", mock.Anything, suite.user.ID.Hex()).Return(suite.user, nil)
+	suite.mockUsers.On("UpdatePassword", mock.Anything, suite.user.ID.Hex(), mock.Anything, suite.now.Truncate(time.Second)).Return(nil)
+	suite.mockTokens.On("RevokeTokens", mock.Anything, suite.user.ID.Hex(), "password_reset").Return(nil)
+
+	err := suite.useCase.ResetPassword(context.Background(), token, "correct horse battery")
+	suite.NoError(err)
+
+	suite.mockUsers.AssertCalled(suite.T(), "UpdatePassword", mock.Anything, suite.user.ID.Hex(), mock.MatchedBy(func(hash string) bool {
+		return suite.hasher.Compare(hash, "correct horse battery") == nil
+	}), suite.now.Truncate(time.Second))
+	suite.mockTokens.AssertExpectations(suite.T())
+}
+
+func (suite *PasswordResetTestSuite) TestResetPassword_InvalidToken() {
+	suite.mockTokens.On("GetToken", mock.Anything, "password_reset", mock.Anything, suite.now).
+		Return(domain.OneTimeToken{}, errors.New("invalid or expired token"))
+
+	err := suite.useCase.ResetPassword(context.Background(), "used-or-expired", "correct horse battery")
+	suite.EqualError(err, "invalid or expired token")
+	suite.mockUsers.AssertNotCalled(suite.T(), "UpdatePassword", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *PasswordResetTestSuite) TestResetPassword_PolicyCheckedBeforeTokenIsSpent() {
+	suite.mockTokens.On("GetToken", mock.Anything, "password_reset", mock.Anything, suite.now).
+		Return(domain.OneTimeToken{UserID: suite.user.ID.Hex()}, nil)
+	suite.mockUsers.On("GetUserByID", mock.Anything, suite.user.ID.Hex()).Return(suite.user, nil)
+
+	err := suite.useCase.ResetPassword(context.Background(), "4f1c0a", "short")
+	suite.EqualError(err, "password must be at least 8 characters long")
+
+	err = suite.useCase.ResetPassword(context.Background(), "4f1c0a", strings.Repeat("a", 73))
+	suite.EqualError(err, "password must be at most 72 bytes long")
+	suite.mockTokens.AssertNotCalled(suite.T(), "ConsumeToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *PasswordResetTestSuite) TestResetPassword_EmailAsPasswordRefused() {
+	suite.mockTokens.On("GetToken", mock.Anything, "password_reset", mock.Anything, suite.now).
+		Return(domain.OneTimeToken{UserID: suite.user.ID.Hex()}, nil)
+	suite.mockUsers.On("GetUserByID", mock.Anything, suite.user.ID.Hex()).Return(suite.user, nil)
+
+	err := suite.useCase.ResetPassword(context.Background(), "4f1c0a", "KidusM3L@gmail.com")
+	suite.EqualError(err, "password must not be your email address")
+	suite.mockTokens.AssertNotCalled(suite.T(), "ConsumeToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	suite.mockUsers.AssertNotCalled(suite.T(), "UpdatePassword", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPasswordResetTestSuite(t *testing.T) {
+	suite.Run(t, new(PasswordResetTestSuite))
+}
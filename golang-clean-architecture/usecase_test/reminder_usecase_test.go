@@ -0,0 +1,94 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"golang-clean-architecture/domain"
+	"golang-clean-architecture/domain/mocks"
+	"golang-clean-architecture/infrastructure"
+	"golang-clean-architecture/use_cases"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ReminderTestSuite struct {
+	suite.Suite
+	mockRepo *mocks.TaskRepository
+	notifier *infrastructure.FakeNotifier
+	useCase  domain.ReminderUseCase
+	now      time.Time
+}
+
+func (suite *ReminderTestSuite) SetupTest() {
+	suite.mockRepo = new(mocks.TaskRepository)
+	suite.notifier = &infrastructure.FakeNotifier{}
+	suite.useCase = use_cases.NewReminderUseCase(suite.mockRepo, suite.notifier, []time.Duration{time.Hour, 24 * time.Hour})
+	suite.now = time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC)
+}
+
+func (suite *ReminderTestSuite) TestOverdueTaskIsMarkedAndReported() {
+	task := &domain.Task{ID: primitive.NewObjectID(), Title: "File taxes", DueDate: suite.now.Add(-time.Minute), Status: "pending"}
+	taskID := task.ID.Hex()
+	suite.mockRepo.On("GetTasksDueBefore", mock.Anything, suite.now.Add(24*time.Hour), "overdue").Return([]*domain.Task{task}, nil)
+	suite.mockRepo.On("UpdateTask", mock.Anything, taskID, &domain.Task{Status: "overdue"}).Return(nil)
+	suite.mockRepo.On("MarkReminderSent", mock.Anything, taskID, "overdue").Return(true, nil)
+
+	err := suite.useCase.CheckDueTasks(context.Background(), suite.now)
+	suite.NoError(err)
+	suite.Len(suite.notifier.Sent(), 1)
+	suite.Equal("task.overdue", suite.notifier.Sent()[0].Type)
+	suite.mockRepo.AssertCalled(suite.T(), "UpdateTask", mock.Anything, taskID, &domain.Task{Status: "overdue"})
+}
+
+func (suite *ReminderTestSuite) TestOnlyTightestWindowIsReminded() {
+	task := &domain.Task{ID: primitive.NewObjectID(), Title: "Standup", DueDate: suite.now.Add(30 * time.Minute), Status: "pending"}
+	taskID := task.ID.Hex()
+	suite.mockRepo.On("GetTasksDueBefore", mock.Anything, suite.now.Add(24*time.Hour), "overdue").Return([]*domain.Task{task}, nil)
+	suite.mockRepo.On("MarkReminderSent", mock.Anything, taskID, "due:1h0m0s").Return(true, nil)
+
+	err := suite.useCase.CheckDueTasks(context.Background(), suite.now)
+	suite.NoError(err)
+	suite.Len(suite.notifier.Sent(), 1)
+	suite.Equal("task.due_soon", suite.notifier.Sent()[0].Type)
+	suite.mockRepo.AssertNotCalled(suite.T(), "MarkReminderSent", mock.Anything, taskID, "due:24h0m0s")
+}
+
+func (suite *ReminderTestSuite) TestAlreadySentReminderIsSkipped() {
+	task := &domain.Task{ID: primitive.NewObjectID(), Title: "Standup", DueDate: suite.now.Add(2 * time.Hour), Status: "pending"}
+	suite.mockRepo.On("GetTasksDueBefore", mock.Anything, suite.now.Add(24*time.Hour), "overdue").Return([]*domain.Task{task}, nil)
+	suite.mockRepo.On("MarkReminderSent", mock.Anything, task.ID.Hex(), "due:24h0m0s").Return(false, nil)
+
+	err := suite.useCase.CheckDueTasks(context.Background(), suite.now)
+	suite.NoError(err)
+	suite.Empty(suite.notifier.Sent())
+}
+
+func (suite *ReminderTestSuite) TestFailedReminderIsReleasedForRetry() {
+	task := &domain.Task{ID: primitive.NewObjectID(), Title: "Standup", DueDate: suite.now.Add(2 * time.Hour), Status: "pending"}
+	taskID := task.ID.Hex()
+	suite.notifier.Err = errors.New("smtp down")
+	suite.mockRepo.On("GetTasksDueBefore", mock.Anything, mock.Anything, "overdue").Return([]*domain.Task{task}, nil)
+	suite.mockRepo.On("MarkReminderSent", mock.Anything, taskID, "due:24h0m0s").Return(true, nil)
+	suite.mockRepo.On("ClearReminderSent", mock.Anything, taskID, "due:24h0m0s").Return(nil)
+
+	err := suite.useCase.CheckDueTasks(context.Background(), suite.now)
+	suite.Error(err)
+	suite.mockRepo.AssertCalled(suite.T(), "ClearReminderSent", mock.Anything, taskID, "due:24h0m0s")
+}
+
+func (suite *ReminderTestSuite) TestTasksReportedOverdueAreNotFetchedAgain() {
+	suite.mockRepo.On("GetTasksDueBefore", mock.Anything, suite.now.Add(24*time.Hour), "overdue").Return([]*domain.Task{}, nil)
+
+	err := suite.useCase.CheckDueTasks(context.Background(), suite.now)
+	suite.NoError(err)
+	suite.Empty(suite.notifier.Sent())
+	suite.mockRepo.AssertNotCalled(suite.T(), "MarkReminderSent", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReminderTestSuite(t *testing.T) {
+	suite.Run(t, new(ReminderTestSuite))
+}
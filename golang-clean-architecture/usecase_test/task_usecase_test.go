@@ -1,6 +1,7 @@
 package usecase_test
 
 import (
+	"context"
 	"errors"
 	"golang-clean-architecture/domain"
 	"golang-clean-architecture/domain/mocks"
@@ -8,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -15,12 +17,17 @@ import (
 type TaskTestSuite struct {
     suite.Suite
     taskmockRepo *mocks.TaskRepository
+    taskmockSearcher *mocks.TaskSearcher
+    taskmockEvents *mocks.EventPublisher
     taskuseCase  domain.TaskUseCase
 }
 
 func (suite *TaskTestSuite) SetupTest() {
     suite.taskmockRepo = new(mocks.TaskRepository)
-    suite.taskuseCase = use_cases.NewTaskUseCase(suite.taskmockRepo)
+    suite.taskmockSearcher = new(mocks.TaskSearcher)
+    suite.taskmockEvents = new(mocks.EventPublisher)
+    suite.taskmockEvents.On("Publish", mock.Anything, mock.Anything).Return()
+    suite.taskuseCase = use_cases.NewTaskUseCase(suite.taskmockRepo, suite.taskmockSearcher, suite.taskmockEvents)
 }
 
 func (suite *TaskTestSuite) TestGetTasks_Positive() {
@@ -29,11 +36,11 @@ func (suite *TaskTestSuite) TestGetTasks_Positive() {
         {ID: primitive.NewObjectID(), Title: "Task 1", Description: "Description 1", Status: "pending"},
         {ID: primitive.NewObjectID(), Title: "Task 2", Description: "Description 2", Status: "pending"},
     }
-    suite.taskmockRepo.On("GetTasks").Return(tasks, nil)
-    result, err := suite.taskuseCase.GetTasks()
+    suite.taskmockRepo.On("GetTasks", mock.Anything).Return(tasks, nil)
+    result, err := suite.taskuseCase.GetTasks(context.Background())
     suite.NoError(err, "no error when getting tasks")
     suite.Equal(tasks, result, "tasks should match")
-    suite.taskmockRepo.AssertCalled(suite.T(), "GetTasks")
+    suite.taskmockRepo.AssertCalled(suite.T(), "GetTasks", mock.Anything)
 }
 
 func (suite *TaskTestSuite) TestGetTasks_ErrorFetchingTasks() {
@@ -42,80 +49,84 @@ func (suite *TaskTestSuite) TestGetTasks_ErrorFetchingTasks() {
         {ID: primitive.NewObjectID(), Title: "Task 1", Description: "Description 1", Status: "pending"},
         {ID: primitive.NewObjectID(), Title: "Task 2", Description: "Description 2", Status: "pending"},
     }
-    suite.taskmockRepo.On("GetTasks").Return(tasks, errors.New("error while fetching tasks"))
-    _, err := suite.taskuseCase.GetTasks()
+    suite.taskmockRepo.On("GetTasks", mock.Anything).Return(tasks, errors.New("error while fetching tasks"))
+    _, err := suite.taskuseCase.GetTasks(context.Background())
     suite.Error(err, "error when getting tasks")
     suite.Equal(err.Error(), "error while fetching tasks")
-    suite.taskmockRepo.AssertCalled(suite.T(), "GetTasks")
+    suite.taskmockRepo.AssertCalled(suite.T(), "GetTasks", mock.Anything)
 }
 
 func (suite *TaskTestSuite) TestGetTask_Positive() {
 
     task := domain.Task{ID: primitive.NewObjectID(), Title: "Task 1", Description: "Description 1", Status: "pending"}
-    suite.taskmockRepo.On("GetTask", task.ID.Hex()).Return(task, nil)
-    result, err := suite.taskuseCase.GetTask(task.ID.Hex())
+    suite.taskmockRepo.On("GetTask", mock.Anything, task.ID.Hex()).Return(task, nil)
+    result, err := suite.taskuseCase.GetTask(context.Background(), task.ID.Hex())
     suite.NoError(err, "no error when fetching a task")
     suite.Equal(task, result, "tasks should match")
-    suite.taskmockRepo.AssertCalled(suite.T(), "GetTask", task.ID.Hex())
+    suite.taskmockRepo.AssertCalled(suite.T(), "GetTask", mock.Anything, task.ID.Hex())
 }
 
 func (suite *TaskTestSuite) TestGetTask_InvalidTaskID() {
 
     task := domain.Task{ID: primitive.NewObjectID(), Title: "Task 1", Description: "Description 1", Status: "pending"}
-    suite.taskmockRepo.On("GetTask", task.ID.Hex()).Return(task, errors.New("invalid task ID"))
-	_, err := suite.taskuseCase.GetTask(task.ID.Hex())
+    suite.taskmockRepo.On("GetTask", mock.Anything, task.ID.Hex()).Return(task, errors.New("invalid task ID"))
+	_, err := suite.taskuseCase.GetTask(context.Background(), task.ID.Hex())
     suite.Error(err, "error while fetching a task")
     suite.Equal(err.Error(), "invalid task ID")
-    suite.taskmockRepo.AssertCalled(suite.T(), "GetTask", task.ID.Hex())
+    suite.taskmockRepo.AssertCalled(suite.T(), "GetTask", mock.Anything, task.ID.Hex())
 }
 
 func (suite *TaskTestSuite) TestGetTask_NoTaskWithSpecifiedID() {
 
     task := domain.Task{ID: primitive.NewObjectID(), Title: "Task 1", Description: "Description 1", Status: "pending"}
-    suite.taskmockRepo.On("GetTask", task.ID.Hex()).Return(task, errors.New("no task with specified ID"))
-	_, err := suite.taskuseCase.GetTask(task.ID.Hex())
+    suite.taskmockRepo.On("GetTask", mock.Anything, task.ID.Hex()).Return(task, errors.New("no task with specified ID"))
+	_, err := suite.taskuseCase.GetTask(context.Background(), task.ID.Hex())
     suite.Error(err, "error while fetching a task")
     suite.Equal(err.Error(), "no task with specified ID")
-    suite.taskmockRepo.AssertCalled(suite.T(), "GetTask", task.ID.Hex())
+    suite.taskmockRepo.AssertCalled(suite.T(), "GetTask", mock.Anything, task.ID.Hex())
 }
 
 func (suite *TaskTestSuite) TestDeleteTask_Positive() {
 
     task := domain.Task{ID: primitive.NewObjectID(), Title: "Task 1", Description: "Description 1", Status: "pending"}
-    suite.taskmockRepo.On("DeleteTask", task.ID.Hex()).Return(nil)
-    err := suite.taskuseCase.DeleteTask(task.ID.Hex())
+    suite.taskmockRepo.On("DeleteTask", mock.Anything, task.ID.Hex()).Return(nil)
+    err := suite.taskuseCase.DeleteTask(context.Background(), task.ID.Hex())
     suite.NoError(err, "no error when deleting a task")
-    suite.taskmockRepo.AssertCalled(suite.T(), "DeleteTask", task.ID.Hex())
+    suite.taskmockRepo.AssertCalled(suite.T(), "DeleteTask", mock.Anything, task.ID.Hex())
+    suite.taskmockEvents.AssertCalled(suite.T(), "Publish", mock.Anything, mock.MatchedBy(func(event domain.Event) bool {
+        return event.Type == "task.deleted"
+    }))
 }
 
 func (suite *TaskTestSuite) TestDeleteTask_InvalidTaskID() {
 
 	task_id := primitive.NewObjectID().Hex()
-    suite.taskmockRepo.On("DeleteTask", task_id).Return(errors.New("invalid task ID"))
-	err := suite.taskuseCase.DeleteTask(task_id)
+    suite.taskmockRepo.On("DeleteTask", mock.Anything, task_id).Return(errors.New("invalid task ID"))
+	err := suite.taskuseCase.DeleteTask(context.Background(), task_id)
     suite.Error(err, "error while deleting a task")
     suite.Equal(err.Error(), "invalid task ID")
-    suite.taskmockRepo.AssertCalled(suite.T(), "DeleteTask", task_id)
+    suite.taskmockRepo.AssertCalled(suite.T(), "DeleteTask", mock.Anything, task_id)
+    suite.taskmockEvents.AssertNotCalled(suite.T(), "Publish", mock.Anything, mock.Anything)
 }
 
 func (suite *TaskTestSuite) TestDeleteTask_TaskNotFound() {
 
 	task_id := primitive.NewObjectID().Hex()
-    suite.taskmockRepo.On("DeleteTask", task_id).Return(errors.New("task with specified id not found"))
-	err := suite.taskuseCase.DeleteTask(task_id)
+    suite.taskmockRepo.On("DeleteTask", mock.Anything, task_id).Return(errors.New("task with specified id not found"))
+	err := suite.taskuseCase.DeleteTask(context.Background(), task_id)
     suite.Error(err, "error while deleting a task")
     suite.Equal(err.Error(), "task with specified id not found")
-    suite.taskmockRepo.AssertCalled(suite.T(), "DeleteTask", task_id)
+    suite.taskmockRepo.AssertCalled(suite.T(), "DeleteTask", mock.Anything, task_id)
 }
 
 func (suite *TaskTestSuite) TestUpdateTask_Positive() {
 
     modifiedTask := domain.Task{Title: "Task 2", Description: "Description 1", Status: "pending"}
 	task_id := primitive.NewObjectID().Hex()
-    suite.taskmockRepo.On("UpdateTask", task_id, &modifiedTask).Return(nil)
-	err := suite.taskuseCase.UpdateTask(task_id, &modifiedTask)
+    suite.taskmockRepo.On("UpdateTask", mock.Anything, task_id, &modifiedTask).Return(nil)
+	err := suite.taskuseCase.UpdateTask(context.Background(), task_id, &modifiedTask)
     suite.NoError(err, "no error while fetching a task")
-    suite.taskmockRepo.AssertCalled(suite.T(), "UpdateTask", task_id, &modifiedTask)
+    suite.taskmockRepo.AssertCalled(suite.T(), "UpdateTask", mock.Anything, task_id, &modifiedTask)
 
 }
 
@@ -123,19 +134,22 @@ func (suite *TaskTestSuite) TestUpdateTask_TaskNotFound() {
 
 	modifiedTask := domain.Task{Title: "Task 2", Description: "Description 1", Status: "pending"}
 	task_id := primitive.NewObjectID().Hex()
-    suite.taskmockRepo.On("UpdateTask", task_id, &modifiedTask).Return(errors.New("task with specified id not found"))
-	err := suite.taskuseCase.UpdateTask(task_id, &modifiedTask)
+    suite.taskmockRepo.On("UpdateTask", mock.Anything, task_id, &modifiedTask).Return(errors.New("task with specified id not found"))
+	err := suite.taskuseCase.UpdateTask(context.Background(), task_id, &modifiedTask)
     suite.Error(err, "error while updating a task")
     suite.Equal(err.Error(), "task with specified id not found")
-    suite.taskmockRepo.AssertCalled(suite.T(), "UpdateTask", task_id, &modifiedTask)
+    suite.taskmockRepo.AssertCalled(suite.T(), "UpdateTask", mock.Anything, task_id, &modifiedTask)
 }
 
 func (suite *TaskTestSuite) TestPostTask_Positive() {
 	insertedTask := domain.Task{Title: "Task 2", Description: "Description 1", DueDate : time.Now(), Status: "pending"}
-	suite.taskmockRepo.On("PostTask", &insertedTask).Return(nil)
-	err := suite.taskuseCase.PostTask(insertedTask)
+	suite.taskmockRepo.On("PostTask", mock.Anything, &insertedTask).Return(nil)
+	err := suite.taskuseCase.PostTask(context.Background(), &insertedTask)
 	suite.NoError(err,  "no error while posting a task")
-	suite.taskmockRepo.AssertCalled(suite.T(), "PostTask", &insertedTask)
+	suite.taskmockRepo.AssertCalled(suite.T(), "PostTask", mock.Anything, &insertedTask)
+	suite.taskmockEvents.AssertCalled(suite.T(), "Publish", mock.Anything, mock.MatchedBy(func(event domain.Event) bool {
+		return event.Type == "task.created"
+	}))
 }
 
 func (suite *TaskTestSuite) TestPostTask_RequiredFieldsMissing() {
@@ -145,11 +159,112 @@ func (suite *TaskTestSuite) TestPostTask_RequiredFieldsMissing() {
 		DueDate : time.Now(),
 		Status : "on going",
 	}
-	suite.taskmockRepo.On("PostTask", &insertedTask).Return(errors.New("required field missing"))
-	err := suite.taskuseCase.PostTask(insertedTask)
+	suite.taskmockRepo.On("PostTask", mock.Anything, &insertedTask).Return(errors.New("required field missing"))
+	err := suite.taskuseCase.PostTask(context.Background(), &insertedTask)
 	suite.Error(err, "error while posting a task")
 	suite.Equal(err.Error(), "required fields are missing")
-	suite.taskmockRepo.AssertNotCalled(suite.T(), "PostTask", &insertedTask)
+	suite.taskmockRepo.AssertNotCalled(suite.T(), "PostTask", mock.Anything, &insertedTask)
+}
+
+func (suite *TaskTestSuite) TestSearchTasks_Positive() {
+	results := []*domain.TaskSearchResult{
+		{Task: &domain.Task{ID: primitive.NewObjectID(), Title: "Deploy release"}, Score: 1.5},
+	}
+	suite.taskmockSearcher.On("SearchTasks", mock.Anything, "deploy", 20).Return(results, nil)
+	found, err := suite.taskuseCase.SearchTasks(context.Background(), "  deploy ", 0)
+	suite.NoError(err, "no error while searching tasks")
+	suite.Equal(results, found)
+	suite.taskmockSearcher.AssertCalled(suite.T(), "SearchTasks", mock.Anything, "deploy", 20)
+}
+
+func (suite *TaskTestSuite) TestSearchTasks_LimitCapped() {
+	suite.taskmockSearcher.On("SearchTasks", mock.Anything, "deploy", 100).Return([]*domain.TaskSearchResult{}, nil)
+	_, err := suite.taskuseCase.SearchTasks(context.Background(), "deploy", 5000)
+	suite.NoError(err, "no error while searching tasks")
+	suite.taskmockSearcher.AssertCalled(suite.T(), "SearchTasks", mock.Anything, "deploy", 100)
+}
+
+func (suite *TaskTestSuite) TestSearchTasks_EmptyQuery() {
+	_, err := suite.taskuseCase.SearchTasks(context.Background(), "   ", 10)
+	suite.Error(err, "error when the query is empty")
+	suite.Equal("search query is required", err.Error())
+	suite.taskmockSearcher.AssertNotCalled(suite.T(), "SearchTasks", mock.Anything, "", 10)
+}
+
+func (suite *TaskTestSuite) TestPostTask_InvalidRecurrence() {
+	insertedTask := domain.Task{Title: "Checklist", Description: "weekly ops", DueDate: time.Now(), Status: "pending", Recurrence: "FREQ=HOURLY"}
+	err := suite.taskuseCase.PostTask(context.Background(), &insertedTask)
+	suite.Error(err, "error when the recurrence rule is unsupported")
+	suite.Contains(err.Error(), "invalid recurrence rule")
+	suite.taskmockRepo.AssertNotCalled(suite.T(), "PostTask", mock.Anything, mock.Anything)
+}
+
+func (suite *TaskTestSuite) TestUpdateTask_CompletingOccurrenceCreatesNext() {
+	due := time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC)
+	current := domain.Task{ID: primitive.NewObjectID(), Title: "Checklist", Description: "weekly ops", DueDate: due, Status: "done", Recurrence: "FREQ=WEEKLY", Occurrence: 1}
+	modifiedTask := domain.Task{Status: "done"}
+	taskID := current.ID.Hex()
+
+	suite.taskmockRepo.On("UpdateTask", mock.Anything, taskID, &modifiedTask).Return(nil)
+	suite.taskmockRepo.On("GetTask", mock.Anything, taskID).Return(current, nil)
+	suite.taskmockRepo.On("GetSeriesTasks", mock.Anything, taskID).Return([]*domain.Task{&current}, nil)
+	suite.taskmockRepo.On("PostTask", mock.Anything, mock.Anything).Return(nil)
+
+	err := suite.taskuseCase.UpdateTask(context.Background(), taskID, &modifiedTask)
+	suite.NoError(err)
+	suite.taskmockRepo.AssertCalled(suite.T(), "PostTask", mock.Anything, mock.MatchedBy(func(task *domain.Task) bool {
+		return task.SeriesID == taskID && task.Occurrence == 2 && task.Status == "pending" &&
+			task.DueDate.Equal(due.AddDate(0, 0, 7))
+	}))
+}
+
+func (suite *TaskTestSuite) TestUpdateTask_NextOccurrenceAlreadyExists() {
+	current := domain.Task{ID: primitive.NewObjectID(), Title: "Checklist", Description: "weekly ops", DueDate: time.Now(), Status: "done", Recurrence: "FREQ=WEEKLY", Occurrence: 1}
+	following := domain.Task{ID: primitive.NewObjectID(), SeriesID: current.ID.Hex(), Occurrence: 2}
+	modifiedTask := domain.Task{Status: "done"}
+	taskID := current.ID.Hex()
+
+	suite.taskmockRepo.On("UpdateTask", mock.Anything, taskID, &modifiedTask).Return(nil)
+	suite.taskmockRepo.On("GetTask", mock.Anything, taskID).Return(current, nil)
+	suite.taskmockRepo.On("GetSeriesTasks", mock.Anything, taskID).Return([]*domain.Task{&current, &following}, nil)
+
+	err := suite.taskuseCase.UpdateTask(context.Background(), taskID, &modifiedTask)
+	suite.NoError(err)
+	suite.taskmockRepo.AssertNotCalled(suite.T(), "PostTask", mock.Anything, mock.Anything)
+}
+
+func (suite *TaskTestSuite) TestMaterializeRecurringTasks_CatchesUpUntilHorizon() {
+	due := time.Date(2024, time.March, 1, 9, 0, 0, 0, time.UTC)
+	first := &domain.Task{ID: primitive.NewObjectID(), Title: "Standup", Description: "daily", DueDate: due, Status: "pending", Recurrence: "FREQ=DAILY;COUNT=3", Occurrence: 1}
+	seriesID := first.ID.Hex()
+
+	suite.taskmockRepo.On("GetRecurringTasks", mock.Anything).Return([]*domain.Task{first}, nil)
+	suite.taskmockRepo.On("GetSeriesTasks", mock.Anything, seriesID).Return([]*domain.Task{first}, nil)
+	suite.taskmockRepo.On("PostTask", mock.Anything, mock.Anything).Return(nil)
+
+	err := suite.taskuseCase.MaterializeRecurringTasks(context.Background(), due.AddDate(0, 0, 30))
+	suite.NoError(err)
+	// COUNT=3 stops the series after two more occurrences
+	suite.taskmockRepo.AssertNumberOfCalls(suite.T(), "PostTask", 2)
+}
+
+func (suite *TaskTestSuite) TestStopRecurrence_NotRecurring() {
+	task := domain.Task{ID: primitive.NewObjectID(), Title: "Task 1", Description: "Description 1", Status: "pending"}
+	suite.taskmockRepo.On("GetTask", mock.Anything, task.ID.Hex()).Return(task, nil)
+	err := suite.taskuseCase.StopRecurrence(context.Background(), task.ID.Hex())
+	suite.Error(err)
+	suite.Equal("task is not part of a recurring series", err.Error())
+	suite.taskmockRepo.AssertNotCalled(suite.T(), "UpdateSeriesRecurrence", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *TaskTestSuite) TestUpdateRecurrence_UsesSeriesID() {
+	seriesID := primitive.NewObjectID().Hex()
+	task := domain.Task{ID: primitive.NewObjectID(), DueDate: time.Now(), Recurrence: "FREQ=DAILY", SeriesID: seriesID, Occurrence: 4}
+	suite.taskmockRepo.On("GetTask", mock.Anything, task.ID.Hex()).Return(task, nil)
+	suite.taskmockRepo.On("UpdateSeriesRecurrence", mock.Anything, seriesID, "FREQ=WEEKLY;BYDAY=MO").Return(nil)
+	err := suite.taskuseCase.UpdateRecurrence(context.Background(), task.ID.Hex(), " FREQ=WEEKLY;BYDAY=MO ")
+	suite.NoError(err)
+	suite.taskmockRepo.AssertCalled(suite.T(), "UpdateSeriesRecurrence", mock.Anything, seriesID, "FREQ=WEEKLY;BYDAY=MO")
 }
 
 func TestTaskTestSuite(t *testing.T) {
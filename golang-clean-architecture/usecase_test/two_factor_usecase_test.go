@@ -0,0 +1,127 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"golang-clean-architecture/domain"
+	"golang-clean-architecture/domain/mocks"
+	"golang-clean-architecture/infrastructure"
+	"golang-clean-architecture/use_cases"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const testTOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+type TwoFactorTestSuite struct {
+	suite.Suite
+	mockUsers     *mocks.UserRepository
+	mockTwoFactor *mocks.TwoFactorRepository
+	mockTokens    *mocks.OneTimeTokenRepository
+	useCase       *use_cases.TwoFactorUseCase
+	now           time.Time
+	user          domain.User
+}
+
+func (suite *TwoFactorTestSuite) SetupTest() {
+	suite.mockUsers = new(mocks.UserRepository)
+	suite.mockTwoFactor = new(mocks.TwoFactorRepository)
+	suite.mockTokens = new(mocks.OneTimeTokenRepository)
+	suite.now = time.Date(2024, 8, 10, 9, 30, 0, 0, time.UTC)
+	suite.useCase = use_cases.NewTwoFactorUseCase(suite.mockUsers, suite.mockTwoFactor, suite.mockTokens, "Task Manager", 5*time.Minute).(*use_cases.TwoFactorUseCase)
+	suite.useCase.Now = func() time.Time { return suite.now }
+	suite.user = domain.User{ID: primitive.NewObjectID(), Email: "kidusm3l@gmail.com", Role: "admin"}
+}
+
+func (suite *TwoFactorTestSuite) currentCode() string {
+	code, err := infrastructure.TOTPCode(testTOTPSecret, infrastructure.TOTPStep(suite.now))
+	suite.Require().NoError(err)
+	return code
+}
+
+func (suite *TwoFactorTestSuite) TestBeginEnrollment() {
+	suite.mockUsers.On("GetUserByEmail", mock.Anything, "kidusm3l@gmail.com").Return(suite.user)
+	suite.mockTwoFactor.On("SetPendingSecret", mock.Anything, suite.user.ID.Hex(), mock.Anything).Return(nil)
+
+	enrollment, err := suite.useCase.BeginEnrollment(context.Background(), "kidusm3l@gmail.com")
+	suite.NoError(err)
+	suite.Len(enrollment.Secret, 32)
+	suite.True(strings.HasPrefix(enrollment.ProvisioningURI, "otpauth://totp/"))
+	suite.Contains(enrollment.ProvisioningURI, "secret="+enrollment.Secret)
+	suite.mockTwoFactor.AssertCalled(suite.T(), "SetPendingSecret", mock.Anything, suite.user.ID.Hex(), enrollment.Secret)
+}
+
+func (suite *TwoFactorTestSuite) TestBeginEnrollment_AlreadyEnabled() {
+	suite.user.TwoFactorEnabled = true
+	suite.mockUsers.On("GetUserByEmail", mock.Anything, "kidusm3l@gmail.com").Return(suite.user)
+
+	_, err := suite.useCase.BeginEnrollment(context.Background(), "kidusm3l@gmail.com")
+	suite.EqualError(err, "two-factor authentication is already enabled")
+}
+
+func (suite *TwoFactorTestSuite) TestConfirmEnrollment() {
+	suite.mockUsers.On("GetUserByEmail", mock.Anything, "kidusm3l@gmail.com").Return(suite.user)
+	suite.mockTwoFactor.On("GetTwoFactor", mock.Anything, suite.user.ID.Hex()).Return(domain.TwoFactor{PendingSecret: testTOTPSecret}, nil)
+	suite.mockTwoFactor.On("EnableTwoFactor", mock.Anything, suite.user.ID.Hex(), testTOTPSecret, mock.Anything).Return(nil)
+
+	_, err := suite.useCase.ConfirmEnrollment(context.Background(), "kidusm3l@gmail.com", "000000")
+	suite.EqualError(err, "invalid two-factor code")
+	suite.mockTwoFactor.AssertNotCalled(suite.T(), "EnableTwoFactor", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	codes, err := suite.useCase.ConfirmEnrollment(context.Background(), "kidusm3l@gmail.com", suite.currentCode())
+	suite.NoError(err)
+	suite.Len(codes, 10)
+	suite.mockTwoFactor.AssertCalled(suite.T(), "EnableTwoFactor", mock.Anything, suite.user.ID.Hex(), testTOTPSecret, mock.MatchedBy(func(hashes []string) bool {
+		// only hashes are stored, of the codes without their dashes
+		return len(hashes) == 10 &&
+			hashes[0] == infrastructure.HashToken(strings.ReplaceAll(codes[0], "-", "")) &&
+			!strings.Contains(strings.Join(hashes, ","), codes[0])
+	}))
+}
+
+func (suite *TwoFactorTestSuite) expectChallenge(challenge string) {
+	suite.mockTokens.On("ConsumeToken", mock.Anything, "login_challenge", infrastructure.HashToken(challenge), suite.now).
+		Return(domain.OneTimeToken{UserID: suite.user.ID.Hex()}, nil).Once()
+	suite.mockUsers.On("GetUserByID", mock.Anything, suite.user.ID.Hex()).Return(suite.user, nil)
+	suite.mockTwoFactor.On("GetTwoFactor", mock.Anything, suite.user.ID.Hex()).Return(domain.TwoFactor{Secret: testTOTPSecret}, nil)
+}
+
+func (suite *TwoFactorTestSuite) TestVerify_TOTPCodeCannotBeReplayed() {
+	step := infrastructure.TOTPStep(suite.now)
+	suite.mockTwoFactor.On("UseTimeStep", mock.Anything, suite.user.ID.Hex(), step).Return(true, nil).Once()
+	suite.mockTwoFactor.On("UseTimeStep", mock.Anything, suite.user.ID.Hex(), step).Return(false, nil)
+
+	suite.expectChallenge("first")
+	user, err := suite.useCase.Verify(context.Background(), "first", suite.currentCode())
+	suite.NoError(err)
+	suite.Equal(suite.user.Email, user.Email)
+
+	suite.expectChallenge("second")
+	_, err = suite.useCase.Verify(context.Background(), "second", suite.currentCode())
+	suite.EqualError(err, "invalid two-factor code")
+}
+
+func (suite *TwoFactorTestSuite) TestVerify_RecoveryCode() {
+	suite.mockTwoFactor.On("UseRecoveryCode", mock.Anything, suite.user.ID.Hex(), infrastructure.HashToken("7mqzk2dpvx4ahn3e")).Return(true, nil)
+
+	suite.expectChallenge("challenge")
+	_, err := suite.useCase.Verify(context.Background(), "challenge", "7MQZ-K2DP-VX4A-HN3E")
+	suite.NoError(err)
+}
+
+func (suite *TwoFactorTestSuite) TestVerify_InvalidChallenge() {
+	suite.mockTokens.On("ConsumeToken", mock.Anything, "login_challenge", mock.Anything, suite.now).Return(domain.OneTimeToken{}, errors.New("invalid or expired token"))
+
+	_, err := suite.useCase.Verify(context.Background(), "stale", suite.currentCode())
+	suite.EqualError(err, "invalid or expired challenge")
+	suite.mockTwoFactor.AssertNotCalled(suite.T(), "GetTwoFactor", mock.Anything, mock.Anything)
+}
+
+func TestTwoFactorTestSuite(t *testing.T) {
+	suite.Run(t, new(TwoFactorTestSuite))
+}
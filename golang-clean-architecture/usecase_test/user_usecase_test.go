@@ -1,58 +1,311 @@
 package usecase_test
 
 import (
+	"context"
 	"errors"
 	"golang-clean-architecture/domain"
 	"golang-clean-architecture/domain/mocks"
+	"golang-clean-architecture/infrastructure"
 	"golang-clean-architecture/use_cases"
+	"io"
+	"log/slog"
+	"strings"
 	"testing"
+	"time"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
 )
 
 type UserTestSuite struct {
 	suite.Suite
 	mockRepo		*mocks.UserRepository
+	mockEvents		*mocks.EventPublisher
+	mockVerification	*mocks.EmailVerificationUseCase
+	mockGuard		*mocks.LoginGuard
+	mockTwoFactor	*mocks.TwoFactorUseCase
+	mockTokens		*mocks.TokenService
+	hasher			domain.PasswordHasher
 	useCase			domain.UserUseCase
 	
 }
 
 func (suite *UserTestSuite) SetupSuite() {
 	suite.mockRepo = new(mocks.UserRepository)
-	suite.useCase = use_cases.NewUserUseCase(suite.mockRepo)
+	suite.mockEvents = new(mocks.EventPublisher)
+	suite.mockEvents.On("Publish", mock.Anything, mock.Anything).Return()
+	suite.mockVerification = new(mocks.EmailVerificationUseCase)
+	suite.mockVerification.On("SendVerification", mock.Anything, mock.Anything).Return(nil)
+	suite.mockGuard = new(mocks.LoginGuard)
+	suite.mockGuard.On("Check", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	suite.mockGuard.On("RecordFailure", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	suite.mockGuard.On("RecordSuccess", mock.Anything, mock.Anything).Return(nil)
+	suite.mockTwoFactor = new(mocks.TwoFactorUseCase)
+	suite.mockTokens = new(mocks.TokenService)
+	suite.mockTokens.On("IssueToken", mock.Anything).Return(func(user *domain.User) domain.AccessToken {
+		return domain.AccessToken{Token: "token-for-" + user.Email, ExpiresAt: time.Now().Add(time.Hour)}
+	}, nil)
+	suite.hasher = newTestHasher()
+	suite.useCase = use_cases.NewUserUseCase(suite.mockRepo, suite.mockVerification, suite.mockGuard, suite.mockTwoFactor, suite.mockTokens, suite.hasher, infrastructure.NewPasswordPolicy(infrastructure.DefaultPasswordRules()), suite.mockEvents, true, discardLogger)
+}
+
+// discardLogger drops whatever the use cases log during tests.
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// newTestHasher uses the cheapest Argon2id parameters to keep tests fast.
+func newTestHasher() domain.PasswordHasher {
+	return infrastructure.NewPasswordHasher(infrastructure.Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32})
 }
 
 func (suite *UserTestSuite) TestUserRegister_Positive() {
 
-    user := &domain.User{Email: "test@example.com", Password: "password123", Role: "admin"}
-    suite.mockRepo.On("Register", user).Return(nil)
-    suite.mockRepo.On("VerifyFirst", user).Return(nil)
-    suite.mockRepo.On("UserExists", user).Return(nil)
-    err := suite.useCase.Register(user)
+    user := &domain.User{Email: "test@example.com", Password: "correct horse battery", Role: "admin"}
+    suite.mockRepo.On("Register", mock.Anything, user).Return(nil)
+    suite.mockRepo.On("VerifyFirst", mock.Anything, user).Return(nil)
+    suite.mockRepo.On("UserExists", mock.Anything, user).Return(nil)
+    err := suite.useCase.Register(context.Background(), user)
     suite.NoError(err, "no error when creating a user")
-    suite.mockRepo.AssertCalled(suite.T(), "Register", user)
+    suite.mockRepo.AssertCalled(suite.T(), "Register", mock.Anything, user)
 }
 
 func (suite *UserTestSuite) TestUserRegister_DatabaseError() {
 
-	user := &domain.User{Email: "test@example.com", Password: "password123", Role: "admin"}
-	suite.mockRepo.On("Register", user).Return(errors.New("database error"))
-	suite.mockRepo.On("VerifyFirst", user).Return(nil)
-	suite.mockRepo.On("UserExists", user).Return(nil)
-	err := suite.useCase.Register(user)
+	user := &domain.User{Email: "test@example.com", Password: "correct horse battery", Role: "admin"}
+	suite.mockRepo.On("Register", mock.Anything, user).Return(errors.New("database error"))
+	suite.mockRepo.On("VerifyFirst", mock.Anything, user).Return(nil)
+	suite.mockRepo.On("UserExists", mock.Anything, user).Return(nil)
+	err := suite.useCase.Register(context.Background(), user)
 	suite.Error(err, "error when creating a user")
 	suite.Equal(err.Error(), "database error")
-	suite.mockRepo.AssertCalled(suite.T(), "Register", user)
+	suite.mockRepo.AssertCalled(suite.T(), "Register", mock.Anything, user)
 }
 
 func (suite *UserTestSuite) TestUserRegister_UserAlreadyExists() {
-	user := &domain.User{Email: "test@example.com", Password: "password123", Role: "admin"}
-	suite.mockRepo.On("Register", user).Return(nil)
-	suite.mockRepo.On("VerifyFirst", user).Return(nil)
-	suite.mockRepo.On("UserExists", user).Return((errors.New("user already exists")))
-	err := suite.useCase.Register(user)
+	user := &domain.User{Email: "test@example.com", Password: "correct horse battery", Role: "admin"}
+	suite.mockRepo.On("Register", mock.Anything, user).Return(nil)
+	suite.mockRepo.On("VerifyFirst", mock.Anything, user).Return(nil)
+	suite.mockRepo.On("UserExists", mock.Anything, user).Return((errors.New("user already exists")))
+	err := suite.useCase.Register(context.Background(), user)
 	suite.Error(err, "expected error when user already exists")
 	suite.Equal(err.Error(), "user already exists")
-	suite.mockRepo.AssertCalled(suite.T(), "UserExists", user)
+	suite.mockRepo.AssertCalled(suite.T(), "UserExists", mock.Anything, user)
+}
+
+func (suite *UserTestSuite) TestUserRegister_NormalisesEmailAndSendsVerification() {
+	user := &domain.User{Email: "  New.User@Example.COM ", Password: "correct horse battery", Verified: true}
+	suite.mockRepo.On("Register", mock.Anything, user).Return(nil)
+	suite.mockRepo.On("VerifyFirst", mock.Anything, user).Return(nil)
+	suite.mockRepo.On("UserExists", mock.Anything, user).Return(nil)
+	err := suite.useCase.Register(context.Background(), user)
+	suite.NoError(err)
+	suite.Equal("new.user@example.com", user.Email)
+	suite.False(user.Verified, "clients can't register pre-verified accounts")
+	suite.mockVerification.AssertCalled(suite.T(), "SendVerification", mock.Anything, user)
+}
+
+func (suite *UserTestSuite) TestUserRegister_InvalidEmail() {
+	for _, email := range []string{"not-an-email", "Jane <jane@example.com>", "jane@localhost", "jane@@example.com"} {
+		err := suite.useCase.Register(context.Background(), &domain.User{Email: email, Password: "password123"})
+		suite.EqualError(err, "invalid email address", email)
+	}
+}
+
+func (suite *UserTestSuite) TestUserRegister_PasswordPolicy() {
+	err := suite.useCase.Register(context.Background(), &domain.User{Email: "weak@example.com", Password: "a"})
+	var policyErr *domain.PasswordPolicyError
+	suite.Require().ErrorAs(err, &policyErr)
+	suite.Equal("min_length", policyErr.Violations[0].Rule)
+
+	err = suite.useCase.Register(context.Background(), &domain.User{Email: "weak@example.com", Password: "Password123"})
+	suite.EqualError(err, "password is too common")
+	err = suite.useCase.Register(context.Background(), &domain.User{Email: "Weak.Sauce@example.com", Password: "weak.sauce@example.com"})
+	suite.EqualError(err, "password must not be your email address")
+	suite.mockRepo.AssertNotCalled(suite.T(), "UserExists", mock.Anything, mock.MatchedBy(func(user *domain.User) bool { return strings.HasPrefix(user.Email, "weak") }))
+}
+
+func (suite *UserTestSuite) TestUserLogin_UnverifiedAccountRefused() {
+	hashedPassword, err := suite.hasher.Hash("password123")
+	suite.NoError(err)
+	suite.mockRepo.On("GetUserByEmail", mock.Anything, "unverified@example.com").Return(domain.User{Email: "unverified@example.com", Password: hashedPassword})
+	suite.mockRepo.On("GetUserByEmail", mock.Anything, "verified@example.com").Return(domain.User{Email: "verified@example.com", Password: hashedPassword, Verified: true})
+
+	_, err = suite.useCase.Login(context.Background(), &domain.User{Email: "Unverified@Example.com", Password: "password123"}, "203.0.113.7")
+	suite.EqualError(err, "email address not verified")
+
+	result, err := suite.useCase.Login(context.Background(), &domain.User{Email: "verified@example.com", Password: "password123"}, "203.0.113.7")
+	suite.NoError(err)
+	suite.Equal("token-for-verified@example.com", result.Token.Token)
+	suite.False(result.TwoFactorRequired)
+}
+
+func (suite *UserTestSuite) TestUserLogin_TwoFactorChallenge() {
+	hashedPassword, err := suite.hasher.Hash("password123")
+	suite.NoError(err)
+	user := domain.User{ID: primitive.NewObjectID(), Email: "2fa@example.com", Password: hashedPassword, Verified: true, TwoFactorEnabled: true}
+	suite.mockRepo.On("GetUserByEmail", mock.Anything, "2fa@example.com").Return(user)
+	suite.mockTwoFactor.On("Challenge", mock.Anything, mock.Anything).Return("a_challenge", nil)
+	suite.mockTwoFactor.On("Verify", mock.Anything, "a_challenge", "123456").Return(user, nil)
+	suite.mockTwoFactor.On("Verify", mock.Anything, "a_challenge", "000000").Return(user, errors.New("invalid two-factor code"))
+
+	result, err := suite.useCase.Login(context.Background(), &domain.User{Email: "2fa@example.com", Password: "password123"}, "203.0.113.7")
+	suite.NoError(err)
+	suite.True(result.TwoFactorRequired)
+	suite.Equal("a_challenge", result.ChallengeToken)
+	suite.Empty(result.Token, "no token before the second factor")
+
+	_, err = suite.useCase.CompleteTwoFactorLogin(context.Background(), "a_challenge", "000000", "203.0.113.7")
+	suite.EqualError(err, "invalid two-factor code")
+	suite.mockGuard.AssertCalled(suite.T(), "RecordFailure", mock.Anything, "2fa@example.com", "203.0.113.7", mock.Anything)
+
+	token, err := suite.useCase.CompleteTwoFactorLogin(context.Background(), "a_challenge", "123456", "203.0.113.7")
+	suite.NoError(err)
+	suite.Equal("token-for-2fa@example.com", token.Token)
+	suite.mockGuard.AssertCalled(suite.T(), "RecordSuccess", mock.Anything, "2fa@example.com")
+}
+
+func (suite *UserTestSuite) TestTwoFactorLogin_WrongCodesLockTheAccount() {
+	hashedPassword, err := suite.hasher.Hash("password123")
+	suite.NoError(err)
+	user := domain.User{ID: primitive.NewObjectID(), Email: "guessed@example.com", Password: hashedPassword, Verified: true, TwoFactorEnabled: true}
+	repo := new(mocks.UserRepository)
+	repo.On("GetUserByEmail", mock.Anything, user.Email).Return(user)
+	twoFactor := new(mocks.TwoFactorUseCase)
+	twoFactor.On("Challenge", mock.Anything, mock.Anything).Return("a_challenge", nil)
+	twoFactor.On("Verify", mock.Anything, "a_challenge", mock.Anything).Return(user, errors.New("invalid two-factor code"))
+	// no progressive delay, so that only the lockout stops the attempts
+	policy := use_cases.LoginGuardPolicy{MaxAccountFailures: 3, MaxIPFailures: 100, Window: time.Hour, Lockout: time.Hour, BaseDelay: time.Nanosecond, MaxDelay: time.Nanosecond}
+	guard := use_cases.NewLoginGuard(infrastructure.NewMemoryLoginAttemptStore(time.Hour), suite.mockEvents, policy, discardLogger)
+	useCase := use_cases.NewUserUseCase(repo, suite.mockVerification, guard, twoFactor, suite.mockTokens, suite.hasher, infrastructure.NewPasswordPolicy(infrastructure.DefaultPasswordRules()), suite.mockEvents, true, discardLogger)
+
+	for i := 0; i < policy.MaxAccountFailures; i++ {
+		result, err := useCase.Login(context.Background(), &domain.User{Email: user.Email, Password: "password123"}, "203.0.113.7")
+		suite.Require().NoError(err, "the right password doesn't clear the failures of earlier codes")
+		suite.Require().True(result.TwoFactorRequired)
+		_, err = useCase.CompleteTwoFactorLogin(context.Background(), result.ChallengeToken, "000000", "203.0.113.7")
+		suite.EqualError(err, "invalid two-factor code")
+	}
+
+	_, err = useCase.Login(context.Background(), &domain.User{Email: user.Email, Password: "password123"}, "203.0.113.7")
+	var retryErr *domain.RetryError
+	suite.ErrorAs(err, &retryErr, "the account is locked")
+	twoFactor.AssertNumberOfCalls(suite.T(), "Challenge", policy.MaxAccountFailures)
+}
+
+func (suite *UserTestSuite) TestUpdateProfile_Positive() {
+	user := domain.User{ID: primitive.NewObjectID(), Email: "profile@example.com", Timezone: "UTC"}
+	suite.mockRepo.On("GetUserByEmail", mock.Anything, "profile@example.com").Return(user)
+	suite.mockRepo.On("UpdateProfile", mock.Anything, user.ID.Hex(), mock.Anything).Return(nil)
+
+	displayName := "  Kidus  "
+	updated, err := suite.useCase.UpdateProfile(context.Background(), "profile@example.com", domain.ProfileUpdate{DisplayName: &displayName})
+	suite.NoError(err)
+	suite.Equal("Kidus", updated.DisplayName)
+	suite.Equal("UTC", updated.Timezone, "fields that weren't sent are kept")
+	suite.mockRepo.AssertCalled(suite.T(), "UpdateProfile", mock.Anything, user.ID.Hex(), mock.MatchedBy(func(update domain.ProfileUpdate) bool {
+		return *update.DisplayName == "Kidus" && update.Timezone == nil
+	}))
+}
+
+func (suite *UserTestSuite) TestUpdateProfile_Validation() {
+	timezone := "Mars/Olympus_Mons"
+	_, err := suite.useCase.UpdateProfile(context.Background(), "profile@example.com", domain.ProfileUpdate{Timezone: &timezone})
+	suite.EqualError(err, "invalid timezone")
+
+	displayName := strings.Repeat("k", 65)
+	_, err = suite.useCase.UpdateProfile(context.Background(), "profile@example.com", domain.ProfileUpdate{DisplayName: &displayName})
+	suite.EqualError(err, "display name must be at most 64 characters long")
+
+	timezone = "Africa/Addis_Ababa"
+	suite.mockRepo.On("GetUserByEmail", mock.Anything, "tz@example.com").Return(domain.User{ID: primitive.NewObjectID(), Email: "tz@example.com"})
+	suite.mockRepo.On("UpdateProfile", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	updated, err := suite.useCase.UpdateProfile(context.Background(), "tz@example.com", domain.ProfileUpdate{Timezone: &timezone})
+	suite.NoError(err)
+	suite.Equal("Africa/Addis_Ababa", updated.Timezone)
+}
+
+func (suite *UserTestSuite) TestChangePassword() {
+	hashedPassword, err := suite.hasher.Hash("password123")
+	suite.NoError(err)
+	user := domain.User{ID: primitive.NewObjectID(), Email: "change@example.com", Password: hashedPassword}
+	suite.mockRepo.On("GetUserByEmail", mock.Anything, "change@example.com").Return(user)
+	suite.mockRepo.On("UpdatePassword", mock.Anything, user.ID.Hex(), mock.Anything, mock.Anything).Return(nil)
+
+	_, err = suite.useCase.ChangePassword(context.Background(), "change@example.com", "wrong-password", "correct horse battery")
+	suite.EqualError(err, "current password is incorrect")
+
+	_, err = suite.useCase.ChangePassword(context.Background(), "change@example.com", "password123", "short")
+	suite.EqualError(err, "password must be at least 8 characters long")
+	_, err = suite.useCase.ChangePassword(context.Background(), "change@example.com", "password123", "change@example.com")
+	suite.EqualError(err, "password must not be your email address")
+	suite.mockRepo.AssertNotCalled(suite.T(), "UpdatePassword", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	token, err := suite.useCase.ChangePassword(context.Background(), "change@example.com", "password123", "correct horse battery")
+	suite.NoError(err)
+	suite.Equal("token-for-change@example.com", token.Token, "a fresh token replaces the revoked session")
+	suite.mockRepo.AssertCalled(suite.T(), "UpdatePassword", mock.Anything, user.ID.Hex(), mock.MatchedBy(func(hash string) bool {
+		return suite.hasher.Compare(hash, "correct horse battery") == nil
+	}), mock.Anything)
+}
+
+func (suite *UserTestSuite) TestUserLogin_FailuresAreRecorded() {
+	hashedPassword, err := suite.hasher.Hash("password123")
+	suite.NoError(err)
+	suite.mockRepo.On("GetUserByEmail", mock.Anything, "guarded@example.com").Return(domain.User{Email: "guarded@example.com", Password: hashedPassword, Verified: true})
+	suite.mockRepo.On("GetUserByEmail", mock.Anything, "ghost@example.com").Return(domain.User{})
+
+	_, err = suite.useCase.Login(context.Background(), &domain.User{Email: "guarded@example.com", Password: "wrong-password"}, "203.0.113.7")
+	suite.EqualError(err, "invalid credentials")
+	_, err = suite.useCase.Login(context.Background(), &domain.User{Email: "ghost@example.com", Password: "password123"}, "203.0.113.7")
+	suite.EqualError(err, "invalid credentials")
+	suite.mockGuard.AssertCalled(suite.T(), "RecordFailure", mock.Anything, "guarded@example.com", "203.0.113.7", mock.Anything)
+	suite.mockGuard.AssertCalled(suite.T(), "RecordFailure", mock.Anything, "ghost@example.com", "203.0.113.7", mock.Anything)
+
+	_, err = suite.useCase.Login(context.Background(), &domain.User{Email: "guarded@example.com", Password: "password123"}, "203.0.113.7")
+	suite.NoError(err)
+	suite.mockGuard.AssertCalled(suite.T(), "RecordSuccess", mock.Anything, "guarded@example.com")
+}
+
+func (suite *UserTestSuite) TestUserLogin_OutdatedHashIsUpgraded() {
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	suite.NoError(err)
+	weakHasher := infrastructure.NewPasswordHasher(infrastructure.Argon2Params{Memory: 32, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32})
+	weakHash, err := weakHasher.Hash("password123")
+	suite.NoError(err)
+	currentHash, err := suite.hasher.Hash("password123")
+	suite.NoError(err)
+
+	users := map[string]domain.User{
+		"bcrypt@example.com":  {ID: primitive.NewObjectID(), Email: "bcrypt@example.com", Password: string(bcryptHash), Verified: true},
+		"weak@example.com":    {ID: primitive.NewObjectID(), Email: "weak@example.com", Password: weakHash, Verified: true},
+		"current@example.com": {ID: primitive.NewObjectID(), Email: "current@example.com", Password: currentHash, Verified: true},
+	}
+	for email, user := range users {
+		suite.mockRepo.On("GetUserByEmail", mock.Anything, email).Return(user)
+		suite.mockRepo.On("UpdatePasswordHash", mock.Anything, user.ID.Hex(), mock.Anything).Return(nil)
+		_, err := suite.useCase.Login(context.Background(), &domain.User{Email: email, Password: "password123"}, "203.0.113.7")
+		suite.NoError(err, email)
+	}
+
+	for _, email := range []string{"bcrypt@example.com", "weak@example.com"} {
+		suite.mockRepo.AssertCalled(suite.T(), "UpdatePasswordHash", mock.Anything, users[email].ID.Hex(), mock.MatchedBy(func(hash string) bool {
+			return !suite.hasher.NeedsRehash(hash) && suite.hasher.Compare(hash, "password123") == nil
+		}))
+	}
+	suite.mockRepo.AssertNotCalled(suite.T(), "UpdatePasswordHash", mock.Anything, users["current@example.com"].ID.Hex(), mock.Anything)
+}
+
+func (suite *UserTestSuite) TestUserLogin_LockedOutBeforePasswordCheck() {
+	guard := new(mocks.LoginGuard)
+	guard.On("Check", mock.Anything, "locked@example.com", "203.0.113.7", mock.Anything).Return(&domain.RetryError{Message: "too many failed login attempts, try again later", RetryAfter: time.Minute})
+	repo := new(mocks.UserRepository)
+	useCase := use_cases.NewUserUseCase(repo, suite.mockVerification, guard, suite.mockTwoFactor, suite.mockTokens, suite.hasher, infrastructure.NewPasswordPolicy(infrastructure.DefaultPasswordRules()), suite.mockEvents, false, discardLogger)
+
+	_, err := useCase.Login(context.Background(), &domain.User{Email: "locked@example.com", Password: "password123"}, "203.0.113.7")
+	var retryErr *domain.RetryError
+	suite.ErrorAs(err, &retryErr)
+	suite.Equal(time.Minute, retryErr.RetryAfter)
+	repo.AssertNotCalled(suite.T(), "GetUserByEmail", mock.Anything, mock.Anything)
 }
 
 // func (suite *UserTestSuite) TestUserLogin_Positive() {
@@ -61,7 +314,7 @@ func (suite *UserTestSuite) TestUserRegister_UserAlreadyExists() {
 //     foundUser := domain.User{Email: "test@example.com", Password: "jjfvnfnfvjbiehei uwlxu"}
 
 //     // Mock the repository call to GetUserByEmail
-//     suite.mockRepo.On("GetUserByEmail", "test@example.com").Return(foundUser)
+//     suite.mockRepo.On("GetUserByEmail", mock.Anything, "test@example.com").Return(foundUser)
 
 //     // Call the Login method
 //     token, err := suite.useCaase.Login(userInfo)
@@ -71,36 +324,36 @@ func (suite *UserTestSuite) TestUserRegister_UserAlreadyExists() {
 //     suite.NoError(err, "No error should occur when logging in")
 
 //     // Verify that the repository method was called with the expected arguments
-//     suite.mockRepo.AssertCalled(suite.T(), "GetUserByEmail", "test@example.com")
+//     suite.mockRepo.AssertCalled(suite.T(), "GetUserByEmail", mock.Anything, "test@example.com")
 // }
 
 func (suite *UserTestSuite) PromoteUser_Positive() {
 	user_id := "valid_user_id"
-	suite.mockRepo.On("PromoteUser", user_id).Return(nil)
-	err := suite.useCase.PromoteUser(user_id)
+	suite.mockRepo.On("PromoteUser", mock.Anything, user_id).Return(nil)
+	err := suite.useCase.PromoteUser(context.Background(), user_id)
 
 	suite.NoError(err, "no error in promoting the user")
-	suite.mockRepo.AssertCalled(suite.T(), "PromoteUser", user_id)
+	suite.mockRepo.AssertCalled(suite.T(), "PromoteUser", mock.Anything, user_id)
 }
 
 func (suite *UserTestSuite) PromoteUser_UserAlreadyanAdmin() {
 	user_id := "admin_user_id"
-	suite.mockRepo.On("PromoteUser", user_id).Return("user already an admin")
-	err := suite.useCase.PromoteUser(user_id)
+	suite.mockRepo.On("PromoteUser", mock.Anything, user_id).Return("user already an admin")
+	err := suite.useCase.PromoteUser(context.Background(), user_id)
 
 	suite.Error(err, "error in promoting the user")
 	suite.Equal(err, "user already an admin")
-	suite.mockRepo.AssertCalled(suite.T(), "PromoteUser", user_id)
+	suite.mockRepo.AssertCalled(suite.T(), "PromoteUser", mock.Anything, user_id)
 }
 
 func (suite *UserTestSuite) PromoteUser_NoUserWithSpecifiedID() {
 	user_id := "valid_user_id"
-	suite.mockRepo.On("PromoteUser", user_id).Return("no user with specified id")
-	err := suite.useCase.PromoteUser(user_id)
+	suite.mockRepo.On("PromoteUser", mock.Anything, user_id).Return("no user with specified id")
+	err := suite.useCase.PromoteUser(context.Background(), user_id)
 
 	suite.Error(err, "error in promoting the user")
 	suite.Equal(err, "no user with specified ID")
-	suite.mockRepo.AssertCalled(suite.T(), "PromoteUser", user_id)
+	suite.mockRepo.AssertCalled(suite.T(), "PromoteUser", mock.Anything, user_id)
 }
 
 
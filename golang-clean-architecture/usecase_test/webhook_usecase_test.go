@@ -0,0 +1,132 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"golang-clean-architecture/domain"
+	"golang-clean-architecture/domain/mocks"
+	"golang-clean-architecture/use_cases"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type WebhookTestSuite struct {
+	suite.Suite
+	mockRepo   *mocks.WebhookRepository
+	mockSender *mocks.WebhookSender
+	useCase    domain.WebhookUseCase
+}
+
+func (suite *WebhookTestSuite) SetupTest() {
+	suite.mockRepo = new(mocks.WebhookRepository)
+	suite.mockSender = new(mocks.WebhookSender)
+	suite.useCase = use_cases.NewWebhookUseCase(suite.mockRepo, suite.mockSender, discardLogger)
+}
+
+func (suite *WebhookTestSuite) TestCreateSubscription_GeneratesSecret() {
+	subscription := &domain.WebhookSubscription{URL: "https://hooks.example.com/tasks", EventTypes: []string{"task.created"}}
+	suite.mockRepo.On("CreateSubscription", mock.Anything, subscription).Return(nil)
+
+	err := suite.useCase.CreateSubscription(context.Background(), subscription)
+	suite.NoError(err)
+	suite.Len(subscription.Secret, 64)
+}
+
+func (suite *WebhookTestSuite) TestCreateSubscription_Validation() {
+	err := suite.useCase.CreateSubscription(context.Background(), &domain.WebhookSubscription{URL: "ftp://example.com", EventTypes: []string{"task.created"}})
+	suite.EqualError(err, "webhook url must be an absolute http(s) url")
+
+	err = suite.useCase.CreateSubscription(context.Background(), &domain.WebhookSubscription{URL: "https://example.com", EventTypes: []string{"task.archived"}})
+	suite.EqualError(err, "unsupported event type task.archived")
+	suite.mockRepo.AssertNotCalled(suite.T(), "CreateSubscription", mock.Anything, mock.Anything)
+}
+
+func (suite *WebhookTestSuite) TestPublish_QueuesDeliveryPerSubscription() {
+	subscriptions := []*domain.WebhookSubscription{
+		{ID: primitive.NewObjectID(), URL: "https://a.example.com"},
+		{ID: primitive.NewObjectID(), URL: "https://b.example.com"},
+	}
+	suite.mockRepo.On("GetSubscriptionsForEvent", mock.Anything, "task.deleted").Return(subscriptions, nil)
+	suite.mockRepo.On("CreateDelivery", mock.Anything, mock.Anything).Return(nil)
+
+	suite.useCase.Publish(context.Background(), domain.Event{ID: "evt1", Type: "task.deleted", Data: map[string]string{"id": "12345"}})
+
+	suite.mockRepo.AssertNumberOfCalls(suite.T(), "CreateDelivery", 2)
+	suite.mockRepo.AssertCalled(suite.T(), "CreateDelivery", mock.Anything, mock.MatchedBy(func(delivery *domain.WebhookDelivery) bool {
+		var payload map[string]interface{}
+		json.Unmarshal([]byte(delivery.Payload), &payload)
+		return delivery.SubscriptionID == subscriptions[1].ID.Hex() && delivery.Status == "pending" && payload["type"] == "task.deleted"
+	}))
+}
+
+func (suite *WebhookTestSuite) TestDeliverPending_Success() {
+	subscription := domain.WebhookSubscription{ID: primitive.NewObjectID(), URL: "https://a.example.com", Secret: "s3cret"}
+	delivery := &domain.WebhookDelivery{ID: primitive.NewObjectID(), SubscriptionID: subscription.ID.Hex(), Status: "pending"}
+	now := time.Now()
+	suite.mockRepo.On("ClaimPendingDelivery", mock.Anything, now, time.Minute).Return(delivery, nil).Once()
+	suite.mockRepo.On("ClaimPendingDelivery", mock.Anything, now, time.Minute).Return(nil, nil)
+	suite.mockRepo.On("GetSubscription", mock.Anything, subscription.ID.Hex()).Return(subscription, nil)
+	suite.mockSender.On("Send", &subscription, delivery).Return(204, nil)
+	suite.mockRepo.On("RecordAttempt", mock.Anything, delivery.ID.Hex(), mock.Anything, "succeeded", mock.Anything).Return(nil)
+
+	err := suite.useCase.DeliverPending(context.Background(), now)
+	suite.NoError(err)
+	suite.mockRepo.AssertCalled(suite.T(), "RecordAttempt", mock.Anything, delivery.ID.Hex(), mock.MatchedBy(func(attempt domain.WebhookAttempt) bool {
+		return attempt.StatusCode == 204 && attempt.Error == ""
+	}), "succeeded", mock.Anything)
+}
+
+func (suite *WebhookTestSuite) TestDeliverPending_RetriesWithBackoff() {
+	subscription := domain.WebhookSubscription{ID: primitive.NewObjectID(), URL: "https://a.example.com"}
+	delivery := &domain.WebhookDelivery{
+		ID:             primitive.NewObjectID(),
+		SubscriptionID: subscription.ID.Hex(),
+		Status:         "pending",
+		Attempts:       make([]domain.WebhookAttempt, 2),
+	}
+	now := time.Now()
+	suite.mockRepo.On("ClaimPendingDelivery", mock.Anything, now, time.Minute).Return(delivery, nil).Once()
+	suite.mockRepo.On("ClaimPendingDelivery", mock.Anything, now, time.Minute).Return(nil, nil)
+	suite.mockRepo.On("GetSubscription", mock.Anything, subscription.ID.Hex()).Return(subscription, nil)
+	suite.mockSender.On("Send", &subscription, delivery).Return(503, nil)
+	suite.mockRepo.On("RecordAttempt", mock.Anything, delivery.ID.Hex(), mock.Anything, "pending", mock.Anything).Return(nil)
+
+	err := suite.useCase.DeliverPending(context.Background(), now)
+	suite.NoError(err)
+	// third failed attempt: 30s doubled twice
+	suite.mockRepo.AssertCalled(suite.T(), "RecordAttempt", mock.Anything, delivery.ID.Hex(), mock.Anything, "pending", mock.MatchedBy(func(next time.Time) bool {
+		wait := time.Until(next)
+		return wait > 110*time.Second && wait <= 120*time.Second
+	}))
+}
+
+func (suite *WebhookTestSuite) TestDeliverPending_GivesUpAfterMaxAttempts() {
+	subscription := domain.WebhookSubscription{ID: primitive.NewObjectID(), URL: "https://a.example.com"}
+	delivery := &domain.WebhookDelivery{
+		ID:             primitive.NewObjectID(),
+		SubscriptionID: subscription.ID.Hex(),
+		Status:         "pending",
+		Attempts:       make([]domain.WebhookAttempt, 7),
+	}
+	now := time.Now()
+	suite.mockRepo.On("ClaimPendingDelivery", mock.Anything, now, time.Minute).Return(delivery, nil).Once()
+	suite.mockRepo.On("ClaimPendingDelivery", mock.Anything, now, time.Minute).Return(nil, nil)
+	suite.mockRepo.On("GetSubscription", mock.Anything, subscription.ID.Hex()).Return(subscription, nil)
+	suite.mockSender.On("Send", &subscription, delivery).Return(0, errors.New("error while sending webhook"))
+	suite.mockRepo.On("RecordAttempt", mock.Anything, delivery.ID.Hex(), mock.Anything, "failed", mock.Anything).Return(nil)
+
+	err := suite.useCase.DeliverPending(context.Background(), now)
+	suite.NoError(err)
+	suite.mockRepo.AssertCalled(suite.T(), "RecordAttempt", mock.Anything, delivery.ID.Hex(), mock.MatchedBy(func(attempt domain.WebhookAttempt) bool {
+		return attempt.Error == "error while sending webhook"
+	}), "failed", mock.Anything)
+}
+
+func TestWebhookTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookTestSuite))
+}